@@ -4,6 +4,7 @@ import (
 	"flag"
 	"log"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/caarlos0/env/v11"
@@ -17,7 +18,9 @@ type Config struct {
 	Env              string `env:"ENV" envDefault:"dev"`
 	Version          string `env:"VERSION" envDefault:"v0.1.0"`
 	BuildTime        string `env:"BUILD_TIME" envDefault:"unknown"`
+	InstanceID       string `env:"INSTANCE_ID"`
 	Secret           string `env:"SECRET,required"`
+	SecretsKey       string `env:"SECRETS_KEY"` // encrypts health check credentials, defaults to SECRET with a warning
 	APIHost          string `env:"API_HOST" envDefault:"localhost"`
 	FrontendURL      string `env:"FRONTEND_URL" envDefault:"localhost"`
 	REST_APIPort     int    `env:"REST_API_PORT" envDefault:"8080"`
@@ -40,6 +43,18 @@ type Config struct {
 	SmtpUser         string `env:"SMTP_USER"`
 	SmtpPass         string `env:"SMTP_PASS"`
 	EmailFrom        string `env:"EMAIL_FROM" envDefault:"alerts@alerting.platform"`
+
+	SchedulerReconcileInterval int `env:"SCHEDULER_RECONCILE_INTERVAL" envDefault:"300"` // in seconds
+	SchedulerPartitions        int `env:"SCHEDULER_PARTITIONS" envDefault:"64"`
+	SchedulerLeaseTTL          int `env:"SCHEDULER_LEASE_TTL" envDefault:"15"` // in seconds
+	SchedulerJitter            int `env:"SCHEDULER_JITTER" envDefault:"0"`     // random delay of ticks in percent of the interval, at most 50
+
+	CheckLocations  []string `env:"CHECK_LOCATIONS" envSeparator:"," envDefault:"default"` // locations with workers, services pick among them
+	WorkerLocation  string   `env:"WORKER_LOCATION" envDefault:"default"`
+	EgressAllowlist []string `env:"EGRESS_ALLOWLIST" envSeparator:","` // CIDRs checks may reach despite being private
+
+	WorkerConcurrency   int `env:"WORKER_CONCURRENCY" envDefault:"10"`    // checks a worker runs at the same time
+	WorkerHostRateLimit int `env:"WORKER_HOST_RATE_LIMIT" envDefault:"5"` // checks per second to one host, 0 disables
 }
 
 var (
@@ -83,6 +98,48 @@ func GetConfig() *Config {
 	return cfg
 }
 
+// GetInstanceID identifies this replica among others of the same service.
+// Falls back to the hostname, which is the pod name on Kubernetes.
+func GetInstanceID() string {
+	if id := GetConfig().InstanceID; id != "" {
+		return id
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Fatal("Failed to determine instance ID: ", err)
+	}
+
+	return hostname
+}
+
+// CheckSecretsKey warns when health check credentials are encrypted with SECRET,
+// which makes them unreadable once SECRET is rotated
+func CheckSecretsKey() {
+	if GetConfig().SecretsKey == "" {
+		log.Println("[WARNING] SECRETS_KEY is not set, health check credentials are encrypted with SECRET and become unreadable when it is rotated")
+	}
+}
+
+func GetSecretsKey() []byte {
+	cfg := GetConfig()
+	if cfg.SecretsKey != "" {
+		return []byte(cfg.SecretsKey)
+	}
+	return []byte(cfg.Secret)
+}
+
+// GetCheckLocations lists the locations workers run in, ignoring blank entries
+func GetCheckLocations() []string {
+	var locations []string
+	for _, location := range GetConfig().CheckLocations {
+		if location = strings.TrimSpace(location); location != "" {
+			locations = append(locations, location)
+		}
+	}
+	return locations
+}
+
 func Intro(name string) {
 	config := GetConfig()
 	log.Printf("Starting Alerting Platform %s - Version: %s, Build Time: %s, Environment: %s", name, config.Version, config.BuildTime, config.Env)
@@ -0,0 +1,38 @@
+package crontab
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	// The images ship without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// Schedules use the five standard fields or descriptors like @daily
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Parse returns the schedule of the expression in the timezone, UTC if empty
+func Parse(expression string, timezone string) (cron.Schedule, error) {
+	location := time.UTC
+	if timezone != "" {
+		var err error
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+	}
+
+	schedule, err := parser.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expression, err)
+	}
+
+	// Times are computed in the location, so 9:00 stays 9:00 across DST changes
+	if spec, ok := schedule.(*cron.SpecSchedule); ok {
+		spec.Location = location
+	}
+
+	return schedule, nil
+}
@@ -24,10 +24,18 @@ type IncidentLog struct {
 	Oncaller   string    `firestore:"oncaller,omitempty"`
 	Timestamp  time.Time `firestore:"timestamp"`
 	Type       string    `firestore:"type"`
+	Kind       string    `firestore:"kind,omitempty"` // missing for DOWN incidents logged before degraded ones existed
 }
 
 type MetricLog struct {
-	ServiceID int64     `firestore:"monitored_service_id"`
-	Timestamp time.Time `firestore:"timestamp"`
-	Type      string    `firestore:"type"`
+	ServiceID      int64     `firestore:"monitored_service_id"`
+	Timestamp      time.Time `firestore:"timestamp"`
+	Type           string    `firestore:"type"`
+	ResponseTimeMs float64   `firestore:"response_time_ms,omitempty"` // missing in logs written before it was recorded
+	StatusCode     int       `firestore:"status_code,omitempty"`
+	ErrorType      string    `firestore:"error_type,omitempty"`
+	Error          string    `firestore:"error,omitempty"`
+	WorkerID       string    `firestore:"worker_id,omitempty"`
+	Attempts       int       `firestore:"attempts,omitempty"`  // missing in logs written before retries
+	GapSince       time.Time `firestore:"gap_since,omitempty"` // NO_DATA logs only, the timestamp ends the gap
 }
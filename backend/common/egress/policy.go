@@ -0,0 +1,165 @@
+package egress
+
+import (
+	"alerting-platform/common/config"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+var ErrForbiddenTarget = errors.New("target address is not allowed")
+
+// deniedRanges are not covered by the net.IP helpers used in allowed
+var deniedRanges = mustParseCIDRs([]string{
+	"0.0.0.0/8",     // "this network"
+	"100.64.0.0/10", // carrier-grade NAT, also used for cluster internal addresses
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved
+	"64:ff9b::/96",  // NAT64, reaches IPv4 addresses through a gateway
+})
+
+// Policy decides which addresses health checks may connect to. Private, loopback
+// and link-local addresses are denied unless they are in the allowlist.
+type Policy struct {
+	allowlist []*net.IPNet
+}
+
+var (
+	policy *Policy
+	once   sync.Once
+)
+
+func NewPolicy(allowlist []string) (*Policy, error) {
+	policy := &Policy{}
+
+	for _, cidr := range allowlist {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress allowlist entry %q: %w", cidr, err)
+		}
+		policy.allowlist = append(policy.allowlist, network)
+	}
+
+	return policy, nil
+}
+
+// GetPolicy returns the policy configured through EGRESS_ALLOWLIST
+func GetPolicy() *Policy {
+	once.Do(func() {
+		var err error
+		policy, err = NewPolicy(config.GetConfig().EgressAllowlist)
+		if err != nil {
+			log.Fatal("Failed to load egress policy: ", err)
+		}
+	})
+
+	return policy
+}
+
+func (p *Policy) CheckIP(ip net.IP) error {
+	for _, network := range p.allowlist {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+
+	if !allowed(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenTarget, ip)
+	}
+
+	return nil
+}
+
+func allowed(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+
+	for _, network := range deniedRanges {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckHost resolves the host and requires every address to be allowed, as the
+// dialer may pick any of them. Lookup errors are returned as they are, so callers
+// can tell an unknown host from a forbidden one.
+func (p *Policy) CheckHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		return p.CheckIP(ip)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if err := p.CheckIP(addr.IP); err != nil {
+			return fmt.Errorf("%s resolves to a forbidden address: %w", host, err)
+		}
+	}
+
+	return nil
+}
+
+// Control is meant for net.Dialer. It runs after the name was resolved, with the
+// address about to be connected to, so a name rebound to a private address
+// between validation and the check is still refused.
+func (p *Policy) Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s is not an IP address", ErrForbiddenTarget, host)
+	}
+
+	return p.CheckIP(ip)
+}
+
+// Host accepts both URLs like tcp://db.example.com and bare host names
+func Host(target string) string {
+	if u, err := url.Parse(target); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return target
+}
+
+// AddressHost accepts host:port as well as a bare host
+func AddressHost(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+func mustParseCIDRs(cidrs []string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
@@ -2,6 +2,7 @@ package live
 
 import (
 	"alerting-platform/common/config"
+	"expvar"
 	"log"
 	"net/http"
 	"strconv"
@@ -14,6 +15,7 @@ func StartLiveServer(wg *sync.WaitGroup) {
 	cfg := config.GetConfig()
 
 	mux.HandleFunc("/live", LiveHandler)
+	mux.Handle("/debug/vars", expvar.Handler())
 
 	server := &http.Server{
 		Addr:         ":" + strconv.Itoa(cfg.LivePort),
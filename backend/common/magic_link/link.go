@@ -4,15 +4,26 @@ import (
 	"fmt"
 )
 
-const ResolveEndpointPath = "/api/v1/incidents/resolve"
+const (
+	AcknowledgeEndpointPath = "/api/v1/incidents/acknowledge"
+	ResolveEndpointPath     = "/api/v1/incidents/resolve"
+)
+
+func GenerateAcknowledgeLink(incidentID string, serviceID uint64, email string, secretKey []byte, apiHost string, apiPort int) (string, error) {
+	return generateLink(ActionAcknowledge, AcknowledgeEndpointPath, incidentID, serviceID, email, secretKey, apiHost, apiPort)
+}
 
 func GenerateResolveLink(incidentID string, serviceID uint64, email string, secretKey []byte, apiHost string, apiPort int) (string, error) {
-	token, err := GenerateToken(incidentID, serviceID, email, secretKey)
+	return generateLink(ActionResolve, ResolveEndpointPath, incidentID, serviceID, email, secretKey, apiHost, apiPort)
+}
+
+func generateLink(action string, endpointPath string, incidentID string, serviceID uint64, email string, secretKey []byte, apiHost string, apiPort int) (string, error) {
+	token, err := GenerateToken(action, incidentID, serviceID, email, secretKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token for link: %w", err)
 	}
 
-	link := fmt.Sprintf("%s:%d%s/%s", apiHost, apiPort, ResolveEndpointPath, token)
+	link := fmt.Sprintf("%s:%d%s/%s", apiHost, apiPort, endpointPath, token)
 
 	return link, nil
 }
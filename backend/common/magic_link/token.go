@@ -7,15 +7,22 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	ActionAcknowledge = "acknowledge" // stops the escalation, the incident stays open
+	ActionResolve     = "resolve"     // closes the incident
+)
+
 type ActionClaims struct {
+	Action     string `json:"act,omitempty"` // tokens issued before actions existed have none and resolve
 	IncidentID string `json:"inc_id"`
 	ServiceID  uint64 `json:"svc_id"`
 	OnCaller   string `json:"email"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(incidentID string, serviceID uint64, email string, secretKey []byte) (string, error) {
+func GenerateToken(action string, incidentID string, serviceID uint64, email string, secretKey []byte) (string, error) {
 	claims := ActionClaims{
+		Action:     action,
 		IncidentID: incidentID,
 		ServiceID:  serviceID,
 		OnCaller:   email,
@@ -49,3 +56,11 @@ func ParseToken(tokenString string, secretKey []byte) (*ActionClaims, error) {
 
 	return claims, nil
 }
+
+// Allows reports whether the token may be used for the action
+func (claims *ActionClaims) Allows(action string) bool {
+	if claims.Action == "" {
+		return action == ActionResolve
+	}
+	return claims.Action == action
+}
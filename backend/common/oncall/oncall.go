@@ -0,0 +1,254 @@
+package oncall
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	// The images ship without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// LocalTimeLayout is the wall clock time of layer starts and ends in the schedule's timezone
+const LocalTimeLayout = "2006-01-02T15:04"
+
+// maxMergedShifts bounds the walk over consecutive shifts of the same user
+const maxMergedShifts = 1000
+
+// Schedule decides who is on call at any time. Later layers take precedence over
+// earlier ones while they are active, and overrides take precedence over all layers.
+type Schedule struct {
+	Timezone  string     `json:"timezone,omitempty"` // handoffs follow the wall clock of this zone, UTC if empty
+	Layers    []Layer    `json:"layers"`
+	Overrides []Override `json:"overrides,omitempty"`
+}
+
+// Layer rotates through its users, handing off every RotationDays days at the time of day of Start
+type Layer struct {
+	Users        []string `json:"users"`
+	Start        string   `json:"start"`         // first handoff, see LocalTimeLayout
+	End          string   `json:"end,omitempty"` // the layer never ends if empty
+	RotationDays int      `json:"rotation_days"` // 7 for weekly rotations
+}
+
+// Override puts somebody else on call for a while, like a holiday swap
+type Override struct {
+	User  string    `json:"user"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Shift is a period with the same user on call, nobody if the user is empty
+type Shift struct {
+	User  string
+	Start time.Time // zero if the shift started before any layer or override
+	End   time.Time // zero if the shift never ends
+}
+
+// Validate reports unknown timezones, malformed times and empty rotations
+func (s Schedule) Validate() error {
+	location, err := s.location()
+	if err != nil {
+		return err
+	}
+
+	for i, layer := range s.Layers {
+		if len(layer.Users) == 0 {
+			return fmt.Errorf("layer %d has no users", i+1)
+		}
+
+		if layer.RotationDays < 1 {
+			return fmt.Errorf("layer %d must rotate every day or less often", i+1)
+		}
+
+		start, end, err := layer.bounds(location)
+		if err != nil {
+			return fmt.Errorf("layer %d: %w", i+1, err)
+		}
+
+		if !end.IsZero() && !end.After(start) {
+			return fmt.Errorf("layer %d ends before it starts", i+1)
+		}
+	}
+
+	for i, override := range s.Overrides {
+		if override.User == "" {
+			return fmt.Errorf("override %d has no user", i+1)
+		}
+
+		if !override.End.After(override.Start) {
+			return fmt.Errorf("override %d ends before it starts", i+1)
+		}
+	}
+
+	return nil
+}
+
+// ShiftAt returns who is on call at the time, from the previous to the next change of user
+func (s Schedule) ShiftAt(t time.Time) Shift {
+	shift := s.segmentAt(t)
+
+	for range maxMergedShifts {
+		if shift.End.IsZero() {
+			break
+		}
+
+		next := s.segmentAt(shift.End)
+		if next.User != shift.User {
+			break
+		}
+		shift.End = next.End
+	}
+
+	for range maxMergedShifts {
+		if shift.Start.IsZero() {
+			break
+		}
+
+		previous := s.segmentAt(shift.Start.Add(-time.Nanosecond))
+		if previous.User != shift.User {
+			break
+		}
+		shift.Start = previous.Start
+	}
+
+	return shift
+}
+
+// NextShift returns the shift after the given one, none if it never ends
+func (s Schedule) NextShift(shift Shift) (Shift, bool) {
+	if shift.End.IsZero() {
+		return Shift{}, false
+	}
+	return s.ShiftAt(shift.End), true
+}
+
+// segmentAt returns who is on call at the time, between the closest layer or override
+// boundaries around it. Consecutive segments may have the same user.
+func (s Schedule) segmentAt(t time.Time) Shift {
+	location, err := s.location()
+	if err != nil {
+		location = time.UTC
+	}
+
+	var shift Shift
+	addBoundary := func(boundary time.Time) {
+		if boundary.IsZero() {
+			return
+		}
+
+		if boundary.After(t) {
+			if shift.End.IsZero() || boundary.Before(shift.End) {
+				shift.End = boundary
+			}
+		} else if boundary.After(shift.Start) {
+			shift.Start = boundary
+		}
+	}
+
+	found := false
+	for i := len(s.Overrides) - 1; i >= 0; i-- {
+		override := s.Overrides[i]
+		addBoundary(override.Start)
+		addBoundary(override.End)
+
+		if !found && !t.Before(override.Start) && t.Before(override.End) {
+			shift.User = override.User
+			found = true
+		}
+	}
+
+	for i := len(s.Layers) - 1; i >= 0; i-- {
+		layer := s.Layers[i]
+		if len(layer.Users) == 0 || layer.RotationDays < 1 {
+			continue
+		}
+
+		start, end, err := layer.bounds(location)
+		if err != nil {
+			continue
+		}
+
+		addBoundary(start)
+		addBoundary(end)
+
+		if t.Before(start) || (!end.IsZero() && !t.Before(end)) {
+			continue
+		}
+
+		if found {
+			continue
+		}
+
+		// Handoffs of layers below an override or another layer, or of layers
+		// without anybody to hand off to, never change the user on call
+		rotation := layer.rotation(start, t)
+		if layer.rotates() {
+			addBoundary(layer.handoff(start, rotation))
+			addBoundary(layer.handoff(start, rotation+1))
+		}
+
+		shift.User = layer.Users[rotation%len(layer.Users)]
+		found = true
+	}
+
+	return shift
+}
+
+func (s Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+	return location, nil
+}
+
+func (l Layer) bounds(location *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(LocalTimeLayout, l.Start, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid start " + l.Start)
+	}
+
+	var end time.Time
+	if l.End != "" {
+		end, err = time.ParseInLocation(LocalTimeLayout, l.End, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid end " + l.End)
+		}
+	}
+
+	return start, end, nil
+}
+
+// rotates reports whether the layer has more than one user to hand off between
+func (l Layer) rotates() bool {
+	for _, user := range l.Users {
+		if user != l.Users[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// handoff returns the start of the rotation, on the wall clock of the start so
+// handoffs stay at the same local time across DST changes
+func (l Layer) handoff(start time.Time, rotation int) time.Time {
+	return time.Date(start.Year(), start.Month(), start.Day()+rotation*l.RotationDays, start.Hour(), start.Minute(), 0, 0, start.Location())
+}
+
+// rotation returns the index of the rotation at the time, which must not be before the start
+func (l Layer) rotation(start time.Time, t time.Time) int {
+	local := t.In(start.Location())
+	startDate := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	rotation := int(date.Sub(startDate)/(24*time.Hour)) / l.RotationDays
+	if l.handoff(start, rotation).After(t) {
+		rotation--
+	}
+	return rotation
+}
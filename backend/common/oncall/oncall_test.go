@@ -0,0 +1,165 @@
+package oncall
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func utc(value string) time.Time {
+	t, err := time.Parse(LocalTimeLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestShiftAtAcrossDST(t *testing.T) {
+	// Warsaw moves from CET (UTC+1) to CEST (UTC+2) on 2026-03-29 at 02:00
+	schedule := Schedule{
+		Timezone: "Europe/Warsaw",
+		Layers: []Layer{
+			{Users: []string{"a", "b"}, Start: "2026-03-27T09:00", RotationDays: 1},
+		},
+	}
+	assert.NoError(t, schedule.Validate())
+
+	t.Run("Handoff stays at the local time", func(t *testing.T) {
+		shift := schedule.ShiftAt(utc("2026-03-28T12:00"))
+
+		assert.Equal(t, "b", shift.User)
+		assert.Equal(t, utc("2026-03-28T08:00"), shift.Start.UTC())
+		assert.Equal(t, utc("2026-03-29T07:00"), shift.End.UTC())
+		assert.Equal(t, 23*time.Hour, shift.End.Sub(shift.Start))
+	})
+
+	t.Run("Shift after the change", func(t *testing.T) {
+		shift := schedule.ShiftAt(utc("2026-03-29T12:00"))
+
+		assert.Equal(t, "a", shift.User)
+		assert.Equal(t, utc("2026-03-29T07:00"), shift.Start.UTC())
+		assert.Equal(t, utc("2026-03-30T07:00"), shift.End.UTC())
+	})
+}
+
+func TestShiftAtLayers(t *testing.T) {
+	// A weekly rotation with a weekend cover layered on top
+	schedule := Schedule{
+		Layers: []Layer{
+			{Users: []string{"a", "b"}, Start: "2026-01-05T09:00", RotationDays: 7},
+			{Users: []string{"c"}, Start: "2026-01-10T00:00", End: "2026-01-12T00:00", RotationDays: 1},
+		},
+	}
+	assert.NoError(t, schedule.Validate())
+
+	tests := []struct {
+		name     string
+		at       string
+		expected Shift
+	}{
+		{
+			name:     "Before any layer",
+			at:       "2026-01-01T00:00",
+			expected: Shift{End: utc("2026-01-05T09:00")},
+		},
+		{
+			name:     "First layer until the cover starts",
+			at:       "2026-01-09T12:00",
+			expected: Shift{User: "a", Start: utc("2026-01-05T09:00"), End: utc("2026-01-10T00:00")},
+		},
+		{
+			name:     "Later layer takes precedence",
+			at:       "2026-01-11T12:00",
+			expected: Shift{User: "c", Start: utc("2026-01-10T00:00"), End: utc("2026-01-12T00:00")},
+		},
+		{
+			name:     "First layer again after the end",
+			at:       "2026-01-12T03:00",
+			expected: Shift{User: "a", Start: utc("2026-01-12T00:00"), End: utc("2026-01-12T09:00")},
+		},
+		{
+			name:     "Next rotation",
+			at:       "2026-01-12T09:00",
+			expected: Shift{User: "b", Start: utc("2026-01-12T09:00"), End: utc("2026-01-19T09:00")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, schedule.ShiftAt(utc(tt.at)))
+		})
+	}
+}
+
+func TestShiftAtOverrides(t *testing.T) {
+	layers := []Layer{{Users: []string{"a"}, Start: "2026-01-01T00:00", RotationDays: 1}}
+	long := Override{User: "x", Start: utc("2026-01-10T00:00"), End: utc("2026-01-20T00:00")}
+	short := Override{User: "y", Start: utc("2026-01-15T00:00"), End: utc("2026-01-17T00:00")}
+
+	t.Run("Later override takes precedence", func(t *testing.T) {
+		schedule := Schedule{Layers: layers, Overrides: []Override{long, short}}
+
+		assert.Equal(t, Shift{User: "x", Start: utc("2026-01-10T00:00"), End: utc("2026-01-15T00:00")}, schedule.ShiftAt(utc("2026-01-12T00:00")))
+		assert.Equal(t, Shift{User: "y", Start: utc("2026-01-15T00:00"), End: utc("2026-01-17T00:00")}, schedule.ShiftAt(utc("2026-01-16T00:00")))
+		assert.Equal(t, Shift{User: "x", Start: utc("2026-01-17T00:00"), End: utc("2026-01-20T00:00")}, schedule.ShiftAt(utc("2026-01-18T00:00")))
+		assert.Equal(t, Shift{User: "a", Start: utc("2026-01-20T00:00")}, schedule.ShiftAt(utc("2026-01-21T00:00")))
+	})
+
+	t.Run("Earlier override is hidden", func(t *testing.T) {
+		schedule := Schedule{Layers: layers, Overrides: []Override{short, long}}
+
+		assert.Equal(t, Shift{User: "x", Start: utc("2026-01-10T00:00"), End: utc("2026-01-20T00:00")}, schedule.ShiftAt(utc("2026-01-16T00:00")))
+	})
+}
+
+func TestNextShift(t *testing.T) {
+	t.Run("Walks the rotation until the layer ends", func(t *testing.T) {
+		schedule := Schedule{
+			Layers: []Layer{
+				{Users: []string{"a", "b"}, Start: "2026-01-01T09:00", End: "2026-01-03T09:00", RotationDays: 1},
+			},
+		}
+
+		shift := schedule.ShiftAt(utc("2026-01-01T12:00"))
+		assert.Equal(t, Shift{User: "a", Start: utc("2026-01-01T09:00"), End: utc("2026-01-02T09:00")}, shift)
+
+		shift, ok := schedule.NextShift(shift)
+		assert.True(t, ok)
+		assert.Equal(t, Shift{User: "b", Start: utc("2026-01-02T09:00"), End: utc("2026-01-03T09:00")}, shift)
+
+		shift, ok = schedule.NextShift(shift)
+		assert.True(t, ok)
+		assert.Equal(t, Shift{Start: utc("2026-01-03T09:00")}, shift)
+
+		_, ok = schedule.NextShift(shift)
+		assert.False(t, ok)
+	})
+
+	t.Run("Single user never hands off", func(t *testing.T) {
+		schedule := Schedule{
+			Layers: []Layer{
+				{Users: []string{"a"}, Start: "2026-01-01T00:00", RotationDays: 1},
+			},
+		}
+
+		shift := schedule.ShiftAt(utc("2026-05-01T00:00"))
+		assert.Equal(t, Shift{User: "a", Start: utc("2026-01-01T00:00")}, shift)
+
+		_, ok := schedule.NextShift(shift)
+		assert.False(t, ok)
+	})
+
+	t.Run("Layer hidden by a single user layer", func(t *testing.T) {
+		schedule := Schedule{
+			Layers: []Layer{
+				{Users: []string{"a", "b"}, Start: "2026-01-01T00:00", RotationDays: 1},
+				{Users: []string{"c"}, Start: "2026-01-05T00:00", RotationDays: 7},
+			},
+		}
+
+		shift, ok := schedule.NextShift(schedule.ShiftAt(utc("2026-01-04T12:00")))
+		assert.True(t, ok)
+		assert.Equal(t, Shift{User: "c", Start: utc("2026-01-05T00:00")}, shift)
+	})
+}
@@ -7,10 +7,37 @@ const (
 	ServiceModifiedTopic            = "service-modified"
 	ServiceRemovedTopic             = "service-removed"
 	IncidentStartTopic              = "incident-start"
+	IncidentAcknowledgedTopic       = "incident-acknowledged"
+	IncidentRecoveredTopic          = "incident-recovered"
 	IncidentResolvedTopic           = "incident-resolved"
 	IncidentAcknowledgeTimeoutTopic = "incident-acknowledge-timeout"
 	IncidentUnresolvedTopic         = "incident-unresolved"
 	NotifyOncallerTopic             = "notify-oncaller"
+	NotifyRecoveryTopic             = "notify-recovery"
 	OncallerAcknowledgedTopic       = "oncaller-acknowledged"
+	OncallerResolvedTopic           = "oncaller-resolved"
 	ExecuteHealthCheckTopic         = "execute-health-check"
+	MonitoringGapTopic              = "monitoring-gap"
+)
+
+// DefaultLocation is where workers run unless WORKER_LOCATION says otherwise
+const DefaultLocation = "default"
+
+// ExecuteHealthCheckTopicFor returns the topic workers of a location listen on.
+// The default location keeps the original topic, so single location setups are unchanged.
+func ExecuteHealthCheckTopicFor(location string) string {
+	if location == "" || location == DefaultLocation {
+		return ExecuteHealthCheckTopic
+	}
+	return ExecuteHealthCheckTopic + "-" + location
+}
+
+// StatusNoData is the status of services whose checks did not run for a while
+const StatusNoData = "NO_DATA"
+
+const (
+	IncidentKindDown       = "DOWN"
+	IncidentKindDegraded   = "DEGRADED"    // up, but slower than the latency threshold
+	IncidentKindCertExpiry = "CERT_EXPIRY" // warning, the certificate expires soon
+	IncidentKindFlapping   = "FLAPPING"    // changing between up and down too often
 )
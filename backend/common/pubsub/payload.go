@@ -6,19 +6,180 @@ import (
 	"fmt"
 	"time"
 
+	"alerting-platform/common/oncall"
+
 	"cloud.google.com/go/pubsub"
 )
 
+const (
+	CheckTypeHTTP = "http"
+	CheckTypeTCP  = "tcp"
+	CheckTypeGRPC = "grpc"
+	CheckTypeDNS  = "dns"
+	// An ordered list of HTTP requests, see SyntheticStep
+	CheckTypeSynthetic = "synthetic"
+	// Pushed by the monitored job itself, the scheduler only notices missing pings
+	CheckTypeHeartbeat = "heartbeat"
+)
+
+// Record types of DNS checks
+const (
+	DNSRecordA     = "A"
+	DNSRecordAAAA  = "AAAA"
+	DNSRecordCNAME = "CNAME"
+	DNSRecordMX    = "MX"
+	DNSRecordTXT   = "TXT"
+	DNSRecordNS    = "NS"
+)
+
+const (
+	AuthTypeBasic  = "basic"
+	AuthTypeBearer = "bearer"
+)
+
 type MonitoringTask struct {
 	ServiceID uint64 `json:"service_id"`
 	URL       string `json:"url"`
+	CheckType string `json:"check_type,omitempty"` // empty means http, for tasks queued by older schedulers
+	Port      int    `json:"port,omitempty"`
+
+	GRPCServiceName string `json:"grpc_service_name,omitempty"`
+	GRPCUseTLS      bool   `json:"grpc_use_tls,omitempty"`
+	GRPCTimeout     int    `json:"grpc_timeout,omitempty"` // in seconds, worker default if empty
+
+	DNSRecordType string   `json:"dns_record_type,omitempty"` // A if empty
+	DNSResolvers  []string `json:"dns_resolvers,omitempty"`   // host:port, the system resolver if empty
+	DNSExpected   []string `json:"dns_expected,omitempty"`    // the whole answer, in any order, any answer if empty
+
+	Steps []SyntheticStep `json:"steps,omitempty"` // synthetic checks only
+
+	ExpectedStatusCodes []int  `json:"expected_status_codes,omitempty"` // any 2xx if empty
+	BodyContains        string `json:"body_contains,omitempty"`
+	BodyRegex           string `json:"body_regex,omitempty"`
+	JSONPath            string `json:"json_path,omitempty"`
+	JSONPathEquals      string `json:"json_path_equals,omitempty"`
+
+	Method              string            `json:"method,omitempty"`            // GET if empty
+	EncryptedHeaders    map[string]string `json:"encrypted_headers,omitempty"` // only the worker decrypts the values
+	RequestBody         string            `json:"request_body,omitempty"`
+	AuthType            string            `json:"auth_type,omitempty"`
+	AuthUsername        string            `json:"auth_username,omitempty"`
+	EncryptedAuthSecret string            `json:"encrypted_auth_secret,omitempty"` // only the worker decrypts it
+
+	Retries        int `json:"retries,omitempty"`         // extra attempts before the service is reported down
+	RetryBackoff   int `json:"retry_backoff,omitempty"`   // in milliseconds, doubled after every retry
+	AttemptTimeout int `json:"attempt_timeout,omitempty"` // in seconds, worker default if empty
+
+	Location string `json:"location,omitempty"`
+	Round    int64  `json:"round,omitempty"` // shared by the tasks of one tick across locations
+
+	// Workers drop tasks that waited longer than the interval, the next tick is already due
+	ScheduledAt         time.Time `json:"scheduled_at,omitempty"`
+	HealthCheckInterval int       `json:"health_check_interval,omitempty"` // in seconds
+}
+
+// SyntheticStep is one request of a synthetic check. Placeholders like {{token}}
+// in the URL, headers and body are replaced with variables extracted by earlier steps.
+type SyntheticStep struct {
+	Name    string            `json:"name"`
+	Method  string            `json:"method,omitempty"` // GET if empty
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+
+	ExpectedStatusCodes []int  `json:"expected_status_codes,omitempty"` // any 2xx if empty
+	BodyContains        string `json:"body_contains,omitempty"`
+	BodyRegex           string `json:"body_regex,omitempty"`
+	JSONPath            string `json:"json_path,omitempty"`
+	JSONPathEquals      string `json:"json_path_equals,omitempty"`
+
+	Extract []VariableExtraction `json:"extract,omitempty"`
+}
+
+// VariableExtraction takes a value from the JSON body, or a header when no path is set
+type VariableExtraction struct {
+	Name     string `json:"name"`
+	JSONPath string `json:"json_path,omitempty"`
+	Header   string `json:"header,omitempty"`
 }
 
 type PubSubPayloadData struct {
-	AllowedResponseTime int      `json:"allowed_response_time,omitempty"`
-	HealthCheckInterval int      `json:"health_check_interval,omitempty"`
-	AlertWindow         int      `json:"alert_window,omitempty"`
-	Oncallers           []string `json:"oncallers,omitempty"`
+	AllowedResponseTime int               `json:"allowed_response_time,omitempty"`
+	HealthCheckInterval int               `json:"health_check_interval,omitempty"`
+	AlertWindow         int               `json:"alert_window,omitempty"`
+	LatencyThreshold    int               `json:"latency_threshold,omitempty"` // in milliseconds
+	CertExpiryDays      int               `json:"cert_expiry_days,omitempty"`
+	Locations           []string          `json:"locations,omitempty"`
+	LocationQuorum      int               `json:"location_quorum,omitempty"` // majority of the locations if empty
+	Oncallers           []string          `json:"oncallers,omitempty"`
+	Escalation          []EscalationLevel `json:"escalation,omitempty"`        // replaces the oncallers if set
+	EscalationRepeat    int               `json:"escalation_repeat,omitempty"` // times the whole escalation is repeated
+	AutoResolve         bool              `json:"auto_resolve,omitempty"`      // resolve open incidents when the service recovers
+	RecoveryChecks      int               `json:"recovery_checks,omitempty"`   // consecutive healthy results before the service counts as recovered, 1 if empty
+	NotifyRecovery      bool              `json:"notify_recovery,omitempty"`   // tell the oncallers of open incidents when the service recovers
+	FlapWindow          int               `json:"flap_window,omitempty"`       // recent results checked for flapping, default if empty
+	FlapThreshold       int               `json:"flap_threshold,omitempty"`    // percentage of changes between them that counts as flapping, default if empty
+	IncidentKind        string            `json:"incident_kind,omitempty"`
+	Certificate         *CertificateInfo  `json:"certificate,omitempty"` // set for certificate expiry incidents
+	Result              *CheckResult      `json:"result,omitempty"`      // set on service-up and service-down
+	Gap                 *MonitoringGap    `json:"gap,omitempty"`         // set on monitoring-gap
+}
+
+// EscalationLevel is notified at once when an incident reaches it. Without an
+// acknowledgement within the timeout the incident moves to the next level.
+type EscalationLevel struct {
+	Targets     []string          `json:"targets"`
+	ScheduleIDs []uint            `json:"schedule_ids,omitempty"`
+	Schedules   []oncall.Schedule `json:"schedules,omitempty"` // filled from the IDs when sent, whoever is on call is notified too
+	Timeout     int               `json:"timeout"`             // in minutes
+}
+
+// MonitoringGap is a period in which no checks of the service were scheduled,
+// usually because no scheduler replica was running
+type MonitoringGap struct {
+	Since time.Time `json:"since"` // the last scheduled check
+	Until time.Time `json:"until"`
+}
+
+const (
+	ErrorTypeDNS               = "dns"
+	ErrorTypeConnectionRefused = "connection_refused"
+	ErrorTypeTLS               = "tls"
+	ErrorTypeTimeout           = "timeout"
+	ErrorTypeAssertion         = "assertion"
+	ErrorTypeMissedHeartbeat   = "missed_heartbeat"
+	ErrorTypeForbiddenTarget   = "forbidden_target" // refused by the worker egress policy
+	ErrorTypeOther             = "other"
+)
+
+type CheckResult struct {
+	ResponseTimeMs float64 `json:"response_time_ms"`
+	StatusCode     int     `json:"status_code,omitempty"` // HTTP checks only
+	ErrorType      string  `json:"error_type,omitempty"`
+	Error          string  `json:"error,omitempty"`
+	WorkerID       string  `json:"worker_id,omitempty"`
+	Attempts       int     `json:"attempts,omitempty"` // including the first one
+	Location       string  `json:"location,omitempty"`
+	Round          int64   `json:"round,omitempty"` // copied from the task, empty for results not checked by workers
+
+	Certificate *CertificateInfo `json:"certificate,omitempty"` // HTTPS checks only
+
+	// Synthetic checks only, the steps after a failed one are not run
+	FailedStep string       `json:"failed_step,omitempty"`
+	Steps      []StepResult `json:"steps,omitempty"`
+}
+
+type StepResult struct {
+	Name           string  `json:"name"`
+	ResponseTimeMs float64 `json:"response_time_ms"`
+	StatusCode     int     `json:"status_code,omitempty"`
+}
+
+// CertificateInfo describes the certificate of the peer chain that expires first
+type CertificateInfo struct {
+	NotAfter time.Time `json:"not_after"`
+	Issuer   string    `json:"issuer"`
+	Subject  string    `json:"subject"`
 }
 
 type PubSubPayload struct {
@@ -72,6 +72,17 @@ type ServiceInfoForIncident struct {
 	AlertWindow         int64                  `protobuf:"varint,2,opt,name=alert_window,json=alertWindow,proto3" json:"alert_window,omitempty"`
 	AllowedResponseTime int64                  `protobuf:"varint,3,opt,name=allowed_response_time,json=allowedResponseTime,proto3" json:"allowed_response_time,omitempty"`
 	Oncallers           []string               `protobuf:"bytes,4,rep,name=oncallers,proto3" json:"oncallers,omitempty"`
+	LatencyThreshold    int64                  `protobuf:"varint,5,opt,name=latency_threshold,json=latencyThreshold,proto3" json:"latency_threshold,omitempty"`
+	CertExpiryDays      int64                  `protobuf:"varint,6,opt,name=cert_expiry_days,json=certExpiryDays,proto3" json:"cert_expiry_days,omitempty"`
+	Locations           []string               `protobuf:"bytes,7,rep,name=locations,proto3" json:"locations,omitempty"`
+	LocationQuorum      int64                  `protobuf:"varint,8,opt,name=location_quorum,json=locationQuorum,proto3" json:"location_quorum,omitempty"`
+	Escalation          []*EscalationLevel     `protobuf:"bytes,9,rep,name=escalation,proto3" json:"escalation,omitempty"`
+	EscalationRepeat    int64                  `protobuf:"varint,10,opt,name=escalation_repeat,json=escalationRepeat,proto3" json:"escalation_repeat,omitempty"`
+	AutoResolve         bool                   `protobuf:"varint,11,opt,name=auto_resolve,json=autoResolve,proto3" json:"auto_resolve,omitempty"`
+	RecoveryChecks      int64                  `protobuf:"varint,12,opt,name=recovery_checks,json=recoveryChecks,proto3" json:"recovery_checks,omitempty"`
+	NotifyRecovery      bool                   `protobuf:"varint,13,opt,name=notify_recovery,json=notifyRecovery,proto3" json:"notify_recovery,omitempty"`
+	FlapWindow          int64                  `protobuf:"varint,14,opt,name=flap_window,json=flapWindow,proto3" json:"flap_window,omitempty"`
+	FlapThreshold       int64                  `protobuf:"varint,15,opt,name=flap_threshold,json=flapThreshold,proto3" json:"flap_threshold,omitempty"`
 	unknownFields       protoimpl.UnknownFields
 	sizeCache           protoimpl.SizeCache
 }
@@ -134,30 +145,699 @@ func (x *ServiceInfoForIncident) GetOncallers() []string {
 	return nil
 }
 
+func (x *ServiceInfoForIncident) GetLatencyThreshold() int64 {
+	if x != nil {
+		return x.LatencyThreshold
+	}
+	return 0
+}
+
+func (x *ServiceInfoForIncident) GetCertExpiryDays() int64 {
+	if x != nil {
+		return x.CertExpiryDays
+	}
+	return 0
+}
+
+func (x *ServiceInfoForIncident) GetLocations() []string {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *ServiceInfoForIncident) GetLocationQuorum() int64 {
+	if x != nil {
+		return x.LocationQuorum
+	}
+	return 0
+}
+
+func (x *ServiceInfoForIncident) GetEscalation() []*EscalationLevel {
+	if x != nil {
+		return x.Escalation
+	}
+	return nil
+}
+
+func (x *ServiceInfoForIncident) GetEscalationRepeat() int64 {
+	if x != nil {
+		return x.EscalationRepeat
+	}
+	return 0
+}
+
+func (x *ServiceInfoForIncident) GetAutoResolve() bool {
+	if x != nil {
+		return x.AutoResolve
+	}
+	return false
+}
+
+func (x *ServiceInfoForIncident) GetRecoveryChecks() int64 {
+	if x != nil {
+		return x.RecoveryChecks
+	}
+	return 0
+}
+
+func (x *ServiceInfoForIncident) GetNotifyRecovery() bool {
+	if x != nil {
+		return x.NotifyRecovery
+	}
+	return false
+}
+
+func (x *ServiceInfoForIncident) GetFlapWindow() int64 {
+	if x != nil {
+		return x.FlapWindow
+	}
+	return 0
+}
+
+func (x *ServiceInfoForIncident) GetFlapThreshold() int64 {
+	if x != nil {
+		return x.FlapThreshold
+	}
+	return 0
+}
+
+type EscalationLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Targets       []string               `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+	Timeout       int64                  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Schedules     []*Schedule            `protobuf:"bytes,3,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EscalationLevel) Reset() {
+	*x = EscalationLevel{}
+	mi := &file_rpc_services_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EscalationLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EscalationLevel) ProtoMessage() {}
+
+func (x *EscalationLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_services_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EscalationLevel.ProtoReflect.Descriptor instead.
+func (*EscalationLevel) Descriptor() ([]byte, []int) {
+	return file_rpc_services_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EscalationLevel) GetTargets() []string {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *EscalationLevel) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *EscalationLevel) GetSchedules() []*Schedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+type Schedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timezone      string                 `protobuf:"bytes,1,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Layers        []*ScheduleLayer       `protobuf:"bytes,2,rep,name=layers,proto3" json:"layers,omitempty"`
+	Overrides     []*ScheduleOverride    `protobuf:"bytes,3,rep,name=overrides,proto3" json:"overrides,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Schedule) Reset() {
+	*x = Schedule{}
+	mi := &file_rpc_services_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schedule) ProtoMessage() {}
+
+func (x *Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_services_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schedule.ProtoReflect.Descriptor instead.
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return file_rpc_services_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Schedule) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Schedule) GetLayers() []*ScheduleLayer {
+	if x != nil {
+		return x.Layers
+	}
+	return nil
+}
+
+func (x *Schedule) GetOverrides() []*ScheduleOverride {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+type ScheduleLayer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []string               `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Start         string                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	RotationDays  int64                  `protobuf:"varint,4,opt,name=rotation_days,json=rotationDays,proto3" json:"rotation_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleLayer) Reset() {
+	*x = ScheduleLayer{}
+	mi := &file_rpc_services_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleLayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleLayer) ProtoMessage() {}
+
+func (x *ScheduleLayer) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_services_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleLayer.ProtoReflect.Descriptor instead.
+func (*ScheduleLayer) Descriptor() ([]byte, []int) {
+	return file_rpc_services_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ScheduleLayer) GetUsers() []string {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ScheduleLayer) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *ScheduleLayer) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *ScheduleLayer) GetRotationDays() int64 {
+	if x != nil {
+		return x.RotationDays
+	}
+	return 0
+}
+
+type ScheduleOverride struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Start         int64                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64                  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleOverride) Reset() {
+	*x = ScheduleOverride{}
+	mi := &file_rpc_services_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleOverride) ProtoMessage() {}
+
+func (x *ScheduleOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_services_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleOverride.ProtoReflect.Descriptor instead.
+func (*ScheduleOverride) Descriptor() ([]byte, []int) {
+	return file_rpc_services_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ScheduleOverride) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ScheduleOverride) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *ScheduleOverride) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type SchedulerConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     uint64                 `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchedulerConfigRequest) Reset() {
+	*x = SchedulerConfigRequest{}
+	mi := &file_rpc_services_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulerConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerConfigRequest) ProtoMessage() {}
+
+func (x *SchedulerConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_services_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerConfigRequest.ProtoReflect.Descriptor instead.
+func (*SchedulerConfigRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_services_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SchedulerConfigRequest) GetServiceId() uint64 {
+	if x != nil {
+		return x.ServiceId
+	}
+	return 0
+}
+
 type ServiceInfoForScheduler struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
-	ServiceId           uint64                 `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
-	Url                 string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	HealthCheckInterval int64                  `protobuf:"varint,3,opt,name=health_check_interval,json=healthCheckInterval,proto3" json:"health_check_interval,omitempty"`
+	ServiceId           uint64                 `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Url                 string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	HealthCheckInterval int64                  `protobuf:"varint,3,opt,name=health_check_interval,json=healthCheckInterval,proto3" json:"health_check_interval,omitempty"`
+	CheckType           string                 `protobuf:"bytes,4,opt,name=check_type,json=checkType,proto3" json:"check_type,omitempty"`
+	Port                int32                  `protobuf:"varint,5,opt,name=port,proto3" json:"port,omitempty"`
+	GrpcServiceName     string                 `protobuf:"bytes,6,opt,name=grpc_service_name,json=grpcServiceName,proto3" json:"grpc_service_name,omitempty"`
+	GrpcUseTls          bool                   `protobuf:"varint,7,opt,name=grpc_use_tls,json=grpcUseTls,proto3" json:"grpc_use_tls,omitempty"`
+	GrpcTimeout         int64                  `protobuf:"varint,8,opt,name=grpc_timeout,json=grpcTimeout,proto3" json:"grpc_timeout,omitempty"`
+	ExpectedStatusCodes []int32                `protobuf:"varint,9,rep,packed,name=expected_status_codes,json=expectedStatusCodes,proto3" json:"expected_status_codes,omitempty"`
+	BodyContains        string                 `protobuf:"bytes,10,opt,name=body_contains,json=bodyContains,proto3" json:"body_contains,omitempty"`
+	BodyRegex           string                 `protobuf:"bytes,11,opt,name=body_regex,json=bodyRegex,proto3" json:"body_regex,omitempty"`
+	JsonPath            string                 `protobuf:"bytes,12,opt,name=json_path,json=jsonPath,proto3" json:"json_path,omitempty"`
+	JsonPathEquals      string                 `protobuf:"bytes,13,opt,name=json_path_equals,json=jsonPathEquals,proto3" json:"json_path_equals,omitempty"`
+	Method              string                 `protobuf:"bytes,14,opt,name=method,proto3" json:"method,omitempty"`
+	EncryptedHeaders    map[string]string      `protobuf:"bytes,15,rep,name=encrypted_headers,json=encryptedHeaders,proto3" json:"encrypted_headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	RequestBody         string                 `protobuf:"bytes,16,opt,name=request_body,json=requestBody,proto3" json:"request_body,omitempty"`
+	AuthType            string                 `protobuf:"bytes,17,opt,name=auth_type,json=authType,proto3" json:"auth_type,omitempty"`
+	AuthUsername        string                 `protobuf:"bytes,18,opt,name=auth_username,json=authUsername,proto3" json:"auth_username,omitempty"`
+	EncryptedAuthSecret string                 `protobuf:"bytes,19,opt,name=encrypted_auth_secret,json=encryptedAuthSecret,proto3" json:"encrypted_auth_secret,omitempty"`
+	HeartbeatGrace      int64                  `protobuf:"varint,20,opt,name=heartbeat_grace,json=heartbeatGrace,proto3" json:"heartbeat_grace,omitempty"`
+	Locations           []string               `protobuf:"bytes,21,rep,name=locations,proto3" json:"locations,omitempty"`
+	Retries             int64                  `protobuf:"varint,22,opt,name=retries,proto3" json:"retries,omitempty"`
+	RetryBackoff        int64                  `protobuf:"varint,23,opt,name=retry_backoff,json=retryBackoff,proto3" json:"retry_backoff,omitempty"`
+	AttemptTimeout      int64                  `protobuf:"varint,24,opt,name=attempt_timeout,json=attemptTimeout,proto3" json:"attempt_timeout,omitempty"`
+	DnsRecordType       string                 `protobuf:"bytes,25,opt,name=dns_record_type,json=dnsRecordType,proto3" json:"dns_record_type,omitempty"`
+	DnsResolvers        []string               `protobuf:"bytes,26,rep,name=dns_resolvers,json=dnsResolvers,proto3" json:"dns_resolvers,omitempty"`
+	DnsExpected         []string               `protobuf:"bytes,27,rep,name=dns_expected,json=dnsExpected,proto3" json:"dns_expected,omitempty"`
+	Steps               []*SyntheticStep       `protobuf:"bytes,28,rep,name=steps,proto3" json:"steps,omitempty"`
+	FailingInterval     int64                  `protobuf:"varint,29,opt,name=failing_interval,json=failingInterval,proto3" json:"failing_interval,omitempty"`
+	CronSchedule        string                 `protobuf:"bytes,30,opt,name=cron_schedule,json=cronSchedule,proto3" json:"cron_schedule,omitempty"`
+	CronTimezone        string                 `protobuf:"bytes,31,opt,name=cron_timezone,json=cronTimezone,proto3" json:"cron_timezone,omitempty"`
+	LocationQuorum      int64                  `protobuf:"varint,32,opt,name=location_quorum,json=locationQuorum,proto3" json:"location_quorum,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ServiceInfoForScheduler) Reset() {
+	*x = ServiceInfoForScheduler{}
+	mi := &file_rpc_services_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceInfoForScheduler) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceInfoForScheduler) ProtoMessage() {}
+
+func (x *ServiceInfoForScheduler) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_services_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceInfoForScheduler.ProtoReflect.Descriptor instead.
+func (*ServiceInfoForScheduler) Descriptor() ([]byte, []int) {
+	return file_rpc_services_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ServiceInfoForScheduler) GetServiceId() uint64 {
+	if x != nil {
+		return x.ServiceId
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetHealthCheckInterval() int64 {
+	if x != nil {
+		return x.HealthCheckInterval
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetCheckType() string {
+	if x != nil {
+		return x.CheckType
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetGrpcServiceName() string {
+	if x != nil {
+		return x.GrpcServiceName
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetGrpcUseTls() bool {
+	if x != nil {
+		return x.GrpcUseTls
+	}
+	return false
+}
+
+func (x *ServiceInfoForScheduler) GetGrpcTimeout() int64 {
+	if x != nil {
+		return x.GrpcTimeout
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetExpectedStatusCodes() []int32 {
+	if x != nil {
+		return x.ExpectedStatusCodes
+	}
+	return nil
+}
+
+func (x *ServiceInfoForScheduler) GetBodyContains() string {
+	if x != nil {
+		return x.BodyContains
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetBodyRegex() string {
+	if x != nil {
+		return x.BodyRegex
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetJsonPath() string {
+	if x != nil {
+		return x.JsonPath
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetJsonPathEquals() string {
+	if x != nil {
+		return x.JsonPathEquals
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetEncryptedHeaders() map[string]string {
+	if x != nil {
+		return x.EncryptedHeaders
+	}
+	return nil
+}
+
+func (x *ServiceInfoForScheduler) GetRequestBody() string {
+	if x != nil {
+		return x.RequestBody
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetAuthType() string {
+	if x != nil {
+		return x.AuthType
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetAuthUsername() string {
+	if x != nil {
+		return x.AuthUsername
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetEncryptedAuthSecret() string {
+	if x != nil {
+		return x.EncryptedAuthSecret
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetHeartbeatGrace() int64 {
+	if x != nil {
+		return x.HeartbeatGrace
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetLocations() []string {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *ServiceInfoForScheduler) GetRetries() int64 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetRetryBackoff() int64 {
+	if x != nil {
+		return x.RetryBackoff
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetAttemptTimeout() int64 {
+	if x != nil {
+		return x.AttemptTimeout
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetDnsRecordType() string {
+	if x != nil {
+		return x.DnsRecordType
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetDnsResolvers() []string {
+	if x != nil {
+		return x.DnsResolvers
+	}
+	return nil
+}
+
+func (x *ServiceInfoForScheduler) GetDnsExpected() []string {
+	if x != nil {
+		return x.DnsExpected
+	}
+	return nil
+}
+
+func (x *ServiceInfoForScheduler) GetSteps() []*SyntheticStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *ServiceInfoForScheduler) GetFailingInterval() int64 {
+	if x != nil {
+		return x.FailingInterval
+	}
+	return 0
+}
+
+func (x *ServiceInfoForScheduler) GetCronSchedule() string {
+	if x != nil {
+		return x.CronSchedule
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetCronTimezone() string {
+	if x != nil {
+		return x.CronTimezone
+	}
+	return ""
+}
+
+func (x *ServiceInfoForScheduler) GetLocationQuorum() int64 {
+	if x != nil {
+		return x.LocationQuorum
+	}
+	return 0
+}
+
+type SyntheticStep struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Name                string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Method              string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Url                 string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Headers             map[string]string      `protobuf:"bytes,4,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Body                string                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	ExpectedStatusCodes []int32                `protobuf:"varint,6,rep,packed,name=expected_status_codes,json=expectedStatusCodes,proto3" json:"expected_status_codes,omitempty"`
+	BodyContains        string                 `protobuf:"bytes,7,opt,name=body_contains,json=bodyContains,proto3" json:"body_contains,omitempty"`
+	BodyRegex           string                 `protobuf:"bytes,8,opt,name=body_regex,json=bodyRegex,proto3" json:"body_regex,omitempty"`
+	JsonPath            string                 `protobuf:"bytes,9,opt,name=json_path,json=jsonPath,proto3" json:"json_path,omitempty"`
+	JsonPathEquals      string                 `protobuf:"bytes,10,opt,name=json_path_equals,json=jsonPathEquals,proto3" json:"json_path_equals,omitempty"`
+	Extract             []*VariableExtraction  `protobuf:"bytes,11,rep,name=extract,proto3" json:"extract,omitempty"`
 	unknownFields       protoimpl.UnknownFields
 	sizeCache           protoimpl.SizeCache
 }
 
-func (x *ServiceInfoForScheduler) Reset() {
-	*x = ServiceInfoForScheduler{}
-	mi := &file_rpc_services_proto_msgTypes[2]
+func (x *SyntheticStep) Reset() {
+	*x = SyntheticStep{}
+	mi := &file_rpc_services_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ServiceInfoForScheduler) String() string {
+func (x *SyntheticStep) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServiceInfoForScheduler) ProtoMessage() {}
+func (*SyntheticStep) ProtoMessage() {}
 
-func (x *ServiceInfoForScheduler) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_services_proto_msgTypes[2]
+func (x *SyntheticStep) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_services_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,30 +848,146 @@ func (x *ServiceInfoForScheduler) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServiceInfoForScheduler.ProtoReflect.Descriptor instead.
-func (*ServiceInfoForScheduler) Descriptor() ([]byte, []int) {
-	return file_rpc_services_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use SyntheticStep.ProtoReflect.Descriptor instead.
+func (*SyntheticStep) Descriptor() ([]byte, []int) {
+	return file_rpc_services_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ServiceInfoForScheduler) GetServiceId() uint64 {
+func (x *SyntheticStep) GetName() string {
 	if x != nil {
-		return x.ServiceId
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *ServiceInfoForScheduler) GetUrl() string {
+func (x *SyntheticStep) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *SyntheticStep) GetUrl() string {
 	if x != nil {
 		return x.Url
 	}
 	return ""
 }
 
-func (x *ServiceInfoForScheduler) GetHealthCheckInterval() int64 {
+func (x *SyntheticStep) GetHeaders() map[string]string {
 	if x != nil {
-		return x.HealthCheckInterval
+		return x.Headers
 	}
-	return 0
+	return nil
+}
+
+func (x *SyntheticStep) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *SyntheticStep) GetExpectedStatusCodes() []int32 {
+	if x != nil {
+		return x.ExpectedStatusCodes
+	}
+	return nil
+}
+
+func (x *SyntheticStep) GetBodyContains() string {
+	if x != nil {
+		return x.BodyContains
+	}
+	return ""
+}
+
+func (x *SyntheticStep) GetBodyRegex() string {
+	if x != nil {
+		return x.BodyRegex
+	}
+	return ""
+}
+
+func (x *SyntheticStep) GetJsonPath() string {
+	if x != nil {
+		return x.JsonPath
+	}
+	return ""
+}
+
+func (x *SyntheticStep) GetJsonPathEquals() string {
+	if x != nil {
+		return x.JsonPathEquals
+	}
+	return ""
+}
+
+func (x *SyntheticStep) GetExtract() []*VariableExtraction {
+	if x != nil {
+		return x.Extract
+	}
+	return nil
+}
+
+type VariableExtraction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	JsonPath      string                 `protobuf:"bytes,2,opt,name=json_path,json=jsonPath,proto3" json:"json_path,omitempty"`
+	Header        string                 `protobuf:"bytes,3,opt,name=header,proto3" json:"header,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VariableExtraction) Reset() {
+	*x = VariableExtraction{}
+	mi := &file_rpc_services_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariableExtraction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariableExtraction) ProtoMessage() {}
+
+func (x *VariableExtraction) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_services_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariableExtraction.ProtoReflect.Descriptor instead.
+func (*VariableExtraction) Descriptor() ([]byte, []int) {
+	return file_rpc_services_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VariableExtraction) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VariableExtraction) GetJsonPath() string {
+	if x != nil {
+		return x.JsonPath
+	}
+	return ""
+}
+
+func (x *VariableExtraction) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
 }
 
 type SchedulerConfigResponse struct {
@@ -203,7 +999,7 @@ type SchedulerConfigResponse struct {
 
 func (x *SchedulerConfigResponse) Reset() {
 	*x = SchedulerConfigResponse{}
-	mi := &file_rpc_services_proto_msgTypes[3]
+	mi := &file_rpc_services_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -215,7 +1011,7 @@ func (x *SchedulerConfigResponse) String() string {
 func (*SchedulerConfigResponse) ProtoMessage() {}
 
 func (x *SchedulerConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_services_proto_msgTypes[3]
+	mi := &file_rpc_services_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -228,7 +1024,7 @@ func (x *SchedulerConfigResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SchedulerConfigResponse.ProtoReflect.Descriptor instead.
 func (*SchedulerConfigResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_services_proto_rawDescGZIP(), []int{3}
+	return file_rpc_services_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *SchedulerConfigResponse) GetServices() []*ServiceInfoForScheduler {
@@ -244,24 +1040,118 @@ const file_rpc_services_proto_rawDesc = "" +
 	"\n" +
 	"\x12rpc/services.proto\x12\x03rpc\x1a\x1bgoogle/protobuf/empty.proto\"R\n" +
 	"\x17ServicesInfoForIncident\x127\n" +
-	"\bservices\x18\x01 \x03(\v2\x1b.rpc.ServiceInfoForIncidentR\bservices\"\xac\x01\n" +
+	"\bservices\x18\x01 \x03(\v2\x1b.rpc.ServiceInfoForIncidentR\bservices\"\xea\x04\n" +
 	"\x16ServiceInfoForIncident\x12\x1d\n" +
 	"\n" +
 	"service_id\x18\x01 \x01(\x04R\tserviceId\x12!\n" +
 	"\falert_window\x18\x02 \x01(\x03R\valertWindow\x122\n" +
 	"\x15allowed_response_time\x18\x03 \x01(\x03R\x13allowedResponseTime\x12\x1c\n" +
-	"\toncallers\x18\x04 \x03(\tR\toncallers\"~\n" +
+	"\toncallers\x18\x04 \x03(\tR\toncallers\x12+\n" +
+	"\x11latency_threshold\x18\x05 \x01(\x03R\x10latencyThreshold\x12(\n" +
+	"\x10cert_expiry_days\x18\x06 \x01(\x03R\x0ecertExpiryDays\x12\x1c\n" +
+	"\tlocations\x18\a \x03(\tR\tlocations\x12'\n" +
+	"\x0flocation_quorum\x18\b \x01(\x03R\x0elocationQuorum\x124\n" +
+	"\n" +
+	"escalation\x18\t \x03(\v2\x14.rpc.EscalationLevelR\n" +
+	"escalation\x12+\n" +
+	"\x11escalation_repeat\x18\n" +
+	" \x01(\x03R\x10escalationRepeat\x12!\n" +
+	"\fauto_resolve\x18\v \x01(\bR\vautoResolve\x12'\n" +
+	"\x0frecovery_checks\x18\f \x01(\x03R\x0erecoveryChecks\x12'\n" +
+	"\x0fnotify_recovery\x18\r \x01(\bR\x0enotifyRecovery\x12\x1f\n" +
+	"\vflap_window\x18\x0e \x01(\x03R\n" +
+	"flapWindow\x12%\n" +
+	"\x0eflap_threshold\x18\x0f \x01(\x03R\rflapThreshold\"r\n" +
+	"\x0fEscalationLevel\x12\x18\n" +
+	"\atargets\x18\x01 \x03(\tR\atargets\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x03R\atimeout\x12+\n" +
+	"\tschedules\x18\x03 \x03(\v2\r.rpc.ScheduleR\tschedules\"\x87\x01\n" +
+	"\bSchedule\x12\x1a\n" +
+	"\btimezone\x18\x01 \x01(\tR\btimezone\x12*\n" +
+	"\x06layers\x18\x02 \x03(\v2\x12.rpc.ScheduleLayerR\x06layers\x123\n" +
+	"\toverrides\x18\x03 \x03(\v2\x15.rpc.ScheduleOverrideR\toverrides\"r\n" +
+	"\rScheduleLayer\x12\x14\n" +
+	"\x05users\x18\x01 \x03(\tR\x05users\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\tR\x03end\x12#\n" +
+	"\rrotation_days\x18\x04 \x01(\x03R\frotationDays\"N\n" +
+	"\x10ScheduleOverride\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x03R\x03end\"7\n" +
+	"\x16SchedulerConfigRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\x04R\tserviceId\"\x9f\n" +
+	"\n" +
 	"\x17ServiceInfoForScheduler\x12\x1d\n" +
 	"\n" +
 	"service_id\x18\x01 \x01(\x04R\tserviceId\x12\x10\n" +
 	"\x03url\x18\x02 \x01(\tR\x03url\x122\n" +
-	"\x15health_check_interval\x18\x03 \x01(\x03R\x13healthCheckInterval\"S\n" +
+	"\x15health_check_interval\x18\x03 \x01(\x03R\x13healthCheckInterval\x12\x1d\n" +
+	"\n" +
+	"check_type\x18\x04 \x01(\tR\tcheckType\x12\x12\n" +
+	"\x04port\x18\x05 \x01(\x05R\x04port\x12*\n" +
+	"\x11grpc_service_name\x18\x06 \x01(\tR\x0fgrpcServiceName\x12 \n" +
+	"\fgrpc_use_tls\x18\a \x01(\bR\n" +
+	"grpcUseTls\x12!\n" +
+	"\fgrpc_timeout\x18\b \x01(\x03R\vgrpcTimeout\x122\n" +
+	"\x15expected_status_codes\x18\t \x03(\x05R\x13expectedStatusCodes\x12#\n" +
+	"\rbody_contains\x18\n" +
+	" \x01(\tR\fbodyContains\x12\x1d\n" +
+	"\n" +
+	"body_regex\x18\v \x01(\tR\tbodyRegex\x12\x1b\n" +
+	"\tjson_path\x18\f \x01(\tR\bjsonPath\x12(\n" +
+	"\x10json_path_equals\x18\r \x01(\tR\x0ejsonPathEquals\x12\x16\n" +
+	"\x06method\x18\x0e \x01(\tR\x06method\x12_\n" +
+	"\x11encrypted_headers\x18\x0f \x03(\v22.rpc.ServiceInfoForScheduler.EncryptedHeadersEntryR\x10encryptedHeaders\x12!\n" +
+	"\frequest_body\x18\x10 \x01(\tR\vrequestBody\x12\x1b\n" +
+	"\tauth_type\x18\x11 \x01(\tR\bauthType\x12#\n" +
+	"\rauth_username\x18\x12 \x01(\tR\fauthUsername\x122\n" +
+	"\x15encrypted_auth_secret\x18\x13 \x01(\tR\x13encryptedAuthSecret\x12'\n" +
+	"\x0fheartbeat_grace\x18\x14 \x01(\x03R\x0eheartbeatGrace\x12\x1c\n" +
+	"\tlocations\x18\x15 \x03(\tR\tlocations\x12\x18\n" +
+	"\aretries\x18\x16 \x01(\x03R\aretries\x12#\n" +
+	"\rretry_backoff\x18\x17 \x01(\x03R\fretryBackoff\x12'\n" +
+	"\x0fattempt_timeout\x18\x18 \x01(\x03R\x0eattemptTimeout\x12&\n" +
+	"\x0fdns_record_type\x18\x19 \x01(\tR\rdnsRecordType\x12#\n" +
+	"\rdns_resolvers\x18\x1a \x03(\tR\fdnsResolvers\x12!\n" +
+	"\fdns_expected\x18\x1b \x03(\tR\vdnsExpected\x12(\n" +
+	"\x05steps\x18\x1c \x03(\v2\x12.rpc.SyntheticStepR\x05steps\x12)\n" +
+	"\x10failing_interval\x18\x1d \x01(\x03R\x0ffailingInterval\x12#\n" +
+	"\rcron_schedule\x18\x1e \x01(\tR\fcronSchedule\x12#\n" +
+	"\rcron_timezone\x18\x1f \x01(\tR\fcronTimezone\x12'\n" +
+	"\x0flocation_quorum\x18  \x01(\x03R\x0elocationQuorum\x1aC\n" +
+	"\x15EncryptedHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xca\x03\n" +
+	"\rSyntheticStep\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x129\n" +
+	"\aheaders\x18\x04 \x03(\v2\x1f.rpc.SyntheticStep.HeadersEntryR\aheaders\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04body\x122\n" +
+	"\x15expected_status_codes\x18\x06 \x03(\x05R\x13expectedStatusCodes\x12#\n" +
+	"\rbody_contains\x18\a \x01(\tR\fbodyContains\x12\x1d\n" +
+	"\n" +
+	"body_regex\x18\b \x01(\tR\tbodyRegex\x12\x1b\n" +
+	"\tjson_path\x18\t \x01(\tR\bjsonPath\x12(\n" +
+	"\x10json_path_equals\x18\n" +
+	" \x01(\tR\x0ejsonPathEquals\x121\n" +
+	"\aextract\x18\v \x03(\v2\x17.rpc.VariableExtractionR\aextract\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\x12VariableExtraction\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tjson_path\x18\x02 \x01(\tR\bjsonPath\x12\x16\n" +
+	"\x06header\x18\x03 \x01(\tR\x06header\"S\n" +
 	"\x17SchedulerConfigResponse\x128\n" +
 	"\bservices\x18\x01 \x03(\v2\x1c.rpc.ServiceInfoForSchedulerR\bservices2d\n" +
 	"\x16IncidentManagerService\x12J\n" +
-	"\x12GetAllServicesInfo\x12\x16.google.protobuf.Empty\x1a\x1c.rpc.ServicesInfoForIncident2i\n" +
+	"\x12GetAllServicesInfo\x12\x16.google.protobuf.Empty\x1a\x1c.rpc.ServicesInfoForIncident2\xc1\x01\n" +
 	"\x10SchedulerService\x12U\n" +
-	"\x1dGetAllSchedulerConfigurations\x12\x16.google.protobuf.Empty\x1a\x1c.rpc.SchedulerConfigResponseB\x1eZ\x1calerting-platform/common/rpcb\x06proto3"
+	"\x1dGetAllSchedulerConfigurations\x12\x16.google.protobuf.Empty\x1a\x1c.rpc.SchedulerConfigResponse\x12V\n" +
+	"\x19GetSchedulerConfiguration\x12\x1b.rpc.SchedulerConfigRequest\x1a\x1c.rpc.ServiceInfoForSchedulerB\x1eZ\x1calerting-platform/common/rpcb\x06proto3"
 
 var (
 	file_rpc_services_proto_rawDescOnce sync.Once
@@ -275,26 +1165,45 @@ func file_rpc_services_proto_rawDescGZIP() []byte {
 	return file_rpc_services_proto_rawDescData
 }
 
-var file_rpc_services_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rpc_services_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_rpc_services_proto_goTypes = []any{
 	(*ServicesInfoForIncident)(nil), // 0: rpc.ServicesInfoForIncident
 	(*ServiceInfoForIncident)(nil),  // 1: rpc.ServiceInfoForIncident
-	(*ServiceInfoForScheduler)(nil), // 2: rpc.ServiceInfoForScheduler
-	(*SchedulerConfigResponse)(nil), // 3: rpc.SchedulerConfigResponse
-	(*emptypb.Empty)(nil),           // 4: google.protobuf.Empty
+	(*EscalationLevel)(nil),         // 2: rpc.EscalationLevel
+	(*Schedule)(nil),                // 3: rpc.Schedule
+	(*ScheduleLayer)(nil),           // 4: rpc.ScheduleLayer
+	(*ScheduleOverride)(nil),        // 5: rpc.ScheduleOverride
+	(*SchedulerConfigRequest)(nil),  // 6: rpc.SchedulerConfigRequest
+	(*ServiceInfoForScheduler)(nil), // 7: rpc.ServiceInfoForScheduler
+	(*SyntheticStep)(nil),           // 8: rpc.SyntheticStep
+	(*VariableExtraction)(nil),      // 9: rpc.VariableExtraction
+	(*SchedulerConfigResponse)(nil), // 10: rpc.SchedulerConfigResponse
+	nil,                             // 11: rpc.ServiceInfoForScheduler.EncryptedHeadersEntry
+	nil,                             // 12: rpc.SyntheticStep.HeadersEntry
+	(*emptypb.Empty)(nil),           // 13: google.protobuf.Empty
 }
 var file_rpc_services_proto_depIdxs = []int32{
-	1, // 0: rpc.ServicesInfoForIncident.services:type_name -> rpc.ServiceInfoForIncident
-	2, // 1: rpc.SchedulerConfigResponse.services:type_name -> rpc.ServiceInfoForScheduler
-	4, // 2: rpc.IncidentManagerService.GetAllServicesInfo:input_type -> google.protobuf.Empty
-	4, // 3: rpc.SchedulerService.GetAllSchedulerConfigurations:input_type -> google.protobuf.Empty
-	0, // 4: rpc.IncidentManagerService.GetAllServicesInfo:output_type -> rpc.ServicesInfoForIncident
-	3, // 5: rpc.SchedulerService.GetAllSchedulerConfigurations:output_type -> rpc.SchedulerConfigResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1,  // 0: rpc.ServicesInfoForIncident.services:type_name -> rpc.ServiceInfoForIncident
+	2,  // 1: rpc.ServiceInfoForIncident.escalation:type_name -> rpc.EscalationLevel
+	3,  // 2: rpc.EscalationLevel.schedules:type_name -> rpc.Schedule
+	4,  // 3: rpc.Schedule.layers:type_name -> rpc.ScheduleLayer
+	5,  // 4: rpc.Schedule.overrides:type_name -> rpc.ScheduleOverride
+	11, // 5: rpc.ServiceInfoForScheduler.encrypted_headers:type_name -> rpc.ServiceInfoForScheduler.EncryptedHeadersEntry
+	8,  // 6: rpc.ServiceInfoForScheduler.steps:type_name -> rpc.SyntheticStep
+	12, // 7: rpc.SyntheticStep.headers:type_name -> rpc.SyntheticStep.HeadersEntry
+	9,  // 8: rpc.SyntheticStep.extract:type_name -> rpc.VariableExtraction
+	7,  // 9: rpc.SchedulerConfigResponse.services:type_name -> rpc.ServiceInfoForScheduler
+	13, // 10: rpc.IncidentManagerService.GetAllServicesInfo:input_type -> google.protobuf.Empty
+	13, // 11: rpc.SchedulerService.GetAllSchedulerConfigurations:input_type -> google.protobuf.Empty
+	6,  // 12: rpc.SchedulerService.GetSchedulerConfiguration:input_type -> rpc.SchedulerConfigRequest
+	0,  // 13: rpc.IncidentManagerService.GetAllServicesInfo:output_type -> rpc.ServicesInfoForIncident
+	10, // 14: rpc.SchedulerService.GetAllSchedulerConfigurations:output_type -> rpc.SchedulerConfigResponse
+	7,  // 15: rpc.SchedulerService.GetSchedulerConfiguration:output_type -> rpc.ServiceInfoForScheduler
+	13, // [13:16] is the sub-list for method output_type
+	10, // [10:13] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_rpc_services_proto_init() }
@@ -308,7 +1217,7 @@ func file_rpc_services_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_services_proto_rawDesc), len(file_rpc_services_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -124,6 +124,7 @@ var IncidentManagerService_ServiceDesc = grpc.ServiceDesc{
 
 const (
 	SchedulerService_GetAllSchedulerConfigurations_FullMethodName = "/rpc.SchedulerService/GetAllSchedulerConfigurations"
+	SchedulerService_GetSchedulerConfiguration_FullMethodName     = "/rpc.SchedulerService/GetSchedulerConfiguration"
 )
 
 // SchedulerServiceClient is the client API for SchedulerService service.
@@ -131,6 +132,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SchedulerServiceClient interface {
 	GetAllSchedulerConfigurations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SchedulerConfigResponse, error)
+	GetSchedulerConfiguration(ctx context.Context, in *SchedulerConfigRequest, opts ...grpc.CallOption) (*ServiceInfoForScheduler, error)
 }
 
 type schedulerServiceClient struct {
@@ -151,11 +153,22 @@ func (c *schedulerServiceClient) GetAllSchedulerConfigurations(ctx context.Conte
 	return out, nil
 }
 
+func (c *schedulerServiceClient) GetSchedulerConfiguration(ctx context.Context, in *SchedulerConfigRequest, opts ...grpc.CallOption) (*ServiceInfoForScheduler, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServiceInfoForScheduler)
+	err := c.cc.Invoke(ctx, SchedulerService_GetSchedulerConfiguration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SchedulerServiceServer is the server API for SchedulerService service.
 // All implementations must embed UnimplementedSchedulerServiceServer
 // for forward compatibility.
 type SchedulerServiceServer interface {
 	GetAllSchedulerConfigurations(context.Context, *emptypb.Empty) (*SchedulerConfigResponse, error)
+	GetSchedulerConfiguration(context.Context, *SchedulerConfigRequest) (*ServiceInfoForScheduler, error)
 	mustEmbedUnimplementedSchedulerServiceServer()
 }
 
@@ -169,6 +182,9 @@ type UnimplementedSchedulerServiceServer struct{}
 func (UnimplementedSchedulerServiceServer) GetAllSchedulerConfigurations(context.Context, *emptypb.Empty) (*SchedulerConfigResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetAllSchedulerConfigurations not implemented")
 }
+func (UnimplementedSchedulerServiceServer) GetSchedulerConfiguration(context.Context, *SchedulerConfigRequest) (*ServiceInfoForScheduler, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSchedulerConfiguration not implemented")
+}
 func (UnimplementedSchedulerServiceServer) mustEmbedUnimplementedSchedulerServiceServer() {}
 func (UnimplementedSchedulerServiceServer) testEmbeddedByValue()                          {}
 
@@ -208,6 +224,24 @@ func _SchedulerService_GetAllSchedulerConfigurations_Handler(srv interface{}, ct
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SchedulerService_GetSchedulerConfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchedulerConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetSchedulerConfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_GetSchedulerConfiguration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetSchedulerConfiguration(ctx, req.(*SchedulerConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SchedulerService_ServiceDesc is the grpc.ServiceDesc for SchedulerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -219,6 +253,10 @@ var SchedulerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAllSchedulerConfigurations",
 			Handler:    _SchedulerService_GetAllSchedulerConfigurations_Handler,
 		},
+		{
+			MethodName: "GetSchedulerConfiguration",
+			Handler:    _SchedulerService_GetSchedulerConfiguration_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc/services.proto",
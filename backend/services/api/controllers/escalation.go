@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+
+	"alerting-platform/api/db"
+	"alerting-platform/api/dto"
+	"alerting-platform/api/middleware"
+	"alerting-platform/api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (controller *Controller) CreateEscalationPolicy(c *gin.Context) {
+	var policyInput dto.EscalationPolicyRequest
+
+	if err := c.ShouldBind(&policyInput); err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+
+	if !controller.checkEscalationLevels(c, policyInput.Levels, uint64(jwtUser.ID)) {
+		return
+	}
+
+	policy := db.EscalationPolicy{UserID: jwtUser.ID}
+	utils.MapRequestToEscalationPolicy(policyInput, &policy)
+
+	err := controller.Repository.CreateEscalationPolicy(c.Request.Context(), &policy)
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to create escalation policy", "error": err.Error()})
+		return
+	}
+
+	c.JSON(201, gin.H{"message": "Escalation policy created successfully", "policyID": policy.ID})
+}
+
+func (controller *Controller) GetMyEscalationPolicies(c *gin.Context) {
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+
+	policies, err := controller.Repository.GetEscalationPoliciesForUser(c.Request.Context(), uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to retrieve escalation policies", "error": err.Error()})
+		return
+	}
+
+	dtos := make([]dto.EscalationPolicyDTO, 0, len(policies))
+	for _, policy := range policies {
+		dtos = append(dtos, utils.MapEscalationPolicyToDTO(policy))
+	}
+
+	c.JSON(200, dtos)
+}
+
+func (controller *Controller) GetEscalationPolicyByID(c *gin.Context) {
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"message": "Invalid escalation policy ID", "error": err.Error()})
+		return
+	}
+
+	policy, err := controller.Repository.GetEscalationPolicyByIDAndUserID(c.Request.Context(), policyID, uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(404, gin.H{"message": "Escalation policy not found", "error": err.Error()})
+		return
+	}
+
+	c.JSON(200, utils.MapEscalationPolicyToDTO(*policy))
+}
+
+// UpdateEscalationPolicy also sends the new levels to the incident manager for
+// every service using the policy
+func (controller *Controller) UpdateEscalationPolicy(c *gin.Context) {
+	var policyInput dto.EscalationPolicyRequest
+	if err := c.ShouldBind(&policyInput); err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+	ctx := c.Request.Context()
+
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"message": "Invalid escalation policy ID", "error": err.Error()})
+		return
+	}
+
+	policy, err := controller.Repository.GetEscalationPolicyByIDAndUserID(ctx, policyID, uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(404, gin.H{"message": "Escalation policy not found", "error": err.Error()})
+		return
+	}
+
+	if !controller.checkEscalationLevels(c, policyInput.Levels, uint64(jwtUser.ID)) {
+		return
+	}
+
+	utils.MapRequestToEscalationPolicy(policyInput, policy)
+
+	if err := controller.Repository.SaveEscalationPolicy(ctx, policy); err != nil {
+		c.JSON(500, gin.H{"message": "Failed to update escalation policy", "error": err.Error()})
+		return
+	}
+
+	if err := controller.sendPolicyServicesUpdated(c, policy); err != nil {
+		c.JSON(500, gin.H{"message": "Failed to send service updated message", "error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Escalation policy updated successfully"})
+}
+
+// DeleteEscalationPolicy refuses to delete policies still used by services. Only the
+// owner learns whether the policy is used.
+func (controller *Controller) DeleteEscalationPolicy(c *gin.Context) {
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+	ctx := c.Request.Context()
+
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"message": "Invalid escalation policy ID", "error": err.Error()})
+		return
+	}
+
+	_, err = controller.Repository.GetEscalationPolicyByIDAndUserID(ctx, policyID, uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(404, gin.H{"message": "Escalation policy not found", "error": err.Error()})
+		return
+	}
+
+	services, err := controller.Repository.GetServicesByEscalationPolicy(ctx, policyID)
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to retrieve services using the escalation policy", "error": err.Error()})
+		return
+	}
+
+	if len(services) > 0 {
+		c.JSON(409, gin.H{"message": "Escalation policy is used by " + strconv.Itoa(len(services)) + " services"})
+		return
+	}
+
+	rowsAffected, err := controller.Repository.DeleteEscalationPolicyForUser(ctx, policyID, uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to delete escalation policy", "error": err.Error()})
+		return
+	}
+
+	if rowsAffected == 0 {
+		c.JSON(404, gin.H{"message": "Escalation policy not found"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Escalation policy deleted successfully"})
+}
+
+// getEscalationPolicy loads the policy a service references, which must belong to the
+// same user. It writes the error response itself and reports whether to continue.
+func (controller *Controller) getEscalationPolicy(c *gin.Context, policyID *uint, userID uint64) (*db.EscalationPolicy, bool) {
+	if policyID == nil {
+		return nil, true
+	}
+
+	policy, err := controller.Repository.GetEscalationPolicyByIDAndUserID(c.Request.Context(), uint64(*policyID), userID)
+	if err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": "escalation policy not found"})
+		return nil, false
+	}
+
+	policy.Schedules, err = controller.Repository.GetSchedulesByIDs(c.Request.Context(), policy.ScheduleIDs())
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to retrieve schedules of the escalation policy", "error": err.Error()})
+		return nil, false
+	}
+
+	return policy, true
+}
+
+// checkEscalationLevels makes sure every level notifies somebody, using schedules of
+// the same user. It writes the error response itself and reports whether to continue.
+func (controller *Controller) checkEscalationLevels(c *gin.Context, levels []dto.EscalationLevelDTO, userID uint64) bool {
+	for i, level := range levels {
+		if len(level.Targets) == 0 && len(level.ScheduleIDs) == 0 {
+			c.JSON(400, gin.H{"message": "Invalid input", "error": fmt.Sprintf("level %d has no targets or schedules", i+1)})
+			return false
+		}
+
+		for _, scheduleID := range level.ScheduleIDs {
+			_, err := controller.Repository.GetScheduleByIDAndUserID(c.Request.Context(), uint64(scheduleID), userID)
+			if err != nil {
+				c.JSON(400, gin.H{"message": "Invalid input", "error": fmt.Sprintf("schedule %d not found", scheduleID)})
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// sendPolicyServicesUpdated sends the levels of the policy, with their current
+// schedules, to the incident manager for every service using the policy.
+// Incidents already escalating keep their copy.
+func (controller *Controller) sendPolicyServicesUpdated(c *gin.Context, policy *db.EscalationPolicy) error {
+	ctx := c.Request.Context()
+
+	var err error
+	policy.Schedules, err = controller.Repository.GetSchedulesByIDs(ctx, policy.ScheduleIDs())
+	if err != nil {
+		return err
+	}
+
+	services, err := controller.Repository.GetServicesByEscalationPolicy(ctx, uint64(policy.ID))
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		service.EscalationPolicy = policy
+		if err := controller.PubSubService.SendServiceUpdatedMessage(ctx, service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,318 @@
+package controllers
+
+import (
+	"alerting-platform/api/db"
+	"alerting-platform/api/dto"
+	"alerting-platform/api/middleware"
+	pubsub_common "alerting-platform/common/pubsub"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateEscalationPolicy(t *testing.T) {
+	_, mockRepo, _, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+	policyInput := dto.EscalationPolicyRequest{
+		Name: "Backend",
+		Levels: []dto.EscalationLevelDTO{
+			{Targets: []string{"first@example.com", "second@example.com"}, Timeout: 5},
+			{Targets: []string{"lead@example.com"}, Timeout: 15},
+		},
+		Repeat: 1,
+	}
+
+	t.Run("Success 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		jsonValue, _ := json.Marshal(policyInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/escalation-policies", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("CreateEscalationPolicy", mock.Anything, mock.MatchedBy(func(policy *db.EscalationPolicy) bool {
+			return policy.UserID == jwtUser.ID && policy.Repeat == 1 && len(policy.Levels) == 2 &&
+				policy.Levels[0].Timeout == 5 && len(policy.Levels[0].Targets) == 2
+		})).Return(nil).Once()
+
+		controller.CreateEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "Escalation policy created successfully")
+		mockRepo.AssertExpectations(t)
+	})
+
+	invalidInputs := map[string]dto.EscalationPolicyRequest{
+		"No levels":        {Name: "Backend"},
+		"No targets":       {Name: "Backend", Levels: []dto.EscalationLevelDTO{{Timeout: 5}}},
+		"Invalid target":   {Name: "Backend", Levels: []dto.EscalationLevelDTO{{Targets: []string{"not an email"}, Timeout: 5}}},
+		"No timeout":       {Name: "Backend", Levels: []dto.EscalationLevelDTO{{Targets: []string{"first@example.com"}}}},
+		"Too many repeats": {Name: "Backend", Levels: policyInput.Levels, Repeat: 6},
+	}
+
+	for name, input := range invalidInputs {
+		t.Run(name+" 400", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			jsonValue, _ := json.Marshal(input)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/escalation-policies", bytes.NewBuffer(jsonValue))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set(middleware.IdentityKey, jwtUser)
+
+			controller.CreateEscalationPolicy(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "Invalid input")
+		})
+	}
+}
+
+func TestEscalationPolicySchedules(t *testing.T) {
+	_, mockRepo, _, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+
+	t.Run("Schedule only level 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		policyInput := dto.EscalationPolicyRequest{
+			Name:   "Rotation",
+			Levels: []dto.EscalationLevelDTO{{ScheduleIDs: []uint{5}, Timeout: 5}},
+		}
+
+		jsonValue, _ := json.Marshal(policyInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/escalation-policies", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetScheduleByIDAndUserID", mock.Anything, uint64(5), uint64(jwtUser.ID)).Return(&db.Schedule{Model: gorm.Model{ID: 5}}, nil).Once()
+		mockRepo.On("CreateEscalationPolicy", mock.Anything, mock.MatchedBy(func(policy *db.EscalationPolicy) bool {
+			return len(policy.Levels) == 1 && len(policy.Levels[0].Targets) == 0 && policy.Levels[0].ScheduleIDs[0] == 5
+		})).Return(nil).Once()
+
+		controller.CreateEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Schedule of another user 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		policyInput := dto.EscalationPolicyRequest{
+			Name:   "Rotation",
+			Levels: []dto.EscalationLevelDTO{{Targets: []string{"first@example.com"}, ScheduleIDs: []uint{6}, Timeout: 5}},
+		}
+
+		jsonValue, _ := json.Marshal(policyInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/escalation-policies", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetScheduleByIDAndUserID", mock.Anything, uint64(6), uint64(jwtUser.ID)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		controller.CreateEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "schedule 6 not found")
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetEscalationPolicyByID(t *testing.T) {
+	_, mockRepo, _, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+
+	t.Run("Success 200", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/escalation-policies/3", nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		policy := &db.EscalationPolicy{
+			Model:  gorm.Model{ID: 3},
+			Name:   "Backend",
+			Levels: []pubsub_common.EscalationLevel{{Targets: []string{"first@example.com"}, Timeout: 5}},
+		}
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(policy, nil).Once()
+
+		controller.GetEscalationPolicyByID(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.EscalationPolicyDTO
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Backend", response.Name)
+		assert.Equal(t, []dto.EscalationLevelDTO{{Targets: []string{"first@example.com"}, Timeout: 5}}, response.Levels)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found 404", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/escalation-policies/3", nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		controller.GetEscalationPolicyByID(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdateEscalationPolicy(t *testing.T) {
+	_, mockRepo, mockPubSub, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+	policyInput := dto.EscalationPolicyRequest{
+		Name:   "Backend",
+		Levels: []dto.EscalationLevelDTO{{Targets: []string{"lead@example.com"}, Timeout: 10}},
+	}
+
+	t.Run("Services using the policy are updated 200", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		jsonValue, _ := json.Marshal(policyInput)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/escalation-policies/3", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		policy := &db.EscalationPolicy{Model: gorm.Model{ID: 3}, UserID: jwtUser.ID}
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(policy, nil).Once()
+		mockRepo.On("SaveEscalationPolicy", mock.Anything, policy).Return(nil).Once()
+		mockRepo.On("GetSchedulesByIDs", mock.Anything, []uint(nil)).Return(nil, nil).Once()
+		mockRepo.On("GetServicesByEscalationPolicy", mock.Anything, uint64(3)).Return([]db.MonitoredService{
+			{Model: gorm.Model{ID: 7}},
+			{Model: gorm.Model{ID: 8}},
+		}, nil).Once()
+		mockPubSub.On("SendServiceUpdatedMessage", mock.Anything, mock.MatchedBy(func(service db.MonitoredService) bool {
+			levels, _ := service.Escalation()
+			return len(levels) == 1 && levels[0].Targets[0] == "lead@example.com"
+		})).Return(nil).Twice()
+
+		controller.UpdateEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Escalation policy updated successfully")
+		mockRepo.AssertExpectations(t)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Not Found 404", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		jsonValue, _ := json.Marshal(policyInput)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/escalation-policies/3", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		controller.UpdateEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestDeleteEscalationPolicy(t *testing.T) {
+	_, mockRepo, _, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+
+	t.Run("Success 200", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/escalation-policies/3", nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(&db.EscalationPolicy{Model: gorm.Model{ID: 3}, UserID: jwtUser.ID}, nil).Once()
+		mockRepo.On("GetServicesByEscalationPolicy", mock.Anything, uint64(3)).Return([]db.MonitoredService{}, nil).Once()
+		mockRepo.On("DeleteEscalationPolicyForUser", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(1, nil).Once()
+
+		controller.DeleteEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Used by services 409", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/escalation-policies/3", nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(&db.EscalationPolicy{Model: gorm.Model{ID: 3}, UserID: jwtUser.ID}, nil).Once()
+		mockRepo.On("GetServicesByEscalationPolicy", mock.Anything, uint64(3)).Return([]db.MonitoredService{{}}, nil).Once()
+
+		controller.DeleteEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Escalation policy is used by 1 services")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("DB Error 500", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/escalation-policies/3", nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(&db.EscalationPolicy{Model: gorm.Model{ID: 3}, UserID: jwtUser.ID}, nil).Once()
+		mockRepo.On("GetServicesByEscalationPolicy", mock.Anything, uint64(3)).Return(nil, errors.New("db error")).Once()
+
+		controller.DeleteEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Policy of another user 404", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		otherUser := &middleware.JWTUser{ID: 2, Email: "other@user.com"}
+
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/escalation-policies/3", nil)
+		c.Set(middleware.IdentityKey, otherUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(otherUser.ID)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		controller.DeleteEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.NotContains(t, w.Body.String(), "used by")
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"alerting-platform/api/redis"
+	db_common "alerting-platform/common/db"
+	pubsub_common "alerting-platform/common/pubsub"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReceiveHeartbeat is public, the token in the path is the only credential
+func (controller *Controller) ReceiveHeartbeat(c *gin.Context) {
+	token := c.Param("token")
+	ctx := c.Request.Context()
+
+	service, err := controller.Repository.GetServiceByHeartbeatToken(ctx, token)
+	if err != nil || service.CheckType != pubsub_common.CheckTypeHeartbeat {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Heartbeat monitor not found"})
+		return
+	}
+
+	serviceID := uint64(service.ID)
+	log.Printf("[DEBUG] Heartbeat received for service %d", serviceID)
+
+	redisClient := db_common.GetRedisClient()
+	err = redisClient.Set(ctx, redis.GetLastHeartbeatKey(serviceID), time.Now().UTC().Unix(), 0).Err()
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to record heartbeat", "error": err.Error()})
+		return
+	}
+
+	err = controller.PubSubService.SendHeartbeatMessage(ctx, serviceID)
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to send heartbeat message", "error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Heartbeat received"})
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"alerting-platform/api/db"
+	"alerting-platform/api/redis"
+	pubsub_common "alerting-platform/common/pubsub"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestReceiveHeartbeat(t *testing.T) {
+	_, mockRepo, mockPubSub, _, controller := setupTestRouter()
+
+	token := "b7d1c4e0"
+	service := &db.MonitoredService{
+		Model:          gorm.Model{ID: 7},
+		CheckType:      pubsub_common.CheckTypeHeartbeat,
+		HeartbeatToken: &token,
+	}
+
+	newContext := func(token string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/heartbeat/"+token, nil)
+		c.Params = gin.Params{gin.Param{Key: "token", Value: token}}
+		return c, w
+	}
+
+	t.Run("Success 200", func(t *testing.T) {
+		s := setupRedis(t)
+		defer s.Close()
+
+		mockRepo.On("GetServiceByHeartbeatToken", mock.Anything, token).Return(service, nil).Once()
+		mockPubSub.On("SendHeartbeatMessage", mock.Anything, uint64(7)).Return(nil).Once()
+
+		c, w := newContext(token)
+		controller.ReceiveHeartbeat(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, s.Exists(redis.GetLastHeartbeatKey(7)))
+		mockRepo.AssertExpectations(t)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Token 404", func(t *testing.T) {
+		_, mockRepo, mockPubSub, _, controller := setupTestRouter()
+		mockRepo.On("GetServiceByHeartbeatToken", mock.Anything, "unknown").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		c, w := newContext("unknown")
+		controller.ReceiveHeartbeat(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockPubSub.AssertNotCalled(t, "SendHeartbeatMessage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Pub/Sub Error 500", func(t *testing.T) {
+		s := setupRedis(t)
+		defer s.Close()
+
+		mockRepo.On("GetServiceByHeartbeatToken", mock.Anything, token).Return(service, nil).Once()
+		mockPubSub.On("SendHeartbeatMessage", mock.Anything, uint64(7)).Return(errors.New("pubsub connection failed")).Once()
+
+		c, w := newContext(token)
+		controller.ReceiveHeartbeat(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -10,11 +10,45 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// AcknowledgeIncident stops the escalation of the incident, which stays open until resolved
+func (controller *Controller) AcknowledgeIncident(c *gin.Context) {
+	claims, ok := parseIncidentToken(c, magic_link.ActionAcknowledge)
+	if !ok {
+		return
+	}
+	log.Printf("[DEBUG] Acknowledging incident %s for service %d by on-caller %s", claims.IncidentID, claims.ServiceID, claims.OnCaller)
+
+	err := controller.PubSubService.SendOncallerAcknowledgedMessage(c, claims.IncidentID, claims.ServiceID, claims.OnCaller)
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to send on-caller acknowledged message", "error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Incident acknowledged successfully"})
+}
+
 func (controller *Controller) ResolveIncident(c *gin.Context) {
+	claims, ok := parseIncidentToken(c, magic_link.ActionResolve)
+	if !ok {
+		return
+	}
+	log.Printf("[DEBUG] Resolving incident %s for service %d by on-caller %s", claims.IncidentID, claims.ServiceID, claims.OnCaller)
+
+	err := controller.PubSubService.SendOncallerResolvedMessage(c, claims.IncidentID, claims.ServiceID, claims.OnCaller)
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to send on-caller resolved message", "error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Incident resolved successfully"})
+}
+
+// parseIncidentToken writes the error response itself and reports whether the token allows the action
+func parseIncidentToken(c *gin.Context, action string) (*magic_link.ActionClaims, bool) {
 	tokenString := c.Param("token")
 	if tokenString == "" {
 		c.String(http.StatusBadRequest, "Missing token")
-		return
+		return nil, false
 	}
 
 	secretKey := []byte(config.GetConfig().Secret)
@@ -22,15 +56,13 @@ func (controller *Controller) ResolveIncident(c *gin.Context) {
 	claims, err := magic_link.ParseToken(tokenString, secretKey)
 	if err != nil {
 		c.String(http.StatusUnauthorized, "Invalid or expired token")
-		return
+		return nil, false
 	}
-	log.Printf("[DEBUG] Resolving incident %s for service %d by on-caller %s", claims.IncidentID, claims.ServiceID, claims.OnCaller)
 
-	err = controller.PubSubService.SendOncallerAcknowledgedMessage(c, claims.IncidentID, claims.OnCaller)
-	if err != nil {
-		c.JSON(500, gin.H{"message": "Failed to send on-caller acknowledged message", "error": err.Error()})
-		return
+	if !claims.Allows(action) {
+		c.String(http.StatusForbidden, "Token does not allow this action")
+		return nil, false
 	}
 
-	c.JSON(200, gin.H{"message": "Incident resolved successfully"})
+	return claims, true
 }
@@ -18,6 +18,82 @@ func init() {
 	os.Setenv("REDIS_PREFIX", "test")
 }
 
+func TestAcknowledgeIncident(t *testing.T) {
+	_, _, mockPubSub, _, controller := setupTestRouter()
+
+	testSecret := "test-secret-key-123"
+	config.GetConfig().Secret = testSecret
+
+	incidentID := "INC-123"
+	serviceID := uint64(99)
+	email := "oncaller@example.com"
+
+	t.Run("Success 200", func(t *testing.T) {
+		validToken, _ := magic_link.GenerateToken(magic_link.ActionAcknowledge, incidentID, serviceID, email, []byte(testSecret))
+
+		mockPubSub.On("SendOncallerAcknowledgedMessage", mock.Anything, incidentID, serviceID, email).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/public/incidents/acknowledge/"+validToken, nil)
+		c.Params = gin.Params{gin.Param{Key: "token", Value: validToken}}
+
+		controller.AcknowledgeIncident(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Incident acknowledged successfully")
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Resolve Token 403", func(t *testing.T) {
+		resolveToken, _ := magic_link.GenerateToken(magic_link.ActionResolve, incidentID, serviceID, email, []byte(testSecret))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/public/incidents/acknowledge/"+resolveToken, nil)
+		c.Params = gin.Params{gin.Param{Key: "token", Value: resolveToken}}
+
+		controller.AcknowledgeIncident(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "Token does not allow this action")
+		mockPubSub.AssertNotCalled(t, "SendOncallerResolvedMessage")
+	})
+
+	t.Run("Missing Token 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/public/incidents/acknowledge/", nil)
+		c.Params = gin.Params{gin.Param{Key: "token", Value: ""}}
+
+		controller.AcknowledgeIncident(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Missing token")
+	})
+
+	t.Run("PubSub Error 500", func(t *testing.T) {
+		validToken, _ := magic_link.GenerateToken(magic_link.ActionAcknowledge, incidentID, serviceID, email, []byte(testSecret))
+
+		mockPubSub.On("SendOncallerAcknowledgedMessage", mock.Anything, incidentID, serviceID, email).Return(errors.New("pubsub connection failed")).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/public/incidents/acknowledge/"+validToken, nil)
+		c.Params = gin.Params{gin.Param{Key: "token", Value: validToken}}
+
+		controller.AcknowledgeIncident(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to send on-caller acknowledged message")
+		mockPubSub.AssertExpectations(t)
+	})
+}
+
 func TestResolveIncident(t *testing.T) {
 	_, _, mockPubSub, _, controller := setupTestRouter()
 
@@ -29,9 +105,9 @@ func TestResolveIncident(t *testing.T) {
 	email := "oncaller@example.com"
 
 	t.Run("Success 200", func(t *testing.T) {
-		validToken, _ := magic_link.GenerateToken(incidentID, serviceID, email, []byte(testSecret))
+		validToken, _ := magic_link.GenerateToken(magic_link.ActionResolve, incidentID, serviceID, email, []byte(testSecret))
 
-		mockPubSub.On("SendOncallerAcknowledgedMessage", mock.Anything, incidentID, email).Return(nil).Once()
+		mockPubSub.On("SendOncallerResolvedMessage", mock.Anything, incidentID, serviceID, email).Return(nil).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -46,6 +122,38 @@ func TestResolveIncident(t *testing.T) {
 		mockPubSub.AssertExpectations(t)
 	})
 
+	t.Run("Token Without Action 200", func(t *testing.T) {
+		legacyToken, _ := magic_link.GenerateToken("", incidentID, serviceID, email, []byte(testSecret))
+
+		mockPubSub.On("SendOncallerResolvedMessage", mock.Anything, incidentID, serviceID, email).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/public/incidents/resolve/"+legacyToken, nil)
+		c.Params = gin.Params{gin.Param{Key: "token", Value: legacyToken}}
+
+		controller.ResolveIncident(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Acknowledge Token 403", func(t *testing.T) {
+		acknowledgeToken, _ := magic_link.GenerateToken(magic_link.ActionAcknowledge, incidentID, serviceID, email, []byte(testSecret))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/public/incidents/resolve/"+acknowledgeToken, nil)
+		c.Params = gin.Params{gin.Param{Key: "token", Value: acknowledgeToken}}
+
+		controller.ResolveIncident(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockPubSub.AssertNotCalled(t, "SendOncallerAcknowledgedMessage")
+	})
+
 	t.Run("Missing Token 400", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -57,7 +165,7 @@ func TestResolveIncident(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "Missing token")
-		mockPubSub.AssertNotCalled(t, "SendOncallerAcknowledgedMessage")
+		mockPubSub.AssertNotCalled(t, "SendOncallerResolvedMessage")
 	})
 
 	t.Run("Invalid Token 401", func(t *testing.T) {
@@ -72,12 +180,12 @@ func TestResolveIncident(t *testing.T) {
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 		assert.Contains(t, w.Body.String(), "Invalid or expired token")
-		mockPubSub.AssertNotCalled(t, "SendOncallerAcknowledgedMessage")
+		mockPubSub.AssertNotCalled(t, "SendOncallerResolvedMessage")
 	})
 
 	t.Run("Wrong Secret 401", func(t *testing.T) {
 		wrongSecret := []byte("wrong-secret")
-		forgedToken, _ := magic_link.GenerateToken(incidentID, serviceID, email, wrongSecret)
+		forgedToken, _ := magic_link.GenerateToken(magic_link.ActionResolve, incidentID, serviceID, email, wrongSecret)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -88,13 +196,13 @@ func TestResolveIncident(t *testing.T) {
 		controller.ResolveIncident(c)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		mockPubSub.AssertNotCalled(t, "SendOncallerAcknowledgedMessage")
+		mockPubSub.AssertNotCalled(t, "SendOncallerResolvedMessage")
 	})
 
 	t.Run("PubSub Error 500", func(t *testing.T) {
-		validToken, _ := magic_link.GenerateToken(incidentID, serviceID, email, []byte(testSecret))
+		validToken, _ := magic_link.GenerateToken(magic_link.ActionResolve, incidentID, serviceID, email, []byte(testSecret))
 
-		mockPubSub.On("SendOncallerAcknowledgedMessage", mock.Anything, incidentID, email).Return(errors.New("pubsub connection failed")).Once()
+		mockPubSub.On("SendOncallerResolvedMessage", mock.Anything, incidentID, serviceID, email).Return(errors.New("pubsub connection failed")).Once()
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -105,7 +213,7 @@ func TestResolveIncident(t *testing.T) {
 		controller.ResolveIncident(c)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "Failed to send on-caller acknowledged message")
+		assert.Contains(t, w.Body.String(), "Failed to send on-caller resolved message")
 		mockPubSub.AssertExpectations(t)
 	})
 }
@@ -40,7 +40,9 @@ func RegisterRoutes(r *gin.Engine, authMiddleware *jwt.GinJWTMiddleware) {
 	v1.POST("/login", authMiddleware.LoginHandler)
 	v1.POST("/refresh", authMiddleware.RefreshHandler)
 	v1.POST("/users", controller.RegisterUser)
+	v1.GET("/incidents/acknowledge/:token", controller.AcknowledgeIncident)
 	v1.GET("/incidents/resolve/:token", controller.ResolveIncident)
+	v1.POST("/heartbeat/:token", controller.ReceiveHeartbeat)
 
 	authenticated := v1.Group("/", authMiddleware.MiddlewareFunc())
 	{
@@ -56,6 +58,25 @@ func RegisterRoutes(r *gin.Engine, authMiddleware *jwt.GinJWTMiddleware) {
 			services.GET("/:id/incidents", controller.GetServiceIncidents)
 			services.GET("/:id/metrics", controller.GetServiceStatusMetrics)
 		}
+
+		policies := authenticated.Group("/escalation-policies")
+		{
+			policies.POST("/", controller.CreateEscalationPolicy)
+			policies.GET("/me", controller.GetMyEscalationPolicies)
+			policies.GET("/:id", controller.GetEscalationPolicyByID)
+			policies.PUT("/:id", controller.UpdateEscalationPolicy)
+			policies.DELETE("/:id", controller.DeleteEscalationPolicy)
+		}
+
+		schedules := authenticated.Group("/schedules")
+		{
+			schedules.POST("/", controller.CreateSchedule)
+			schedules.GET("/me", controller.GetMySchedules)
+			schedules.GET("/:id", controller.GetScheduleByID)
+			schedules.GET("/:id/oncall", controller.GetScheduleOnCall)
+			schedules.PUT("/:id", controller.UpdateSchedule)
+			schedules.DELETE("/:id", controller.DeleteSchedule)
+		}
 	}
 }
 
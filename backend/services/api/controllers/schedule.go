@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	"alerting-platform/api/db"
+	"alerting-platform/api/dto"
+	"alerting-platform/api/middleware"
+	"alerting-platform/api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (controller *Controller) CreateSchedule(c *gin.Context) {
+	var scheduleInput dto.ScheduleRequest
+
+	if err := c.ShouldBind(&scheduleInput); err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+
+	schedule := db.Schedule{UserID: jwtUser.ID}
+	utils.MapRequestToSchedule(scheduleInput, &schedule)
+
+	if err := schedule.OnCall().Validate(); err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	err := controller.Repository.CreateSchedule(c.Request.Context(), &schedule)
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to create schedule", "error": err.Error()})
+		return
+	}
+
+	c.JSON(201, gin.H{"message": "Schedule created successfully", "scheduleID": schedule.ID})
+}
+
+func (controller *Controller) GetMySchedules(c *gin.Context) {
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+
+	schedules, err := controller.Repository.GetSchedulesForUser(c.Request.Context(), uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to retrieve schedules", "error": err.Error()})
+		return
+	}
+
+	dtos := make([]dto.ScheduleDTO, 0, len(schedules))
+	for _, schedule := range schedules {
+		dtos = append(dtos, utils.MapScheduleToDTO(schedule))
+	}
+
+	c.JSON(200, dtos)
+}
+
+func (controller *Controller) GetScheduleByID(c *gin.Context) {
+	schedule, ok := controller.getSchedule(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(200, utils.MapScheduleToDTO(*schedule))
+}
+
+// GetScheduleOnCall returns who is on call now and who takes over at the next handoff
+func (controller *Controller) GetScheduleOnCall(c *gin.Context) {
+	schedule, ok := controller.getSchedule(c)
+	if !ok {
+		return
+	}
+
+	onCall := schedule.OnCall()
+	now := onCall.ShiftAt(time.Now().UTC())
+
+	response := dto.OnCallDTO{Now: utils.MapShiftToDTO(now)}
+	if next, exists := onCall.NextShift(now); exists {
+		nextDTO := utils.MapShiftToDTO(next)
+		response.Next = &nextDTO
+	}
+
+	c.JSON(200, response)
+}
+
+// UpdateSchedule also sends the new schedule to the incident manager for every
+// service whose escalation policy uses it
+func (controller *Controller) UpdateSchedule(c *gin.Context) {
+	var scheduleInput dto.ScheduleRequest
+	if err := c.ShouldBind(&scheduleInput); err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	schedule, ok := controller.getSchedule(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	utils.MapRequestToSchedule(scheduleInput, schedule)
+
+	if err := schedule.OnCall().Validate(); err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := controller.Repository.SaveSchedule(ctx, schedule); err != nil {
+		c.JSON(500, gin.H{"message": "Failed to update schedule", "error": err.Error()})
+		return
+	}
+
+	policies, err := controller.Repository.GetEscalationPoliciesBySchedule(ctx, uint64(schedule.ID))
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to retrieve escalation policies using the schedule", "error": err.Error()})
+		return
+	}
+
+	for _, policy := range policies {
+		if err := controller.sendPolicyServicesUpdated(c, &policy); err != nil {
+			c.JSON(500, gin.H{"message": "Failed to send service updated message", "error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(200, gin.H{"message": "Schedule updated successfully"})
+}
+
+// DeleteSchedule refuses to delete schedules still used by escalation policies. Only
+// the owner learns whether the schedule is used.
+func (controller *Controller) DeleteSchedule(c *gin.Context) {
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+	ctx := c.Request.Context()
+
+	scheduleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"message": "Invalid schedule ID", "error": err.Error()})
+		return
+	}
+
+	_, err = controller.Repository.GetScheduleByIDAndUserID(ctx, scheduleID, uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(404, gin.H{"message": "Schedule not found", "error": err.Error()})
+		return
+	}
+
+	policies, err := controller.Repository.GetEscalationPoliciesBySchedule(ctx, scheduleID)
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to retrieve escalation policies using the schedule", "error": err.Error()})
+		return
+	}
+
+	if len(policies) > 0 {
+		c.JSON(409, gin.H{"message": "Schedule is used by " + strconv.Itoa(len(policies)) + " escalation policies"})
+		return
+	}
+
+	rowsAffected, err := controller.Repository.DeleteScheduleForUser(ctx, scheduleID, uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to delete schedule", "error": err.Error()})
+		return
+	}
+
+	if rowsAffected == 0 {
+		c.JSON(404, gin.H{"message": "Schedule not found"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Schedule deleted successfully"})
+}
+
+// getSchedule loads the schedule of the id parameter owned by the user. It writes
+// the error response itself and reports whether to continue.
+func (controller *Controller) getSchedule(c *gin.Context) (*db.Schedule, bool) {
+	userIdentity, exists := c.Get(middleware.IdentityKey)
+	if !exists {
+		c.JSON(500, gin.H{"message": "Failed to get user from context"})
+		return nil, false
+	}
+
+	jwtUser := userIdentity.(*middleware.JWTUser)
+
+	scheduleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"message": "Invalid schedule ID", "error": err.Error()})
+		return nil, false
+	}
+
+	schedule, err := controller.Repository.GetScheduleByIDAndUserID(c.Request.Context(), scheduleID, uint64(jwtUser.ID))
+	if err != nil {
+		c.JSON(404, gin.H{"message": "Schedule not found", "error": err.Error()})
+		return nil, false
+	}
+
+	return schedule, true
+}
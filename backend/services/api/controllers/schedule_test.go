@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"alerting-platform/api/db"
+	"alerting-platform/api/dto"
+	"alerting-platform/api/middleware"
+	"alerting-platform/common/oncall"
+	pubsub_common "alerting-platform/common/pubsub"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateSchedule(t *testing.T) {
+	_, mockRepo, _, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+	scheduleInput := dto.ScheduleRequest{
+		Name:     "Backend weekly",
+		Timezone: "Europe/Warsaw",
+		Layers: []dto.ScheduleLayerDTO{
+			{Users: []string{"first@example.com", "second@example.com"}, Start: "2025-01-06T09:00", RotationDays: 7},
+		},
+		Overrides: []dto.ScheduleOverrideDTO{
+			{User: "holiday@example.com", Start: time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	t.Run("Success 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		jsonValue, _ := json.Marshal(scheduleInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/schedules", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(schedule *db.Schedule) bool {
+			return schedule.UserID == jwtUser.ID && schedule.Timezone == "Europe/Warsaw" &&
+				len(schedule.Layers) == 1 && schedule.Layers[0].RotationDays == 7 && len(schedule.Overrides) == 1
+		})).Return(nil).Once()
+
+		controller.CreateSchedule(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "Schedule created successfully")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid layer start 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := scheduleInput
+		invalidInput.Layers = []dto.ScheduleLayerDTO{{Users: []string{"first@example.com"}, Start: "next monday", RotationDays: 7}}
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/schedules", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateSchedule(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "layer 1: invalid start")
+	})
+
+	t.Run("Unknown timezone 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := scheduleInput
+		invalidInput.Timezone = "Mars/Olympus_Mons"
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/schedules", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateSchedule(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid input")
+	})
+
+	t.Run("Override ending before it starts 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := scheduleInput
+		invalidInput.Overrides = []dto.ScheduleOverrideDTO{
+			{User: "holiday@example.com", Start: time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)},
+		}
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/schedules", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateSchedule(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetScheduleOnCall(t *testing.T) {
+	_, mockRepo, _, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+
+	// Ten days into a weekly rotation, the second user is on call for four more days
+	start := time.Now().UTC().Add(-10 * 24 * time.Hour).Truncate(time.Minute)
+	schedule := &db.Schedule{
+		Model: gorm.Model{ID: 5},
+		Layers: []oncall.Layer{
+			{Users: []string{"first@example.com", "second@example.com"}, Start: start.Format(oncall.LocalTimeLayout), RotationDays: 7},
+		},
+	}
+
+	getOnCall := func(t *testing.T) dto.OnCallDTO {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodGet, "/schedules/5/oncall", nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+
+		mockRepo.On("GetScheduleByIDAndUserID", mock.Anything, uint64(5), uint64(jwtUser.ID)).Return(schedule, nil).Once()
+
+		controller.GetScheduleOnCall(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.OnCallDTO
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("Rotation", func(t *testing.T) {
+		response := getOnCall(t)
+
+		assert.Equal(t, "second@example.com", response.Now.User)
+		if assert.NotNil(t, response.Now.Start) && assert.NotNil(t, response.Now.End) {
+			assert.True(t, start.Add(7*24*time.Hour).Equal(*response.Now.Start))
+			assert.True(t, start.Add(14*24*time.Hour).Equal(*response.Now.End))
+		}
+
+		if assert.NotNil(t, response.Next) {
+			assert.Equal(t, "first@example.com", response.Next.User)
+			assert.True(t, start.Add(21*24*time.Hour).Equal(*response.Next.End))
+		}
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		overrideEnd := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		schedule.Overrides = []oncall.Override{
+			{User: "holiday@example.com", Start: time.Now().UTC().Add(-time.Hour), End: overrideEnd},
+		}
+		defer func() { schedule.Overrides = nil }()
+
+		response := getOnCall(t)
+
+		assert.Equal(t, "holiday@example.com", response.Now.User)
+		if assert.NotNil(t, response.Now.End) {
+			assert.True(t, overrideEnd.Equal(*response.Now.End))
+		}
+
+		// Back to the rotation after the override
+		if assert.NotNil(t, response.Next) {
+			assert.Equal(t, "second@example.com", response.Next.User)
+			assert.True(t, start.Add(14*24*time.Hour).Equal(*response.Next.End))
+		}
+	})
+
+	t.Run("Nobody before the rotation starts", func(t *testing.T) {
+		future := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Minute)
+		schedule.Layers[0].Start = future.Format(oncall.LocalTimeLayout)
+		defer func() { schedule.Layers[0].Start = start.Format(oncall.LocalTimeLayout) }()
+
+		response := getOnCall(t)
+
+		assert.Empty(t, response.Now.User)
+		assert.Nil(t, response.Now.Start)
+		if assert.NotNil(t, response.Next) {
+			assert.Equal(t, "first@example.com", response.Next.User)
+			assert.True(t, future.Equal(*response.Next.Start))
+		}
+	})
+}
+
+func TestUpdateSchedule(t *testing.T) {
+	_, mockRepo, mockPubSub, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+	scheduleInput := dto.ScheduleRequest{
+		Name:   "Backend weekly",
+		Layers: []dto.ScheduleLayerDTO{{Users: []string{"first@example.com"}, Start: "2025-01-06T09:00", RotationDays: 7}},
+	}
+
+	t.Run("Services using the schedule are updated 200", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		jsonValue, _ := json.Marshal(scheduleInput)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/schedules/5", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+
+		schedule := &db.Schedule{Model: gorm.Model{ID: 5}, UserID: jwtUser.ID}
+		policy := db.EscalationPolicy{
+			Model:  gorm.Model{ID: 3},
+			Levels: []pubsub_common.EscalationLevel{{ScheduleIDs: []uint{5}, Timeout: 5}},
+		}
+
+		mockRepo.On("GetScheduleByIDAndUserID", mock.Anything, uint64(5), uint64(jwtUser.ID)).Return(schedule, nil).Once()
+		mockRepo.On("SaveSchedule", mock.Anything, schedule).Return(nil).Once()
+		mockRepo.On("GetEscalationPoliciesBySchedule", mock.Anything, uint64(5)).Return([]db.EscalationPolicy{policy}, nil).Once()
+		mockRepo.On("GetSchedulesByIDs", mock.Anything, []uint{5}).Return([]db.Schedule{{
+			Model:  gorm.Model{ID: 5},
+			Layers: []oncall.Layer{{Users: []string{"first@example.com"}, Start: "2025-01-06T09:00", RotationDays: 7}},
+		}}, nil).Once()
+		mockRepo.On("GetServicesByEscalationPolicy", mock.Anything, uint64(3)).Return([]db.MonitoredService{{Model: gorm.Model{ID: 7}}}, nil).Once()
+		mockPubSub.On("SendServiceUpdatedMessage", mock.Anything, mock.MatchedBy(func(service db.MonitoredService) bool {
+			levels, _ := service.Escalation()
+			return len(levels) == 1 && len(levels[0].Schedules) == 1 && levels[0].Schedules[0].Layers[0].Users[0] == "first@example.com"
+		})).Return(nil).Once()
+
+		controller.UpdateSchedule(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Schedule updated successfully")
+		mockRepo.AssertExpectations(t)
+		mockPubSub.AssertExpectations(t)
+	})
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	_, mockRepo, _, _, controller := setupTestRouter()
+
+	jwtUser := &middleware.JWTUser{ID: 1, Email: "test@user.com"}
+
+	t.Run("Success 200", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/schedules/5", nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+
+		mockRepo.On("GetScheduleByIDAndUserID", mock.Anything, uint64(5), uint64(jwtUser.ID)).Return(&db.Schedule{Model: gorm.Model{ID: 5}, UserID: jwtUser.ID}, nil).Once()
+		mockRepo.On("GetEscalationPoliciesBySchedule", mock.Anything, uint64(5)).Return([]db.EscalationPolicy{}, nil).Once()
+		mockRepo.On("DeleteScheduleForUser", mock.Anything, uint64(5), uint64(jwtUser.ID)).Return(1, nil).Once()
+
+		controller.DeleteSchedule(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Used by escalation policies 409", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/schedules/5", nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+
+		mockRepo.On("GetScheduleByIDAndUserID", mock.Anything, uint64(5), uint64(jwtUser.ID)).Return(&db.Schedule{Model: gorm.Model{ID: 5}, UserID: jwtUser.ID}, nil).Once()
+		mockRepo.On("GetEscalationPoliciesBySchedule", mock.Anything, uint64(5)).Return([]db.EscalationPolicy{{}}, nil).Once()
+
+		controller.DeleteSchedule(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Schedule is used by 1 escalation policies")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Schedule of another user 404", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		otherUser := &middleware.JWTUser{ID: 2, Email: "other@user.com"}
+
+		c.Request, _ = http.NewRequest(http.MethodDelete, "/schedules/5", nil)
+		c.Set(middleware.IdentityKey, otherUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+
+		mockRepo.On("GetScheduleByIDAndUserID", mock.Anything, uint64(5), uint64(otherUser.ID)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		controller.DeleteSchedule(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.NotContains(t, w.Body.String(), "used by")
+		mockRepo.AssertExpectations(t)
+	})
+}
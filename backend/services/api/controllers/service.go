@@ -2,9 +2,21 @@ package controllers
 
 import (
 	"alerting-platform/api/redis"
+	"alerting-platform/common/config"
+	"alerting-platform/common/crontab"
 	db_common "alerting-platform/common/db"
 	"alerting-platform/common/db/firestore"
+	"alerting-platform/common/egress"
+	pubsub_common "alerting-platform/common/pubsub"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"alerting-platform/api/db"
@@ -23,6 +35,11 @@ func (controller *Controller) CreateMonitoredService(c *gin.Context) {
 		return
 	}
 
+	if err := validateServiceInput(c.Request.Context(), serviceInput); err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
 	userIdentity, exists := c.Get(middleware.IdentityKey)
 	if !exists {
 		c.JSON(500, gin.H{"message": "Failed to get user from context"})
@@ -39,16 +56,25 @@ func (controller *Controller) CreateMonitoredService(c *gin.Context) {
 		return
 	}
 
-	service := db.MonitoredService{
-		UserID:              jwtUser.ID,
-		Name:                serviceInput.Name,
-		URL:                 serviceInput.URL,
-		Port:                serviceInput.Port,
-		HealthCheckInterval: serviceInput.HealthCheckInterval,
-		AlertWindow:         serviceInput.AlertWindow,
-		AllowedResponseTime: serviceInput.AllowedResponseTime,
-		FirstOncallerEmail:  serviceInput.FirstOncallerEmail,
-		SecondOncallerEmail: serviceInput.SecondOncallerEmail,
+	policy, ok := controller.getEscalationPolicy(c, serviceInput.EscalationPolicyID, uint64(jwtUser.ID))
+	if !ok {
+		return
+	}
+
+	service := db.MonitoredService{UserID: jwtUser.ID}
+	utils.MapRequestToService(serviceInput, &service)
+
+	if !controller.setAuthSecret(c, &service, serviceInput.AuthSecret) {
+		return
+	}
+
+	if !controller.setHeaders(c, &service, serviceInput.Headers) {
+		return
+	}
+
+	if err := utils.SetHeartbeatToken(&service); err != nil {
+		c.JSON(500, gin.H{"message": "Failed to generate heartbeat token", "error": err.Error()})
+		return
 	}
 
 	err = controller.Repository.CreateService(ctx, &service)
@@ -57,6 +83,7 @@ func (controller *Controller) CreateMonitoredService(c *gin.Context) {
 		return
 	}
 
+	service.EscalationPolicy = policy
 	err = controller.PubSubService.SendServiceCreatedMessage(ctx, service)
 	if err != nil {
 		c.JSON(500, gin.H{"message": "Failed to send service created message", "error": err.Error()})
@@ -145,6 +172,11 @@ func (controller *Controller) UpdateMonitoredService(c *gin.Context) {
 		return
 	}
 
+	if err := validateServiceInput(c.Request.Context(), serviceInput); err != nil {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
 	userIdentity, exists := c.Get(middleware.IdentityKey)
 	if !exists {
 		c.JSON(500, gin.H{"message": "Failed to get user from context"})
@@ -166,17 +198,29 @@ func (controller *Controller) UpdateMonitoredService(c *gin.Context) {
 		return
 	}
 
-	service.Name = serviceInput.Name
-	service.URL = serviceInput.URL
-	service.Port = serviceInput.Port
-	service.HealthCheckInterval = serviceInput.HealthCheckInterval
-	service.AlertWindow = serviceInput.AlertWindow
-	service.AllowedResponseTime = serviceInput.AllowedResponseTime
-	service.FirstOncallerEmail = serviceInput.FirstOncallerEmail
-	service.SecondOncallerEmail = serviceInput.SecondOncallerEmail
+	policy, ok := controller.getEscalationPolicy(c, serviceInput.EscalationPolicyID, uint64(jwtUser.ID))
+	if !ok {
+		return
+	}
+
+	utils.MapRequestToService(serviceInput, service)
+
+	if !controller.setAuthSecret(c, service, serviceInput.AuthSecret) {
+		return
+	}
+
+	if !controller.setHeaders(c, service, serviceInput.Headers) {
+		return
+	}
+
+	if err := utils.SetHeartbeatToken(service); err != nil {
+		c.JSON(500, gin.H{"message": "Failed to generate heartbeat token", "error": err.Error()})
+		return
+	}
 
 	controller.Repository.SaveService(ctx, service)
 
+	service.EscalationPolicy = policy
 	err = controller.PubSubService.SendServiceUpdatedMessage(ctx, *service)
 	if err != nil {
 		c.JSON(500, gin.H{"message": "Failed to send service updated message", "error": err.Error()})
@@ -269,6 +313,154 @@ func (controller *Controller) GetServiceIncidents(c *gin.Context) {
 	c.JSON(200, incidentDTOs)
 }
 
+// validateServiceInput covers rules that binding tags cannot express
+func validateServiceInput(ctx context.Context, input dto.MonitoredServiceRequest) error {
+	switch input.CheckType {
+	case pubsub_common.CheckTypeHeartbeat:
+	case pubsub_common.CheckTypeDNS:
+		// Only the resolvers are connected to, the name itself may point anywhere
+		for _, resolver := range input.DNSResolvers {
+			if err := checkTarget(ctx, egress.AddressHost(resolver)); err != nil {
+				return err
+			}
+		}
+
+		// Workers keep no answers between checks, so changes are only noticed against the expected one
+		if len(input.DNSExpected) == 0 {
+			return errors.New("dns checks need the expected answer")
+		}
+	case pubsub_common.CheckTypeSynthetic:
+		for _, step := range input.Steps {
+			if err := checkTarget(ctx, step.URL); err != nil {
+				return fmt.Errorf("step %s: %w", step.Name, err)
+			}
+
+			if step.BodyRegex != "" {
+				if _, err := regexp.Compile(step.BodyRegex); err != nil {
+					return fmt.Errorf("step %s: invalid body regex: %w", step.Name, err)
+				}
+			}
+		}
+	default:
+		if err := checkTarget(ctx, input.URL); err != nil {
+			return err
+		}
+	}
+
+	if input.BodyRegex != "" {
+		if _, err := regexp.Compile(input.BodyRegex); err != nil {
+			return fmt.Errorf("invalid body regex: %w", err)
+		}
+	}
+
+	if input.CertExpiryDays > 0 {
+		isHTTP := input.CheckType == "" || input.CheckType == pubsub_common.CheckTypeHTTP
+		if !isHTTP || !strings.HasPrefix(strings.ToLower(input.URL), "https://") {
+			return errors.New("certificate expiry monitoring requires an HTTPS check")
+		}
+	}
+
+	for _, location := range input.Locations {
+		if !slices.Contains(config.GetCheckLocations(), location) {
+			return fmt.Errorf("unknown check location: %s", location)
+		}
+	}
+
+	if input.LocationQuorum > max(len(input.Locations), 1) {
+		return errors.New("location quorum cannot exceed the number of locations")
+	}
+
+	if input.Retries > 0 && retryPolicyDuration(input) >= time.Duration(input.HealthCheckInterval)*time.Second {
+		return errors.New("retries must finish within the health check interval")
+	}
+
+	if input.CronSchedule != "" {
+		if input.CheckType == pubsub_common.CheckTypeHeartbeat {
+			return errors.New("heartbeat checks do not support cron schedules")
+		}
+
+		if _, err := crontab.Parse(input.CronSchedule, input.CronTimezone); err != nil {
+			return err
+		}
+	}
+
+	if input.FailingInterval > 0 {
+		if input.CheckType == pubsub_common.CheckTypeHeartbeat {
+			return errors.New("heartbeat checks do not support a failing interval")
+		}
+
+		if input.Retries > 0 && retryPolicyDuration(input) >= time.Duration(input.FailingInterval)*time.Second {
+			return errors.New("retries must finish within the failing interval")
+		}
+	}
+
+	return nil
+}
+
+// checkTarget rejects targets the workers would refuse to connect to. Names that
+// do not resolve are accepted, as workers check the address again on every dial.
+func checkTarget(ctx context.Context, target string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	err := egress.GetPolicy().CheckHost(ctx, egress.Host(target))
+	if errors.Is(err, egress.ErrForbiddenTarget) {
+		return fmt.Errorf("forbidden health check target: %w", err)
+	}
+
+	return nil
+}
+
+// retryPolicyDuration is the longest a check can take with every attempt timing out.
+// Workers wait 10 seconds per attempt unless configured otherwise.
+func retryPolicyDuration(input dto.MonitoredServiceRequest) time.Duration {
+	attemptTimeout := 10 * time.Second
+	if input.AttemptTimeout > 0 {
+		attemptTimeout = time.Duration(input.AttemptTimeout) * time.Second
+	}
+
+	total := time.Duration(input.Retries+1) * attemptTimeout
+	backoff := time.Duration(input.RetryBackoff) * time.Millisecond
+	for range input.Retries {
+		total += backoff
+		backoff *= 2
+	}
+
+	return total
+}
+
+// setAuthSecret writes the error response itself and reports whether to continue
+func (controller *Controller) setAuthSecret(c *gin.Context, service *db.MonitoredService, secret string) bool {
+	err := utils.SetAuthSecret(service, secret)
+	if errors.Is(err, utils.ErrMissingAuthSecret) {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return false
+	}
+
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to encrypt auth secret", "error": err.Error()})
+		return false
+	}
+
+	return true
+}
+
+// setHeaders writes the error response itself and reports whether to continue
+func (controller *Controller) setHeaders(c *gin.Context, service *db.MonitoredService, headers map[string]string) bool {
+	err := utils.SetHeaders(service, headers)
+	if errors.Is(err, utils.ErrMissingHeaderValue) {
+		c.JSON(400, gin.H{"message": "Invalid input", "error": err.Error()})
+		return false
+	}
+
+	if err != nil {
+		c.JSON(500, gin.H{"message": "Failed to encrypt headers", "error": err.Error()})
+		return false
+	}
+
+	return true
+}
+
 var granularities = map[string]time.Duration{
 	"hour":  time.Hour,
 	"day":   24 * time.Hour,
@@ -342,15 +534,57 @@ func aggregateMetrics(metrics []firestore.MetricLog, startTime time.Time) []dto.
 		}
 	}
 
+	latencies := make([][]float64, binCount)
+
 	for _, metric := range metrics {
+		// Gaps are not checks, they only mark the bins they overlap
+		if metric.Type == pubsub_common.StatusNoData {
+			first := max(int(metric.GapSince.Sub(startTime)/binDuration), 0)
+			last := min(int(metric.Timestamp.Sub(startTime)/binDuration), binCount-1)
+			for i := first; i <= last; i++ {
+				bins[i].NoData = true
+			}
+			continue
+		}
+
 		binIndex := int(metric.Timestamp.Sub(startTime) / binDuration)
 		if binIndex >= 0 && binIndex < binCount {
 			bins[binIndex].Total++
 			if metric.Type == "UP" {
 				bins[binIndex].Success++
 			}
+			if metric.Attempts > 1 {
+				bins[binIndex].Retried++
+			}
+			// Older logs carry no latency
+			if metric.ResponseTimeMs > 0 {
+				latencies[binIndex] = append(latencies[binIndex], metric.ResponseTimeMs)
+			}
 		}
 	}
 
+	for i := range bins {
+		if len(latencies[i]) == 0 {
+			continue
+		}
+
+		avg, p95 := latencyStats(latencies[i])
+		bins[i].AvgLatencyMs = &avg
+		bins[i].P95LatencyMs = &p95
+	}
+
 	return bins
 }
+
+// latencyStats uses the nearest-rank percentile, so p95 is always an observed value
+func latencyStats(latencies []float64) (float64, float64) {
+	sort.Float64s(latencies)
+
+	sum := 0.0
+	for _, latency := range latencies {
+		sum += latency
+	}
+
+	rank := int(math.Ceil(0.95*float64(len(latencies)))) - 1
+	return sum / float64(len(latencies)), latencies[rank]
+}
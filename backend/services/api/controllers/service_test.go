@@ -6,8 +6,11 @@ import (
 	"alerting-platform/api/middleware"
 	"alerting-platform/api/pubsub"
 	"alerting-platform/api/redis"
+	"alerting-platform/common/config"
 	db_common "alerting-platform/common/db"
 	"alerting-platform/common/db/firestore"
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/secrets"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -157,6 +160,629 @@ func TestCreateMonitoredService(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 		mockPubSub.AssertExpectations(t)
 	})
+
+	t.Run("Defaults to HTTP check 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		jsonValue, _ := json.Marshal(serviceInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetServiceByName", mock.Anything, serviceInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("CreateService", mock.Anything, mock.MatchedBy(func(s *db.MonitoredService) bool {
+			return s.CheckType == "http"
+		})).Return(nil).Once()
+		mockPubSub.On("SendServiceCreatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("TCP check 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		tcpInput := serviceInput
+		tcpInput.URL = "tcp://db.example.com"
+		tcpInput.Port = 5432
+		tcpInput.CheckType = "tcp"
+
+		jsonValue, _ := json.Marshal(tcpInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetServiceByName", mock.Anything, tcpInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("CreateService", mock.Anything, mock.MatchedBy(func(s *db.MonitoredService) bool {
+			return s.CheckType == "tcp" && s.Port == 5432
+		})).Return(nil).Once()
+		mockPubSub.On("SendServiceCreatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("DNS check 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		dnsInput := serviceInput
+		dnsInput.Name = "Shop DNS"
+		dnsInput.URL = "dns://shop.example.com"
+		dnsInput.Port = 0
+		dnsInput.CheckType = "dns"
+		dnsInput.DNSRecordType = "A"
+		dnsInput.DNSResolvers = []string{"1.1.1.1", "8.8.8.8:53"}
+		dnsInput.DNSExpected = []string{"203.0.113.10"}
+
+		jsonValue, _ := json.Marshal(dnsInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetServiceByName", mock.Anything, dnsInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("CreateService", mock.Anything, mock.MatchedBy(func(s *db.MonitoredService) bool {
+			return s.CheckType == "dns" && s.DNSRecordType == "A" && len(s.DNSResolvers) == 2
+		})).Return(nil).Once()
+		mockPubSub.On("SendServiceCreatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Private DNS resolver 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.URL = "dns://shop.example.com"
+		invalidInput.Port = 0
+		invalidInput.CheckType = "dns"
+		invalidInput.DNSResolvers = []string{"10.96.0.10:53"}
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "forbidden health check target")
+	})
+
+	t.Run("DNS check without expected answer 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.URL = "dns://shop.example.com"
+		invalidInput.Port = 0
+		invalidInput.CheckType = "dns"
+		invalidInput.DNSRecordType = "A"
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "dns checks need the expected answer")
+	})
+
+	t.Run("Synthetic check 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		syntheticInput := serviceInput
+		syntheticInput.Name = "Shop checkout"
+		syntheticInput.URL = ""
+		syntheticInput.Port = 0
+		syntheticInput.CheckType = "synthetic"
+		syntheticInput.Steps = []dto.SyntheticStepDTO{
+			{
+				Name:    "login",
+				Method:  "POST",
+				URL:     "https://shop.example.com/login",
+				Body:    `{"user":"probe"}`,
+				Extract: []dto.VariableExtractionDTO{{Name: "token", JSONPath: "$.token"}},
+			},
+			{
+				Name:         "orders",
+				URL:          "https://shop.example.com/orders",
+				Headers:      map[string]string{"Authorization": "Bearer {{token}}"},
+				BodyContains: "orders",
+			},
+		}
+
+		jsonValue, _ := json.Marshal(syntheticInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetServiceByName", mock.Anything, syntheticInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("CreateService", mock.Anything, mock.MatchedBy(func(s *db.MonitoredService) bool {
+			return s.CheckType == "synthetic" && len(s.Steps) == 2 && s.Steps[0].Extract[0].JSONPath == "$.token"
+		})).Return(nil).Once()
+		mockPubSub.On("SendServiceCreatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Synthetic check without steps 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.CheckType = "synthetic"
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid input")
+	})
+
+	t.Run("Private synthetic step 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.CheckType = "synthetic"
+		invalidInput.Steps = []dto.SyntheticStepDTO{
+			{Name: "login", URL: "https://shop.example.com/login"},
+			{Name: "admin", URL: "http://10.0.0.5/admin"},
+		}
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "step admin: forbidden health check target")
+	})
+
+	t.Run("Unknown check type 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.CheckType = "udp"
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid input")
+	})
+
+	t.Run("Invalid body regex 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.BodyRegex = "status: ("
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid body regex")
+	})
+
+	t.Run("Certificate expiry without HTTPS 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.URL = "http://example.com"
+		invalidInput.CertExpiryDays = 14
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "requires an HTTPS check")
+	})
+
+	t.Run("Too many recovery checks 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.RecoveryChecks = 11
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "RecoveryChecks")
+	})
+
+	t.Run("Metadata server target 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.URL = "http://169.254.169.254/computeMetadata/v1/"
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "forbidden health check target")
+	})
+
+	t.Run("Unknown location 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.Locations = []string{"default", "mars"}
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown check location: mars")
+	})
+
+	t.Run("Location quorum above locations 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.Locations = []string{"default"}
+		invalidInput.LocationQuorum = 2
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "location quorum cannot exceed")
+	})
+
+	t.Run("Retries longer than interval 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		// 3 attempts of 20 seconds already fill the 60 second interval
+		invalidInput := serviceInput
+		invalidInput.Retries = 2
+		invalidInput.RetryBackoff = 500
+		invalidInput.AttemptTimeout = 20
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "retries must finish within the health check interval")
+	})
+
+	t.Run("Failing interval above interval 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.FailingInterval = invalidInput.HealthCheckInterval
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid input")
+	})
+
+	t.Run("Retries longer than failing interval 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		// 3 attempts of 5 seconds fit the interval, but not the 10 second failing interval
+		invalidInput := serviceInput
+		invalidInput.Retries = 2
+		invalidInput.AttemptTimeout = 5
+		invalidInput.FailingInterval = 10
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "retries must finish within the failing interval")
+	})
+
+	t.Run("Cron schedule 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		cronInput := serviceInput
+		cronInput.Name = "Business hours"
+		cronInput.CronSchedule = "*/5 9-17 * * 1-5"
+		cronInput.CronTimezone = "Europe/Warsaw"
+
+		jsonValue, _ := json.Marshal(cronInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetServiceByName", mock.Anything, cronInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("CreateService", mock.Anything, mock.MatchedBy(func(s *db.MonitoredService) bool {
+			return s.CronSchedule == "*/5 9-17 * * 1-5" && s.CronTimezone == "Europe/Warsaw"
+		})).Return(nil).Once()
+		mockPubSub.On("SendServiceCreatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid cron expression 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.CronSchedule = "*/5 9-17 * *"
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid cron expression")
+	})
+
+	t.Run("Unknown timezone 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.CronSchedule = "@daily"
+		invalidInput.CronTimezone = "Mars/Olympus_Mons"
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid input")
+	})
+
+	t.Run("Escalation policy 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		policyID := uint(3)
+		policyInput := serviceInput
+		policyInput.Name = "Escalated"
+		policyInput.FirstOncallerEmail = ""
+		policyInput.EscalationPolicyID = &policyID
+
+		jsonValue, _ := json.Marshal(policyInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		policy := &db.EscalationPolicy{
+			Model:  gorm.Model{ID: 3},
+			Levels: []pubsub_common.EscalationLevel{{Targets: []string{"first@example.com"}, Timeout: 5}},
+		}
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(3), uint64(jwtUser.ID)).Return(policy, nil).Once()
+		mockRepo.On("GetSchedulesByIDs", mock.Anything, []uint(nil)).Return(nil, nil).Once()
+		mockRepo.On("GetServiceByName", mock.Anything, policyInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("CreateService", mock.Anything, mock.MatchedBy(func(s *db.MonitoredService) bool {
+			return s.EscalationPolicyID != nil && *s.EscalationPolicyID == 3 && s.EscalationPolicy == nil
+		})).Return(nil).Once()
+		mockPubSub.On("SendServiceCreatedMessage", mock.Anything, mock.MatchedBy(func(s db.MonitoredService) bool {
+			levels, _ := s.Escalation()
+			return len(levels) == 1 && len(s.Oncallers()) == 0
+		})).Return(nil).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Escalation policy of another user 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		policyID := uint(4)
+		invalidInput := serviceInput
+		invalidInput.EscalationPolicyID = &policyID
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetServiceByName", mock.Anything, invalidInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("GetEscalationPolicyByIDAndUserID", mock.Anything, uint64(4), uint64(jwtUser.ID)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "escalation policy not found")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Neither oncaller nor escalation policy 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.FirstOncallerEmail = ""
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid input")
+	})
+
+	t.Run("Heartbeat service gets a token 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		heartbeatInput := serviceInput
+		heartbeatInput.Name = "Nightly Backup"
+		heartbeatInput.URL = ""
+		heartbeatInput.Port = 0
+		heartbeatInput.CheckType = pubsub_common.CheckTypeHeartbeat
+		heartbeatInput.HeartbeatGrace = 120
+
+		jsonValue, _ := json.Marshal(heartbeatInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		var created *db.MonitoredService
+		mockRepo.On("GetServiceByName", mock.Anything, heartbeatInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("CreateService", mock.Anything, mock.AnythingOfType("*db.MonitoredService")).Run(func(args mock.Arguments) {
+			created = args.Get(1).(*db.MonitoredService)
+		}).Return(nil).Once()
+		mockPubSub.On("SendServiceCreatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		if assert.NotNil(t, created) && assert.NotNil(t, created.HeartbeatToken) {
+			assert.Len(t, *created.HeartbeatToken, 64)
+			assert.Equal(t, 120, created.HeartbeatGrace)
+		}
+	})
+
+	t.Run("Auth secret is stored encrypted 201", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		authInput := serviceInput
+		authInput.Method = "POST"
+		authInput.Headers = map[string]string{"X-Request-Source": "alerting-platform"}
+		authInput.AuthType = "bearer"
+		authInput.AuthSecret = "super-secret-token"
+
+		jsonValue, _ := json.Marshal(authInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		var stored *db.MonitoredService
+		mockRepo.On("GetServiceByName", mock.Anything, authInput.Name).Return(nil, errors.New("not found")).Once()
+		mockRepo.On("CreateService", mock.Anything, mock.AnythingOfType("*db.MonitoredService")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*db.MonitoredService)
+		}).Return(nil).Once()
+		mockPubSub.On("SendServiceCreatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "POST", stored.Method)
+		assert.NotContains(t, stored.AuthSecret, "super-secret-token")
+
+		decrypted, err := secrets.Decrypt(stored.AuthSecret, config.GetSecretsKey())
+		assert.NoError(t, err)
+		assert.Equal(t, "super-secret-token", decrypted)
+
+		assert.NotContains(t, stored.Headers["X-Request-Source"], "alerting-platform")
+		decrypted, err = secrets.Decrypt(stored.Headers["X-Request-Source"], config.GetSecretsKey())
+		assert.NoError(t, err)
+		assert.Equal(t, "alerting-platform", decrypted)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Missing auth secret 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		authInput := serviceInput
+		authInput.AuthType = "basic"
+		authInput.AuthUsername = "monitor"
+
+		jsonValue, _ := json.Marshal(authInput)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/services", bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+
+		mockRepo.On("GetServiceByName", mock.Anything, authInput.Name).Return(nil, errors.New("not found")).Once()
+
+		controller.CreateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "authSecret is required")
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestUpdateMonitoredService(t *testing.T) {
@@ -197,6 +823,100 @@ func TestUpdateMonitoredService(t *testing.T) {
 		mockPubSub.AssertExpectations(t)
 	})
 
+	t.Run("Empty auth secret keeps stored one 200", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		authInput := serviceInput
+		authInput.AuthType = "bearer"
+
+		jsonValue, _ := json.Marshal(authInput)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/services/"+serviceID, bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: serviceID}}
+
+		authService := &db.MonitoredService{Model: gorm.Model{ID: 1}, UserID: 1, AuthType: "bearer", AuthSecret: "encrypted-secret"}
+		mockRepo.On("GetServiceByIDAndUserID", mock.Anything, uint64(1), uint64(jwtUser.ID)).Return(authService, nil).Once()
+		mockRepo.On("SaveService", mock.Anything, mock.MatchedBy(func(s *db.MonitoredService) bool {
+			return s.AuthSecret == "encrypted-secret"
+		})).Return().Once()
+		mockPubSub.On("SendServiceUpdatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.UpdateMonitoredService(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Empty header value keeps stored one 200", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		headerInput := serviceInput
+		headerInput.Headers = map[string]string{"x-api-key": "", "X-Source": "alerting"}
+
+		jsonValue, _ := json.Marshal(headerInput)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/services/"+serviceID, bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: serviceID}}
+
+		headerService := &db.MonitoredService{Model: gorm.Model{ID: 1}, UserID: 1, Headers: map[string]string{"X-Api-Key": "encrypted-key", "X-Removed": "encrypted"}}
+		mockRepo.On("GetServiceByIDAndUserID", mock.Anything, uint64(1), uint64(jwtUser.ID)).Return(headerService, nil).Once()
+		mockRepo.On("SaveService", mock.Anything, mock.MatchedBy(func(s *db.MonitoredService) bool {
+			return len(s.Headers) == 2 && s.Headers["X-Api-Key"] == "encrypted-key" && s.Headers["X-Source"] != "alerting"
+		})).Return().Once()
+		mockPubSub.On("SendServiceUpdatedMessage", mock.Anything, mock.AnythingOfType("db.MonitoredService")).Return(nil).Once()
+
+		controller.UpdateMonitoredService(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Empty value of new header 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		headerInput := serviceInput
+		headerInput.Headers = map[string]string{"X-Api-Key": ""}
+
+		jsonValue, _ := json.Marshal(headerInput)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/services/"+serviceID, bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: serviceID}}
+
+		mockRepo.On("GetServiceByIDAndUserID", mock.Anything, uint64(1), uint64(jwtUser.ID)).Return(&db.MonitoredService{Model: gorm.Model{ID: 1}, UserID: 1}, nil).Once()
+
+		controller.UpdateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "header values are required for new headers: X-Api-Key")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Private TCP target 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		invalidInput := serviceInput
+		invalidInput.CheckType = pubsub_common.CheckTypeTCP
+		invalidInput.URL = "tcp://10.0.0.5"
+
+		jsonValue, _ := json.Marshal(invalidInput)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/services/"+serviceID, bytes.NewBuffer(jsonValue))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: serviceID}}
+
+		controller.UpdateMonitoredService(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "forbidden health check target")
+	})
+
 	t.Run("Invalid Input 400", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -532,6 +1252,30 @@ func TestGetMonitoredServiceByID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Header values are not returned", func(t *testing.T) {
+		_, mockRepo, _, _, controller := setupTestRouter()
+		s := setupRedis(t)
+		defer s.Close()
+
+		headerService := &db.MonitoredService{Model: gorm.Model{ID: 1}, UserID: 1, Headers: map[string]string{"X-Source": "encrypted-source", "X-Api-Key": "encrypted-key"}}
+		mockRepo.On("GetServiceByIDAndUserID", mock.Anything, uint64(1), uint64(jwtUser.ID)).Return(headerService, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/services/"+serviceID, nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: serviceID}}
+
+		controller.GetMonitoredServiceByID(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "encrypted-")
+		var result dto.MonitoredServiceDTO
+		json.Unmarshal(w.Body.Bytes(), &result)
+		assert.Equal(t, []string{"X-Api-Key", "X-Source"}, result.HeaderNames)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Success 200 without status", func(t *testing.T) {
 		_, mockRepo, _, _, controller := setupTestRouter()
 		s := setupRedis(t)
@@ -554,6 +1298,36 @@ func TestGetMonitoredServiceByID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Auth secret is never returned", func(t *testing.T) {
+		_, mockRepo, _, _, controller := setupTestRouter()
+		s := setupRedis(t)
+		defer s.Close()
+
+		authService := *service
+		authService.AuthType = "basic"
+		authService.AuthUsername = "monitor"
+		authService.AuthSecret = "encrypted-secret"
+
+		mockRepo.On("GetServiceByIDAndUserID", mock.Anything, uint64(1), uint64(jwtUser.ID)).Return(&authService, nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/services/"+serviceID, nil)
+		c.Set(middleware.IdentityKey, jwtUser)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: serviceID}}
+
+		controller.GetMonitoredServiceByID(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "encrypted-secret")
+
+		var result dto.MonitoredServiceDTO
+		json.Unmarshal(w.Body.Bytes(), &result)
+		assert.Equal(t, "monitor", result.AuthUsername)
+		assert.True(t, result.HasAuthSecret)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Invalid Service ID 400", func(t *testing.T) {
 		_, _, _, _, controller := setupTestRouter()
 		s := setupRedis(t)
@@ -687,3 +1461,67 @@ func TestGetServiceStatusMetrics(t *testing.T) {
 		mockLogRepo.AssertExpectations(t)
 	})
 }
+
+func TestAggregateMetricsLatency(t *testing.T) {
+	startTime := time.Now().UTC().Add(-time.Hour)
+	inFirstBin := startTime.Add(10 * time.Second)
+	inLastBin := time.Now().UTC().Add(-10 * time.Second)
+
+	metrics := []firestore.MetricLog{
+		{ServiceID: 1, Type: "UP", Timestamp: inLastBin},
+	}
+	for i := 1; i <= 20; i++ {
+		metrics = append(metrics, firestore.MetricLog{ServiceID: 1, Type: "UP", Timestamp: inFirstBin, ResponseTimeMs: float64(i * 10)})
+	}
+
+	bins := aggregateMetrics(metrics, startTime)
+
+	assert.Equal(t, uint(20), bins[0].Total)
+	if assert.NotNil(t, bins[0].AvgLatencyMs) && assert.NotNil(t, bins[0].P95LatencyMs) {
+		assert.InDelta(t, 105.0, *bins[0].AvgLatencyMs, 0.001)
+		assert.InDelta(t, 190.0, *bins[0].P95LatencyMs, 0.001)
+	}
+
+	// Logs without latency count towards availability only
+	last := bins[len(bins)-1]
+	assert.Equal(t, uint(1), last.Total)
+	assert.Nil(t, last.AvgLatencyMs)
+	assert.Nil(t, last.P95LatencyMs)
+}
+
+func TestAggregateMetricsRetried(t *testing.T) {
+	startTime := time.Now().UTC().Add(-time.Hour)
+	inFirstBin := startTime.Add(10 * time.Second)
+
+	metrics := []firestore.MetricLog{
+		{ServiceID: 1, Type: "UP", Timestamp: inFirstBin},
+		{ServiceID: 1, Type: "UP", Timestamp: inFirstBin, Attempts: 1},
+		{ServiceID: 1, Type: "UP", Timestamp: inFirstBin, Attempts: 2},
+		{ServiceID: 1, Type: "DOWN", Timestamp: inFirstBin, Attempts: 4},
+	}
+
+	bins := aggregateMetrics(metrics, startTime)
+
+	assert.Equal(t, uint(4), bins[0].Total)
+	assert.Equal(t, uint(2), bins[0].Retried)
+}
+
+func TestAggregateMetricsNoData(t *testing.T) {
+	startTime := time.Now().UTC().Add(-50 * time.Minute)
+	binDuration := time.Minute
+
+	metrics := []firestore.MetricLog{
+		{ServiceID: 1, Type: "UP", Timestamp: startTime.Add(30 * time.Second)},
+		{ServiceID: 1, Type: pubsub_common.StatusNoData, GapSince: startTime.Add(10*binDuration + time.Second), Timestamp: startTime.Add(12*binDuration + time.Second)},
+		{ServiceID: 1, Type: pubsub_common.StatusNoData, GapSince: startTime.Add(-time.Hour), Timestamp: startTime.Add(time.Second)},
+	}
+
+	bins := aggregateMetrics(metrics, startTime)
+
+	assert.True(t, bins[0].NoData)
+	assert.Equal(t, uint(1), bins[0].Total)
+	for i := 1; i < len(bins); i++ {
+		assert.Equal(t, i >= 10 && i <= 12, bins[i].NoData, "bin %d", i)
+		assert.Zero(t, bins[i].Total)
+	}
+}
@@ -31,6 +31,14 @@ func (m *MockRepository) GetServicesForUser(ctx context.Context, userID uint64)
 	return args.Get(0).([]MonitoredService), args.Error(1)
 }
 
+func (m *MockRepository) GetServiceByID(ctx context.Context, serviceID uint64) (*MonitoredService, error) {
+	args := m.Called(ctx, serviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*MonitoredService), args.Error(1)
+}
+
 func (m *MockRepository) GetServiceByIDAndUserID(ctx context.Context, serviceID uint64, userID uint64) (*MonitoredService, error) {
 	args := m.Called(ctx, serviceID, userID)
 	if args.Get(0) == nil {
@@ -39,6 +47,14 @@ func (m *MockRepository) GetServiceByIDAndUserID(ctx context.Context, serviceID
 	return args.Get(0).(*MonitoredService), args.Error(1)
 }
 
+func (m *MockRepository) GetServiceByHeartbeatToken(ctx context.Context, token string) (*MonitoredService, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*MonitoredService), args.Error(1)
+}
+
 func (m *MockRepository) CreateService(ctx context.Context, service *MonitoredService) error {
 	args := m.Called(ctx, service)
 	return args.Error(0)
@@ -60,3 +76,89 @@ func (m *MockRepository) GetAllServices(ctx context.Context) ([]MonitoredService
 	}
 	return args.Get(0).([]MonitoredService), args.Error(1)
 }
+
+func (m *MockRepository) GetServicesByEscalationPolicy(ctx context.Context, policyID uint64) ([]MonitoredService, error) {
+	args := m.Called(ctx, policyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]MonitoredService), args.Error(1)
+}
+
+func (m *MockRepository) GetEscalationPoliciesForUser(ctx context.Context, userID uint64) ([]EscalationPolicy, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]EscalationPolicy), args.Error(1)
+}
+
+func (m *MockRepository) GetEscalationPolicyByIDAndUserID(ctx context.Context, policyID uint64, userID uint64) (*EscalationPolicy, error) {
+	args := m.Called(ctx, policyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*EscalationPolicy), args.Error(1)
+}
+
+func (m *MockRepository) CreateEscalationPolicy(ctx context.Context, policy *EscalationPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveEscalationPolicy(ctx context.Context, policy *EscalationPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteEscalationPolicyForUser(ctx context.Context, policyID uint64, userID uint64) (int, error) {
+	args := m.Called(ctx, policyID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) GetEscalationPoliciesBySchedule(ctx context.Context, scheduleID uint64) ([]EscalationPolicy, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]EscalationPolicy), args.Error(1)
+}
+
+func (m *MockRepository) GetSchedulesForUser(ctx context.Context, userID uint64) ([]Schedule, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Schedule), args.Error(1)
+}
+
+func (m *MockRepository) GetScheduleByIDAndUserID(ctx context.Context, scheduleID uint64, userID uint64) (*Schedule, error) {
+	args := m.Called(ctx, scheduleID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Schedule), args.Error(1)
+}
+
+func (m *MockRepository) GetSchedulesByIDs(ctx context.Context, scheduleIDs []uint) ([]Schedule, error) {
+	args := m.Called(ctx, scheduleIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Schedule), args.Error(1)
+}
+
+func (m *MockRepository) CreateSchedule(ctx context.Context, schedule *Schedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveSchedule(ctx context.Context, schedule *Schedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteScheduleForUser(ctx context.Context, scheduleID uint64, userID uint64) (int, error) {
+	args := m.Called(ctx, scheduleID, userID)
+	return args.Int(0), args.Error(1)
+}
@@ -1,6 +1,10 @@
 package db
 
 import (
+	"alerting-platform/common/oncall"
+	pubsub_common "alerting-platform/common/pubsub"
+	"slices"
+
 	"gorm.io/gorm"
 )
 
@@ -12,18 +16,133 @@ type User struct {
 
 type MonitoredService struct {
 	gorm.Model
-	UserID              uint   `gorm:"not null;index"`
-	User                User   `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE;"`
-	Name                string `gorm:"not null;unique"`
-	URL                 string `gorm:"not null"`
-	Port                int    `gorm:"not null"`
-	HealthCheckInterval int    `gorm:"not null"` // in seconds
-	AlertWindow         int    `gorm:"not null"` // in seconds
-	AllowedResponseTime int    `gorm:"not null"` // in minutes
-	FirstOncallerEmail  string `gorm:"not null"`
+	UserID              uint    `gorm:"not null;index"`
+	User                User    `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE;"`
+	Name                string  `gorm:"not null;unique"`
+	URL                 string  `gorm:"not null"`
+	Port                int     `gorm:"not null"`
+	CheckType           string  `gorm:"not null;default:http"`
+	HeartbeatToken      *string `gorm:"uniqueIndex"` // heartbeat checks only
+	HeartbeatGrace      int     // in seconds
+	GRPCServiceName     string
+	GRPCUseTLS          bool
+	GRPCTimeout         int // in seconds
+	DNSRecordType       string
+	DNSResolvers        []string                      `gorm:"type:jsonb;serializer:json"` // system resolver of the worker if empty
+	DNSExpected         []string                      `gorm:"type:jsonb;serializer:json"` // any answer if empty
+	Steps               []pubsub_common.SyntheticStep `gorm:"type:jsonb;serializer:json"` // synthetic checks only
+	ExpectedStatusCodes []int                         `gorm:"type:jsonb;serializer:json"` // any 2xx if empty
+	BodyContains        string
+	BodyRegex           string
+	JSONPath            string
+	JSONPathEquals      string
+	Method              string            `gorm:"not null;default:GET"`
+	Headers             map[string]string `gorm:"type:jsonb;serializer:json"` // values encrypted, see common/secrets
+	RequestBody         string
+	AuthType            string
+	AuthUsername        string
+	AuthSecret          string   // encrypted, see common/secrets
+	Locations           []string `gorm:"type:jsonb;serializer:json"` // default location only if empty
+	LocationQuorum      int      // locations that must agree on DOWN, majority if 0
+	Retries             int      // extra attempts before a check fails
+	RetryBackoff        int      // in milliseconds, doubled after every retry
+	AttemptTimeout      int      // in seconds, worker default if 0
+	HealthCheckInterval int      `gorm:"not null"` // in seconds
+	FailingInterval     int      // in seconds, used while the service is failing, 0 disables
+	CronSchedule        string   // replaces the interval as the check schedule if set
+	CronTimezone        string   // UTC if empty
+	AlertWindow         int      `gorm:"not null"` // in seconds
+	AllowedResponseTime int      `gorm:"not null"` // in minutes
+	LatencyThreshold    int      // in milliseconds, 0 disables degraded incidents
+	CertExpiryDays      int      // warn this many days before the certificate expires, 0 disables
+	AutoResolve         bool     // resolve open incidents when the service recovers
+	RecoveryChecks      int      // consecutive healthy results before the service counts as recovered, 1 if 0
+	NotifyRecovery      bool     // tell the oncallers of open incidents when the service recovers
+	FlapWindow          int      // recent results checked for flapping, incident manager default if 0
+	FlapThreshold       int      // percentage of changes between them that counts as flapping, incident manager default if 0
+	FirstOncallerEmail  string   `gorm:"not null"` // empty when an escalation policy is set
 	SecondOncallerEmail *string
+	EscalationPolicyID  *uint             `gorm:"index"` // replaces the oncallers if set
+	EscalationPolicy    *EscalationPolicy `gorm:"foreignKey:EscalationPolicyID;references:ID;constraint:OnDelete:RESTRICT;"`
 	// FirstOncallerID     uint   `gorm:"not null"`
 	// FirstOncaller       User   `gorm:"foreignKey:FirstOncallerID;references:ID"`
 	// SecondOncallerID    *uint  `gorm:"index"`
 	// SecondOncaller      *User  `gorm:"foreignKey:SecondOncallerID;references:ID"`
 }
+
+// EscalationPolicy notifies its levels in order until the incident is acknowledged
+type EscalationPolicy struct {
+	gorm.Model
+	UserID uint                            `gorm:"not null;index"`
+	User   User                            `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE;"`
+	Name   string                          `gorm:"not null"`
+	Levels []pubsub_common.EscalationLevel `gorm:"type:jsonb;serializer:json"`
+	Repeat int                             // times the whole policy is repeated after the last level
+
+	Schedules []Schedule `gorm:"-"` // loaded before the levels are sent, see MonitoredService.Escalation
+}
+
+// Schedule resolves to whoever is on call when an escalation level using it is notified
+type Schedule struct {
+	gorm.Model
+	UserID    uint              `gorm:"not null;index"`
+	User      User              `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE;"`
+	Name      string            `gorm:"not null"`
+	Timezone  string            // UTC if empty
+	Layers    []oncall.Layer    `gorm:"type:jsonb;serializer:json"`
+	Overrides []oncall.Override `gorm:"type:jsonb;serializer:json"`
+}
+
+func (s Schedule) OnCall() oncall.Schedule {
+	return oncall.Schedule{
+		Timezone:  s.Timezone,
+		Layers:    s.Layers,
+		Overrides: s.Overrides,
+	}
+}
+
+// ScheduleIDs returns the schedules used by any level of the policy
+func (p EscalationPolicy) ScheduleIDs() []uint {
+	var ids []uint
+	for _, level := range p.Levels {
+		for _, id := range level.ScheduleIDs {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// Oncallers returns the first and second oncaller of services without an escalation policy
+func (s MonitoredService) Oncallers() []string {
+	var oncallers []string
+	if s.FirstOncallerEmail != "" {
+		oncallers = append(oncallers, s.FirstOncallerEmail)
+	}
+	if s.SecondOncallerEmail != nil {
+		oncallers = append(oncallers, *s.SecondOncallerEmail)
+	}
+	return oncallers
+}
+
+// Escalation returns the levels and repeat of the service's policy, none if the
+// service has no policy or it was not loaded. Levels carry their loaded schedules.
+func (s MonitoredService) Escalation() ([]pubsub_common.EscalationLevel, int) {
+	if s.EscalationPolicy == nil {
+		return nil, 0
+	}
+
+	levels := make([]pubsub_common.EscalationLevel, len(s.EscalationPolicy.Levels))
+	for i, level := range s.EscalationPolicy.Levels {
+		level.Schedules = nil
+		for _, schedule := range s.EscalationPolicy.Schedules {
+			if slices.Contains(level.ScheduleIDs, schedule.ID) {
+				level.Schedules = append(level.Schedules, schedule.OnCall())
+			}
+		}
+		levels[i] = level
+	}
+
+	return levels, s.EscalationPolicy.Repeat
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"fmt"
 
 	"gorm.io/gorm"
 )
@@ -9,12 +10,27 @@ import (
 type RepositoryI interface {
 	GetServiceByName(ctx context.Context, name string) (*MonitoredService, error)
 	GetServicesForUser(ctx context.Context, userID uint64) ([]MonitoredService, error)
+	GetServiceByID(ctx context.Context, serviceID uint64) (*MonitoredService, error)
 	GetServiceByIDAndUserID(ctx context.Context, serviceID uint64, userID uint64) (*MonitoredService, error)
+	GetServiceByHeartbeatToken(ctx context.Context, token string) (*MonitoredService, error)
 	GetAllServices(ctx context.Context) ([]MonitoredService, error)
 	CreateService(ctx context.Context, service *MonitoredService) error
 	SaveService(ctx context.Context, service *MonitoredService)
 	DeleteServiceForUser(ctx context.Context, serviceID uint64, userID uint64) (int, error)
+	GetServicesByEscalationPolicy(ctx context.Context, policyID uint64) ([]MonitoredService, error)
 	CreateUser(ctx context.Context, user *User) error
+	GetEscalationPoliciesForUser(ctx context.Context, userID uint64) ([]EscalationPolicy, error)
+	GetEscalationPolicyByIDAndUserID(ctx context.Context, policyID uint64, userID uint64) (*EscalationPolicy, error)
+	CreateEscalationPolicy(ctx context.Context, policy *EscalationPolicy) error
+	SaveEscalationPolicy(ctx context.Context, policy *EscalationPolicy) error
+	DeleteEscalationPolicyForUser(ctx context.Context, policyID uint64, userID uint64) (int, error)
+	GetEscalationPoliciesBySchedule(ctx context.Context, scheduleID uint64) ([]EscalationPolicy, error)
+	GetSchedulesForUser(ctx context.Context, userID uint64) ([]Schedule, error)
+	GetScheduleByIDAndUserID(ctx context.Context, scheduleID uint64, userID uint64) (*Schedule, error)
+	GetSchedulesByIDs(ctx context.Context, scheduleIDs []uint) ([]Schedule, error)
+	CreateSchedule(ctx context.Context, schedule *Schedule) error
+	SaveSchedule(ctx context.Context, schedule *Schedule) error
+	DeleteScheduleForUser(ctx context.Context, scheduleID uint64, userID uint64) (int, error)
 }
 
 type Repository struct {
@@ -41,6 +57,14 @@ func (r *Repository) GetServicesForUser(ctx context.Context, userID uint64) ([]M
 	return services, nil
 }
 
+func (r *Repository) GetServiceByID(ctx context.Context, serviceID uint64) (*MonitoredService, error) {
+	service, err := gorm.G[MonitoredService](r.conn).Where("id = ?", serviceID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
 func (r *Repository) GetServiceByIDAndUserID(ctx context.Context, serviceID uint64, userID uint64) (*MonitoredService, error) {
 	service, err := gorm.G[MonitoredService](r.conn).Where("id = ? AND user_id = ?", serviceID, userID).First(ctx)
 	if err != nil {
@@ -49,8 +73,16 @@ func (r *Repository) GetServiceByIDAndUserID(ctx context.Context, serviceID uint
 	return &service, nil
 }
 
+func (r *Repository) GetServiceByHeartbeatToken(ctx context.Context, token string) (*MonitoredService, error) {
+	service, err := gorm.G[MonitoredService](r.conn).Where("heartbeat_token = ?", token).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
 func (r *Repository) GetAllServices(ctx context.Context) ([]MonitoredService, error) {
-	services, err := gorm.G[MonitoredService](r.conn).Find(ctx)
+	services, err := gorm.G[MonitoredService](r.conn).Preload("EscalationPolicy", nil).Find(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +101,70 @@ func (r *Repository) DeleteServiceForUser(ctx context.Context, serviceID uint64,
 	return gorm.G[MonitoredService](r.conn).Where("id = ? AND user_id = ?", serviceID, userID).Delete(ctx)
 }
 
+func (r *Repository) GetServicesByEscalationPolicy(ctx context.Context, policyID uint64) ([]MonitoredService, error) {
+	return gorm.G[MonitoredService](r.conn).Where("escalation_policy_id = ?", policyID).Find(ctx)
+}
+
 func (r *Repository) CreateUser(ctx context.Context, user *User) error {
 	return r.conn.Create(user).Error
 }
+
+func (r *Repository) GetEscalationPoliciesForUser(ctx context.Context, userID uint64) ([]EscalationPolicy, error) {
+	return gorm.G[EscalationPolicy](r.conn).Where("user_id = ?", userID).Find(ctx)
+}
+
+func (r *Repository) GetEscalationPolicyByIDAndUserID(ctx context.Context, policyID uint64, userID uint64) (*EscalationPolicy, error) {
+	policy, err := gorm.G[EscalationPolicy](r.conn).Where("id = ? AND user_id = ?", policyID, userID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *Repository) CreateEscalationPolicy(ctx context.Context, policy *EscalationPolicy) error {
+	return gorm.G[EscalationPolicy](r.conn).Create(ctx, policy)
+}
+
+func (r *Repository) SaveEscalationPolicy(ctx context.Context, policy *EscalationPolicy) error {
+	return r.conn.WithContext(ctx).Save(policy).Error
+}
+
+func (r *Repository) DeleteEscalationPolicyForUser(ctx context.Context, policyID uint64, userID uint64) (int, error) {
+	return gorm.G[EscalationPolicy](r.conn).Where("id = ? AND user_id = ?", policyID, userID).Delete(ctx)
+}
+
+func (r *Repository) GetEscalationPoliciesBySchedule(ctx context.Context, scheduleID uint64) ([]EscalationPolicy, error) {
+	containsSchedule := fmt.Sprintf(`[{"schedule_ids": [%d]}]`, scheduleID)
+	return gorm.G[EscalationPolicy](r.conn).Where("levels @> ?::jsonb", containsSchedule).Find(ctx)
+}
+
+func (r *Repository) GetSchedulesForUser(ctx context.Context, userID uint64) ([]Schedule, error) {
+	return gorm.G[Schedule](r.conn).Where("user_id = ?", userID).Find(ctx)
+}
+
+func (r *Repository) GetScheduleByIDAndUserID(ctx context.Context, scheduleID uint64, userID uint64) (*Schedule, error) {
+	schedule, err := gorm.G[Schedule](r.conn).Where("id = ? AND user_id = ?", scheduleID, userID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *Repository) GetSchedulesByIDs(ctx context.Context, scheduleIDs []uint) ([]Schedule, error) {
+	if len(scheduleIDs) == 0 {
+		return nil, nil
+	}
+	return gorm.G[Schedule](r.conn).Where("id IN ?", scheduleIDs).Find(ctx)
+}
+
+func (r *Repository) CreateSchedule(ctx context.Context, schedule *Schedule) error {
+	return gorm.G[Schedule](r.conn).Create(ctx, schedule)
+}
+
+func (r *Repository) SaveSchedule(ctx context.Context, schedule *Schedule) error {
+	return r.conn.WithContext(ctx).Save(schedule).Error
+}
+
+func (r *Repository) DeleteScheduleForUser(ctx context.Context, scheduleID uint64, userID uint64) (int, error) {
+	return gorm.G[Schedule](r.conn).Where("id = ? AND user_id = ?", scheduleID, userID).Delete(ctx)
+}
@@ -0,0 +1,21 @@
+package dto
+
+type EscalationPolicyRequest struct {
+	Name   string               `json:"name" binding:"required"`
+	Levels []EscalationLevelDTO `json:"levels" binding:"required,min=1,max=10,dive"`
+	Repeat int                  `json:"repeat" binding:"omitempty,min=0,max=5"` // times the whole policy is repeated after the last level
+}
+
+// EscalationLevelDTO notifies all its targets and whoever is on call in its schedules at once
+type EscalationLevelDTO struct {
+	Targets     []string `json:"targets" binding:"omitempty,max=10,unique,dive,email"`
+	ScheduleIDs []uint   `json:"scheduleIds" binding:"omitempty,max=5,unique"`
+	Timeout     int      `json:"timeout" binding:"required,min=1"` // in minutes, before the next level is notified
+}
+
+type EscalationPolicyDTO struct {
+	ID     uint                 `json:"id"`
+	Name   string               `json:"name"`
+	Levels []EscalationLevelDTO `json:"levels"`
+	Repeat int                  `json:"repeat"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+type ScheduleRequest struct {
+	Name      string                `json:"name" binding:"required"`
+	Timezone  string                `json:"timezone" binding:"omitempty,timezone"` // IANA name, UTC if empty
+	Layers    []ScheduleLayerDTO    `json:"layers" binding:"required,min=1,max=10,dive"`
+	Overrides []ScheduleOverrideDTO `json:"overrides" binding:"omitempty,max=100,dive"`
+}
+
+// ScheduleLayerDTO rotates through its users. Later layers take precedence over earlier ones.
+type ScheduleLayerDTO struct {
+	Users        []string `json:"users" binding:"required,min=1,max=50,dive,email"`
+	Start        string   `json:"start" binding:"required"` // first handoff as 2006-01-02T15:04 in the schedule's timezone
+	End          string   `json:"end"`                      // the layer never ends if empty
+	RotationDays int      `json:"rotationDays" binding:"required,min=1,max=365"`
+}
+
+// ScheduleOverrideDTO takes precedence over all layers
+type ScheduleOverrideDTO struct {
+	User  string    `json:"user" binding:"required,email"`
+	Start time.Time `json:"start" binding:"required"`
+	End   time.Time `json:"end" binding:"required,gtfield=Start"`
+}
+
+type ScheduleDTO struct {
+	ID        uint                  `json:"id"`
+	Name      string                `json:"name"`
+	Timezone  string                `json:"timezone"`
+	Layers    []ScheduleLayerDTO    `json:"layers"`
+	Overrides []ScheduleOverrideDTO `json:"overrides"`
+}
+
+type OnCallDTO struct {
+	Now  ShiftDTO  `json:"now"`
+	Next *ShiftDTO `json:"next"` // null if the current shift never ends
+}
+
+type ShiftDTO struct {
+	User  string     `json:"user"`  // empty when nobody is on call
+	Start *time.Time `json:"start"` // null if the shift started before the schedule
+	End   *time.Time `json:"end"`   // null if the shift never ends
+}
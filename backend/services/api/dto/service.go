@@ -1,32 +1,128 @@
 package dto
 
 type MonitoredServiceRequest struct {
-	Name                string  `json:"name" binding:"required"`
-	URL                 string  `json:"url" binding:"required,url"`
-	Port                int     `json:"port" binding:"required,min=1,max=65535"`
-	HealthCheckInterval int     `json:"healthCheckInterval" binding:"required,min=1"`
-	AlertWindow         int     `json:"alertWindow" binding:"required,min=1"`
-	AllowedResponseTime int     `json:"allowedResponseTime" binding:"required,min=1"`
-	FirstOncallerEmail  string  `json:"firstOncallerEmail" binding:"required,email"`
-	SecondOncallerEmail *string `json:"secondOncallerEmail" binding:"omitempty,email"`
+	Name                string             `json:"name" binding:"required"`
+	URL                 string             `json:"url" binding:"required_unless=CheckType heartbeat CheckType synthetic,omitempty,url"`
+	Port                int                `json:"port" binding:"required_unless=CheckType heartbeat CheckType dns CheckType synthetic,omitempty,min=1,max=65535"`
+	CheckType           string             `json:"checkType" binding:"omitempty,oneof=http tcp grpc dns synthetic heartbeat"`
+	HeartbeatGrace      int                `json:"heartbeatGrace" binding:"omitempty,min=1"` // in seconds, added to the interval
+	GRPCServiceName     string             `json:"grpcServiceName"`
+	GRPCUseTLS          bool               `json:"grpcUseTLS"`
+	GRPCTimeout         int                `json:"grpcTimeout" binding:"omitempty,min=1,max=60"`
+	DNSRecordType       string             `json:"dnsRecordType" binding:"omitempty,oneof=A AAAA CNAME MX TXT NS"` // A if empty
+	DNSResolvers        []string           `json:"dnsResolvers" binding:"omitempty,max=5,dive,required"`           // host or host:port
+	DNSExpected         []string           `json:"dnsExpected" binding:"omitempty,dive,required"`                  // required for dns checks, the whole answer in any order
+	Steps               []SyntheticStepDTO `json:"steps" binding:"required_if=CheckType synthetic,omitempty,max=10,dive"`
+	ExpectedStatusCodes []int              `json:"expectedStatusCodes" binding:"omitempty,dive,min=100,max=599"`
+	BodyContains        string             `json:"bodyContains"`
+	BodyRegex           string             `json:"bodyRegex"`
+	JSONPath            string             `json:"jsonPath"`
+	JSONPathEquals      string             `json:"jsonPathEquals"`
+	Method              string             `json:"method" binding:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	Headers             map[string]string  `json:"headers"` // values are write only, an empty value keeps the stored one on update
+	RequestBody         string             `json:"requestBody"`
+	AuthType            string             `json:"authType" binding:"omitempty,oneof=basic bearer"`
+	AuthUsername        string             `json:"authUsername" binding:"required_if=AuthType basic"`
+	AuthSecret          string             `json:"authSecret"`                                         // write only, kept unchanged on update when empty
+	Locations           []string           `json:"locations" binding:"omitempty,unique,dive,required"` // default location only if empty
+	LocationQuorum      int                `json:"locationQuorum" binding:"omitempty,min=1"`           // majority of the locations if empty
+	Retries             int                `json:"retries" binding:"omitempty,min=0,max=5"`
+	RetryBackoff        int                `json:"retryBackoff" binding:"omitempty,min=1,max=60000"` // in milliseconds, doubled after every retry
+	AttemptTimeout      int                `json:"attemptTimeout" binding:"omitempty,min=1,max=60"`  // in seconds
+	HealthCheckInterval int                `json:"healthCheckInterval" binding:"required,min=1"`
+	FailingInterval     int                `json:"failingInterval" binding:"omitempty,min=1,ltfield=HealthCheckInterval"` // in seconds, used while the service is failing
+	CronSchedule        string             `json:"cronSchedule"`                                                          // five fields or a descriptor like @daily, replaces the interval as the schedule
+	CronTimezone        string             `json:"cronTimezone" binding:"omitempty,timezone"`                             // IANA name, UTC if empty
+	AlertWindow         int                `json:"alertWindow" binding:"required,min=1"`
+	AllowedResponseTime int                `json:"allowedResponseTime" binding:"required,min=1"`
+	LatencyThreshold    int                `json:"latencyThreshold" binding:"omitempty,min=1"`      // in milliseconds, 0 disables
+	CertExpiryDays      int                `json:"certExpiryDays" binding:"omitempty,min=1"`        // HTTPS only, 0 disables
+	AutoResolve         bool               `json:"autoResolve"`                                     // resolve open incidents when the service recovers
+	RecoveryChecks      int                `json:"recoveryChecks" binding:"omitempty,min=1,max=10"` // consecutive healthy results needed to recover, 1 if empty
+	NotifyRecovery      bool               `json:"notifyRecovery"`                                  // tell the oncallers of open incidents when the service recovers
+	FlapWindow          int                `json:"flapWindow" binding:"omitempty,min=4,max=100"`    // recent results checked for flapping, 20 if empty
+	FlapThreshold       int                `json:"flapThreshold" binding:"omitempty,min=1,max=100"` // percentage of changes between them that counts as flapping, 50 if empty
+	FirstOncallerEmail  string             `json:"firstOncallerEmail" binding:"required_without=EscalationPolicyID,omitempty,email"`
+	SecondOncallerEmail *string            `json:"secondOncallerEmail" binding:"omitempty,email"`
+	EscalationPolicyID  *uint              `json:"escalationPolicyId"` // replaces the oncallers if set
 }
 
 type MonitoredServiceDTO struct {
-	ID                  uint    `json:"id"`
-	Name                string  `json:"name"`
-	URL                 string  `json:"url"`
-	Port                int     `json:"port"`
-	HealthCheckInterval int     `json:"healthCheckInterval"`
-	AlertWindow         int     `json:"alertWindow"`
-	AllowedResponseTime int     `json:"allowedResponseTime"`
-	FirstOncallerEmail  string  `json:"firstOncallerEmail"`
-	SecondOncallerEmail *string `json:"secondOncallerEmail"`
-	Status              string  `json:"status"`
+	ID                  uint               `json:"id"`
+	Name                string             `json:"name"`
+	URL                 string             `json:"url"`
+	Port                int                `json:"port"`
+	CheckType           string             `json:"checkType"`
+	HeartbeatToken      string             `json:"heartbeatToken,omitempty"`
+	HeartbeatGrace      int                `json:"heartbeatGrace"`
+	GRPCServiceName     string             `json:"grpcServiceName"`
+	GRPCUseTLS          bool               `json:"grpcUseTLS"`
+	GRPCTimeout         int                `json:"grpcTimeout"`
+	DNSRecordType       string             `json:"dnsRecordType"`
+	DNSResolvers        []string           `json:"dnsResolvers"`
+	DNSExpected         []string           `json:"dnsExpected"`
+	Steps               []SyntheticStepDTO `json:"steps"`
+	ExpectedStatusCodes []int              `json:"expectedStatusCodes"`
+	BodyContains        string             `json:"bodyContains"`
+	BodyRegex           string             `json:"bodyRegex"`
+	JSONPath            string             `json:"jsonPath"`
+	JSONPathEquals      string             `json:"jsonPathEquals"`
+	Method              string             `json:"method"`
+	HeaderNames         []string           `json:"headerNames"`
+	RequestBody         string             `json:"requestBody"`
+	AuthType            string             `json:"authType"`
+	AuthUsername        string             `json:"authUsername"`
+	HasAuthSecret       bool               `json:"hasAuthSecret"`
+	Locations           []string           `json:"locations"`
+	LocationQuorum      int                `json:"locationQuorum"`
+	Retries             int                `json:"retries"`
+	RetryBackoff        int                `json:"retryBackoff"`
+	AttemptTimeout      int                `json:"attemptTimeout"`
+	HealthCheckInterval int                `json:"healthCheckInterval"`
+	FailingInterval     int                `json:"failingInterval"`
+	CronSchedule        string             `json:"cronSchedule"`
+	CronTimezone        string             `json:"cronTimezone"`
+	AlertWindow         int                `json:"alertWindow"`
+	AllowedResponseTime int                `json:"allowedResponseTime"`
+	LatencyThreshold    int                `json:"latencyThreshold"`
+	CertExpiryDays      int                `json:"certExpiryDays"`
+	AutoResolve         bool               `json:"autoResolve"`
+	RecoveryChecks      int                `json:"recoveryChecks"`
+	NotifyRecovery      bool               `json:"notifyRecovery"`
+	FlapWindow          int                `json:"flapWindow"`
+	FlapThreshold       int                `json:"flapThreshold"`
+	FirstOncallerEmail  string             `json:"firstOncallerEmail"`
+	SecondOncallerEmail *string            `json:"secondOncallerEmail"`
+	EscalationPolicyID  *uint              `json:"escalationPolicyId"`
+	Status              string             `json:"status"`
+}
+
+// SyntheticStepDTO is a single request of a synthetic check. Values extracted by
+// earlier steps are available as {{name}} in the URL, headers and body.
+type SyntheticStepDTO struct {
+	Name                string                  `json:"name" binding:"required"`
+	Method              string                  `json:"method" binding:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	URL                 string                  `json:"url" binding:"required"`
+	Headers             map[string]string       `json:"headers"`
+	Body                string                  `json:"body"`
+	ExpectedStatusCodes []int                   `json:"expectedStatusCodes" binding:"omitempty,dive,min=100,max=599"`
+	BodyContains        string                  `json:"bodyContains"`
+	BodyRegex           string                  `json:"bodyRegex"`
+	JSONPath            string                  `json:"jsonPath"`
+	JSONPathEquals      string                  `json:"jsonPathEquals"`
+	Extract             []VariableExtractionDTO `json:"extract" binding:"omitempty,dive"`
+}
+
+type VariableExtractionDTO struct {
+	Name     string `json:"name" binding:"required,alphanum"`
+	JSONPath string `json:"jsonPath" binding:"required_without=Header"` // the header is used if empty
+	Header   string `json:"header"`
 }
 
 type IncidentDTO struct {
 	ID        string             `json:"id"`
 	ServiceID uint               `json:"serviceID"`
+	Kind      string             `json:"kind"`
 	Events    []IncidentEventDTO `json:"events"`
 }
 
@@ -42,7 +138,11 @@ type StatusMetrics struct {
 }
 
 type StatusDataPoint struct {
-	Timestamp string `json:"timestamp"`
-	Success   uint   `json:"success"`
-	Total     uint   `json:"total"`
+	Timestamp    string   `json:"timestamp"`
+	Success      uint     `json:"success"`
+	Total        uint     `json:"total"`
+	AvgLatencyMs *float64 `json:"avgLatencyMs"` // null when no check in the bin recorded latency
+	P95LatencyMs *float64 `json:"p95LatencyMs"`
+	Retried      uint     `json:"retried"` // checks that needed more than one attempt
+	NoData       bool     `json:"noData"`  // no checks were scheduled for part of the bin
 }
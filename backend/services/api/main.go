@@ -24,6 +24,7 @@ import (
 
 func main() {
 	config.Intro("API")
+	config.CheckSecretsKey()
 
 	if config.GetConfig().Env == config.PROD {
 		gin.SetMode(gin.ReleaseMode)
@@ -32,7 +33,7 @@ func main() {
 	ctx := context.Background()
 
 	dbConn := db.GetDBConnection()
-	dbConn.AutoMigrate(&db.User{}, &db.MonitoredService{})
+	dbConn.AutoMigrate(&db.User{}, &db.Schedule{}, &db.EscalationPolicy{}, &db.MonitoredService{})
 
 	psClient := pubsub_common.Init(ctx)
 	defer psClient.Close()
@@ -87,7 +88,9 @@ func runRPCServer() {
 		db.NewRepository(db.GetDBConnection()),
 	))
 
-	pb.RegisterSchedulerServiceServer(grpcServer, &rpc.SchedulerServiceServer{})
+	pb.RegisterSchedulerServiceServer(grpcServer, rpc.NewSchedulerServiceServer(
+		db.NewRepository(db.GetDBConnection()),
+	))
 	reflection.Register(grpcServer)
 
 	log.Printf("Starting gRPC server listening on port %d", port)
@@ -14,7 +14,9 @@ type PubSubServiceI interface {
 	SendServiceCreatedMessage(ctx context.Context, service db.MonitoredService) error
 	SendServiceUpdatedMessage(ctx context.Context, service db.MonitoredService) error
 	SendServiceDeletedMessage(ctx context.Context, serviceID uint64) error
-	SendOncallerAcknowledgedMessage(ctx context.Context, incidentID string, onCaller string) error
+	SendOncallerAcknowledgedMessage(ctx context.Context, incidentID string, serviceID uint64, onCaller string) error
+	SendOncallerResolvedMessage(ctx context.Context, incidentID string, serviceID uint64, onCaller string) error
+	SendHeartbeatMessage(ctx context.Context, serviceID uint64) error
 }
 
 type PubSubService struct {
@@ -26,11 +28,7 @@ func NewPubSubService(client *pubsub.Client) *PubSubService {
 }
 
 func (s *PubSubService) SendServiceCreatedMessage(ctx context.Context, service db.MonitoredService) error {
-	oncallers := []string{service.FirstOncallerEmail}
-
-	if service.SecondOncallerEmail != nil {
-		oncallers = append(oncallers, *service.SecondOncallerEmail)
-	}
+	escalation, escalationRepeat := service.Escalation()
 
 	payload := pubsub_common.PubSubPayload{
 		ServiceID: uint64(service.ID),
@@ -38,8 +36,19 @@ func (s *PubSubService) SendServiceCreatedMessage(ctx context.Context, service d
 		Data: pubsub_common.PubSubPayloadData{
 			AllowedResponseTime: service.AllowedResponseTime,
 			AlertWindow:         service.AlertWindow,
+			LatencyThreshold:    service.LatencyThreshold,
+			CertExpiryDays:      service.CertExpiryDays,
+			Locations:           service.Locations,
+			LocationQuorum:      service.LocationQuorum,
 			HealthCheckInterval: service.HealthCheckInterval,
-			Oncallers:           oncallers,
+			Oncallers:           service.Oncallers(),
+			Escalation:          escalation,
+			EscalationRepeat:    escalationRepeat,
+			AutoResolve:         service.AutoResolve,
+			RecoveryChecks:      service.RecoveryChecks,
+			NotifyRecovery:      service.NotifyRecovery,
+			FlapWindow:          service.FlapWindow,
+			FlapThreshold:       service.FlapThreshold,
 		},
 	}
 
@@ -47,12 +56,7 @@ func (s *PubSubService) SendServiceCreatedMessage(ctx context.Context, service d
 }
 
 func (s *PubSubService) SendServiceUpdatedMessage(ctx context.Context, service db.MonitoredService) error {
-
-	oncallers := []string{service.FirstOncallerEmail}
-
-	if service.SecondOncallerEmail != nil {
-		oncallers = append(oncallers, *service.SecondOncallerEmail)
-	}
+	escalation, escalationRepeat := service.Escalation()
 
 	payload := pubsub_common.PubSubPayload{
 		ServiceID: uint64(service.ID),
@@ -60,8 +64,19 @@ func (s *PubSubService) SendServiceUpdatedMessage(ctx context.Context, service d
 		Data: pubsub_common.PubSubPayloadData{
 			AllowedResponseTime: service.AllowedResponseTime,
 			AlertWindow:         service.AlertWindow,
+			LatencyThreshold:    service.LatencyThreshold,
+			CertExpiryDays:      service.CertExpiryDays,
+			Locations:           service.Locations,
+			LocationQuorum:      service.LocationQuorum,
 			HealthCheckInterval: service.HealthCheckInterval,
-			Oncallers:           oncallers,
+			Oncallers:           service.Oncallers(),
+			Escalation:          escalation,
+			EscalationRepeat:    escalationRepeat,
+			AutoResolve:         service.AutoResolve,
+			RecoveryChecks:      service.RecoveryChecks,
+			NotifyRecovery:      service.NotifyRecovery,
+			FlapWindow:          service.FlapWindow,
+			FlapThreshold:       service.FlapThreshold,
 		},
 	}
 
@@ -77,8 +92,9 @@ func (s *PubSubService) SendServiceDeletedMessage(ctx context.Context, serviceID
 	return pubsub_common.SendPayload(ctx, s.client, pubsub_common.ServiceRemovedTopic, payload, fmt.Sprintf("%d", serviceID))
 }
 
-func (s *PubSubService) SendOncallerAcknowledgedMessage(ctx context.Context, incidentID string, onCaller string) error {
+func (s *PubSubService) SendOncallerAcknowledgedMessage(ctx context.Context, incidentID string, serviceID uint64, onCaller string) error {
 	payload := pubsub_common.PubSubPayload{
+		ServiceID:  serviceID,
 		IncidentID: incidentID,
 		OnCaller:   onCaller,
 		Timestamp:  time.Now().UTC().Format(time.RFC3339),
@@ -86,3 +102,24 @@ func (s *PubSubService) SendOncallerAcknowledgedMessage(ctx context.Context, inc
 
 	return pubsub_common.SendPayload(ctx, s.client, pubsub_common.OncallerAcknowledgedTopic, payload, incidentID)
 }
+
+func (s *PubSubService) SendOncallerResolvedMessage(ctx context.Context, incidentID string, serviceID uint64, onCaller string) error {
+	payload := pubsub_common.PubSubPayload{
+		ServiceID:  serviceID,
+		IncidentID: incidentID,
+		OnCaller:   onCaller,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+	}
+
+	return pubsub_common.SendPayload(ctx, s.client, pubsub_common.OncallerResolvedTopic, payload, incidentID)
+}
+
+// SendHeartbeatMessage reports a heartbeat service as up, like a passed check would
+func (s *PubSubService) SendHeartbeatMessage(ctx context.Context, serviceID uint64) error {
+	payload := pubsub_common.PubSubPayload{
+		ServiceID: serviceID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	return pubsub_common.SendPayload(ctx, s.client, pubsub_common.ServiceUpTopic, payload, fmt.Sprintf("%d", serviceID))
+}
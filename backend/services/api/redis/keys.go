@@ -5,3 +5,8 @@ import "strconv"
 func GetServiceStatusKey(serviceID uint64) string {
 	return "common:service:" + strconv.FormatUint(serviceID, 10) + ":status"
 }
+
+// GetLastHeartbeatKey is shared with the scheduler, which reports missed heartbeats
+func GetLastHeartbeatKey(serviceID uint64) string {
+	return "common:service:" + strconv.FormatUint(serviceID, 10) + ":last_heartbeat"
+}
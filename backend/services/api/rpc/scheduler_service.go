@@ -2,43 +2,133 @@ package rpc
 
 import (
 	"alerting-platform/api/db"
+	pubsub_common "alerting-platform/common/pubsub"
 	"alerting-platform/common/rpc"
 	"context"
+	"errors"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"gorm.io/gorm"
 )
 
 type SchedulerServiceServer struct {
 	rpc.UnimplementedSchedulerServiceServer
+	repo db.RepositoryI
 }
 
-// service SchedulerService {
-//   rpc GetAllSchedulerConfigurations (google.protobuf.Empty) returns (SchedulerConfigResponse);
-// }
+func NewSchedulerServiceServer(repo db.RepositoryI) *SchedulerServiceServer {
+	return &SchedulerServiceServer{
+		repo: repo,
+	}
+}
 
 func (s *SchedulerServiceServer) GetAllSchedulerConfigurations(ctx context.Context, empty *emptypb.Empty) (*rpc.SchedulerConfigResponse, error) {
-	conn := db.GetDBConnection()
+	services, err := s.repo.GetAllServices(ctx)
 
-	var services []db.MonitoredService
-	result := conn.Find(&services)
-
-	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
-		return nil, result.Error
+	if err != nil {
+		return nil, err
 	}
-	// rpcServices := make([]*rpc.ServiceInfoForIncident, 0, len(services))
-	// schedulerConfigResponse := make(rpc.SchedulerConfigResponse)
+
 	rpcServices := make([]*rpc.ServiceInfoForScheduler, 0, len(services))
 	for _, service := range services {
-		rpcService := &rpc.ServiceInfoForScheduler{
-			ServiceId:           uint64(service.ID),
-			Url:                 service.URL,
-			HealthCheckInterval: int64(service.HealthCheckInterval),
-		}
-		rpcServices = append(rpcServices, rpcService)
+		rpcServices = append(rpcServices, mapServiceToSchedulerInfo(service))
 	}
 
 	return &rpc.SchedulerConfigResponse{
 		Services: rpcServices,
 	}, nil
 }
+
+func (s *SchedulerServiceServer) GetSchedulerConfiguration(ctx context.Context, request *rpc.SchedulerConfigRequest) (*rpc.ServiceInfoForScheduler, error) {
+	service, err := s.repo.GetServiceByID(ctx, request.ServiceId)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.NotFound, "service %d not found", request.ServiceId)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapServiceToSchedulerInfo(*service), nil
+}
+
+func mapServiceToSchedulerInfo(service db.MonitoredService) *rpc.ServiceInfoForScheduler {
+	return &rpc.ServiceInfoForScheduler{
+		ServiceId:           uint64(service.ID),
+		Url:                 service.URL,
+		HealthCheckInterval: int64(service.HealthCheckInterval),
+		FailingInterval:     int64(service.FailingInterval),
+		CronSchedule:        service.CronSchedule,
+		CronTimezone:        service.CronTimezone,
+		CheckType:           service.CheckType,
+		Port:                int32(service.Port),
+		GrpcServiceName:     service.GRPCServiceName,
+		GrpcUseTls:          service.GRPCUseTLS,
+		GrpcTimeout:         int64(service.GRPCTimeout),
+		DnsRecordType:       service.DNSRecordType,
+		DnsResolvers:        service.DNSResolvers,
+		DnsExpected:         service.DNSExpected,
+		Steps:               toRPCSteps(service.Steps),
+		ExpectedStatusCodes: toInt32s(service.ExpectedStatusCodes),
+		BodyContains:        service.BodyContains,
+		BodyRegex:           service.BodyRegex,
+		JsonPath:            service.JSONPath,
+		JsonPathEquals:      service.JSONPathEquals,
+		Method:              service.Method,
+		EncryptedHeaders:    service.Headers,
+		RequestBody:         service.RequestBody,
+		AuthType:            service.AuthType,
+		AuthUsername:        service.AuthUsername,
+		EncryptedAuthSecret: service.AuthSecret,
+		HeartbeatGrace:      int64(service.HeartbeatGrace),
+		Locations:           service.Locations,
+		LocationQuorum:      int64(service.LocationQuorum),
+		Retries:             int64(service.Retries),
+		RetryBackoff:        int64(service.RetryBackoff),
+		AttemptTimeout:      int64(service.AttemptTimeout),
+	}
+}
+
+func toRPCSteps(steps []pubsub_common.SyntheticStep) []*rpc.SyntheticStep {
+	result := make([]*rpc.SyntheticStep, len(steps))
+	for i, step := range steps {
+		extract := make([]*rpc.VariableExtraction, len(step.Extract))
+		for j, extraction := range step.Extract {
+			extract[j] = &rpc.VariableExtraction{
+				Name:     extraction.Name,
+				JsonPath: extraction.JSONPath,
+				Header:   extraction.Header,
+			}
+		}
+
+		result[i] = &rpc.SyntheticStep{
+			Name:                step.Name,
+			Method:              step.Method,
+			Url:                 step.URL,
+			Headers:             step.Headers,
+			Body:                step.Body,
+			ExpectedStatusCodes: toInt32s(step.ExpectedStatusCodes),
+			BodyContains:        step.BodyContains,
+			BodyRegex:           step.BodyRegex,
+			JsonPath:            step.JSONPath,
+			JsonPathEquals:      step.JSONPathEquals,
+			Extract:             extract,
+		}
+	}
+	return result
+}
+
+func toInt32s(values []int) []int32 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]int32, len(values))
+	for i, value := range values {
+		result[i] = int32(value)
+	}
+	return result
+}
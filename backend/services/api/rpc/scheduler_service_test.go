@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"alerting-platform/api/db"
+	"alerting-platform/common/rpc"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+func TestGetAllSchedulerConfigurations(t *testing.T) {
+	ctx := context.Background()
+	empty := &emptypb.Empty{}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(db.MockRepository)
+		server := NewSchedulerServiceServer(mockRepo)
+
+		services := []db.MonitoredService{
+			{Model: gorm.Model{ID: 1}, URL: "http://first.com", HealthCheckInterval: 30},
+			{Model: gorm.Model{ID: 2}, URL: "tcp://second.com", Port: 5432, CheckType: "tcp", HealthCheckInterval: 60},
+		}
+
+		mockRepo.On("GetAllServices", ctx).Return(services, nil).Once()
+
+		response, err := server.GetAllSchedulerConfigurations(ctx, empty)
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Services, 2)
+
+		assert.Equal(t, uint64(1), response.Services[0].ServiceId)
+		assert.Equal(t, "http://first.com", response.Services[0].Url)
+		assert.Equal(t, int64(30), response.Services[0].HealthCheckInterval)
+
+		assert.Equal(t, uint64(2), response.Services[1].ServiceId)
+		assert.Equal(t, "tcp://second.com", response.Services[1].Url)
+		assert.Equal(t, int64(60), response.Services[1].HealthCheckInterval)
+		assert.Equal(t, "tcp", response.Services[1].CheckType)
+		assert.Equal(t, int32(5432), response.Services[1].Port)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error from repository", func(t *testing.T) {
+		mockRepo := new(db.MockRepository)
+		server := NewSchedulerServiceServer(mockRepo)
+
+		dbError := errors.New("database error")
+		mockRepo.On("GetAllServices", ctx).Return(nil, dbError).Once()
+
+		response, err := server.GetAllSchedulerConfigurations(ctx, empty)
+
+		assert.Equal(t, dbError, err)
+		assert.Nil(t, response)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetSchedulerConfiguration(t *testing.T) {
+	ctx := context.Background()
+	request := &rpc.SchedulerConfigRequest{ServiceId: 7}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(db.MockRepository)
+		server := NewSchedulerServiceServer(mockRepo)
+
+		service := &db.MonitoredService{Model: gorm.Model{ID: 7}, URL: "http://example.com", HealthCheckInterval: 15}
+		mockRepo.On("GetServiceByID", ctx, uint64(7)).Return(service, nil).Once()
+
+		response, err := server.GetSchedulerConfiguration(ctx, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(7), response.ServiceId)
+		assert.Equal(t, "http://example.com", response.Url)
+		assert.Equal(t, int64(15), response.HealthCheckInterval)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("gRPC check", func(t *testing.T) {
+		mockRepo := new(db.MockRepository)
+		server := NewSchedulerServiceServer(mockRepo)
+
+		service := &db.MonitoredService{
+			Model:           gorm.Model{ID: 7},
+			URL:             "grpc://payments.internal",
+			Port:            9090,
+			CheckType:       "grpc",
+			GRPCServiceName: "payments.v1.Payments",
+			GRPCUseTLS:      true,
+			GRPCTimeout:     5,
+		}
+		mockRepo.On("GetServiceByID", ctx, uint64(7)).Return(service, nil).Once()
+
+		response, err := server.GetSchedulerConfiguration(ctx, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "grpc", response.CheckType)
+		assert.Equal(t, "payments.v1.Payments", response.GrpcServiceName)
+		assert.True(t, response.GrpcUseTls)
+		assert.Equal(t, int64(5), response.GrpcTimeout)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockRepo := new(db.MockRepository)
+		server := NewSchedulerServiceServer(mockRepo)
+
+		mockRepo.On("GetServiceByID", ctx, uint64(7)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		response, err := server.GetSchedulerConfiguration(ctx, request)
+
+		assert.Nil(t, response)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error from repository", func(t *testing.T) {
+		mockRepo := new(db.MockRepository)
+		server := NewSchedulerServiceServer(mockRepo)
+
+		dbError := errors.New("database error")
+		mockRepo.On("GetServiceByID", ctx, uint64(7)).Return(nil, dbError).Once()
+
+		response, err := server.GetSchedulerConfiguration(ctx, request)
+
+		assert.Nil(t, response)
+		assert.Equal(t, dbError, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
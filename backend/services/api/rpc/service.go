@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"alerting-platform/api/db"
+	"alerting-platform/common/oncall"
+	pubsub_common "alerting-platform/common/pubsub"
 	"alerting-platform/common/rpc"
 	"context"
 
@@ -26,18 +28,42 @@ func (s *IncidentManagerServiceServer) GetAllServicesInfo(ctx context.Context, e
 		return nil, err
 	}
 
+	// One query for the schedules of every policy, Escalation picks those of each level
+	var scheduleIDs []uint
+	for _, service := range services {
+		if service.EscalationPolicy != nil {
+			scheduleIDs = append(scheduleIDs, service.EscalationPolicy.ScheduleIDs()...)
+		}
+	}
+
+	schedules, err := s.repo.GetSchedulesByIDs(ctx, scheduleIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	rpcServices := make([]*rpc.ServiceInfoForIncident, 0, len(services))
 	for _, service := range services {
-		oncallers := []string{service.FirstOncallerEmail}
-		if service.SecondOncallerEmail != nil {
-			oncallers = append(oncallers, *service.SecondOncallerEmail)
+		if service.EscalationPolicy != nil {
+			service.EscalationPolicy.Schedules = schedules
 		}
+		escalation, escalationRepeat := service.Escalation()
 
 		rpcService := &rpc.ServiceInfoForIncident{
 			ServiceId:           uint64(service.ID),
 			AlertWindow:         int64(service.AlertWindow),
 			AllowedResponseTime: int64(service.AllowedResponseTime),
-			Oncallers:           oncallers,
+			LatencyThreshold:    int64(service.LatencyThreshold),
+			CertExpiryDays:      int64(service.CertExpiryDays),
+			Locations:           service.Locations,
+			LocationQuorum:      int64(service.LocationQuorum),
+			Oncallers:           service.Oncallers(),
+			Escalation:          toRPCEscalation(escalation),
+			EscalationRepeat:    int64(escalationRepeat),
+			AutoResolve:         service.AutoResolve,
+			RecoveryChecks:      int64(service.RecoveryChecks),
+			NotifyRecovery:      service.NotifyRecovery,
+			FlapWindow:          int64(service.FlapWindow),
+			FlapThreshold:       int64(service.FlapThreshold),
 		}
 		rpcServices = append(rpcServices, rpcService)
 	}
@@ -46,3 +72,54 @@ func (s *IncidentManagerServiceServer) GetAllServicesInfo(ctx context.Context, e
 		Services: rpcServices,
 	}, nil
 }
+
+func toRPCEscalation(levels []pubsub_common.EscalationLevel) []*rpc.EscalationLevel {
+	if len(levels) == 0 {
+		return nil
+	}
+
+	result := make([]*rpc.EscalationLevel, len(levels))
+	for i, level := range levels {
+		result[i] = &rpc.EscalationLevel{
+			Targets:   level.Targets,
+			Timeout:   int64(level.Timeout),
+			Schedules: toRPCSchedules(level.Schedules),
+		}
+	}
+	return result
+}
+
+func toRPCSchedules(schedules []oncall.Schedule) []*rpc.Schedule {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	result := make([]*rpc.Schedule, len(schedules))
+	for i, schedule := range schedules {
+		layers := make([]*rpc.ScheduleLayer, len(schedule.Layers))
+		for j, layer := range schedule.Layers {
+			layers[j] = &rpc.ScheduleLayer{
+				Users:        layer.Users,
+				Start:        layer.Start,
+				End:          layer.End,
+				RotationDays: int64(layer.RotationDays),
+			}
+		}
+
+		overrides := make([]*rpc.ScheduleOverride, len(schedule.Overrides))
+		for j, override := range schedule.Overrides {
+			overrides[j] = &rpc.ScheduleOverride{
+				User:  override.User,
+				Start: override.Start.Unix(),
+				End:   override.End.Unix(),
+			}
+		}
+
+		result[i] = &rpc.Schedule{
+			Timezone:  schedule.Timezone,
+			Layers:    layers,
+			Overrides: overrides,
+		}
+	}
+	return result
+}
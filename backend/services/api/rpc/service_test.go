@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"alerting-platform/api/db"
+	"alerting-platform/common/oncall"
+	pubsub_common "alerting-platform/common/pubsub"
 	"context"
 	"errors"
 	"testing"
@@ -31,28 +33,65 @@ func TestGetAllServicesInfo(t *testing.T) {
 				Model:               gorm.Model{ID: 2},
 				AlertWindow:         600,
 				AllowedResponseTime: 500,
+				LatencyThreshold:    250,
 				FirstOncallerEmail:  "another@oncaller.com",
 				SecondOncallerEmail: &secondOncaller,
 			},
+			{
+				Model:               gorm.Model{ID: 3},
+				AlertWindow:         600,
+				AllowedResponseTime: 500,
+				EscalationPolicy: &db.EscalationPolicy{
+					Levels: []pubsub_common.EscalationLevel{
+						{Targets: []string{"first@oncaller.com", "second@oncaller.com"}, Timeout: 5},
+						{Targets: []string{"lead@oncaller.com"}, ScheduleIDs: []uint{4}, Timeout: 15},
+					},
+					Repeat: 2,
+				},
+			},
+		}
+
+		schedule := db.Schedule{
+			Model:    gorm.Model{ID: 4},
+			Timezone: "Europe/Warsaw",
+			Layers:   []oncall.Layer{{Users: []string{"week@oncaller.com"}, Start: "2025-01-06T09:00", RotationDays: 7}},
 		}
 
 		mockRepo.On("GetAllServices", ctx).Return(services, nil).Once()
+		mockRepo.On("GetSchedulesByIDs", ctx, []uint{4}).Return([]db.Schedule{schedule}, nil).Once()
 
 		response, err := server.GetAllServicesInfo(ctx, empty)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
-		assert.Len(t, response.Services, 2)
+		assert.Len(t, response.Services, 3)
 
 		assert.Equal(t, uint64(1), response.Services[0].ServiceId)
 		assert.Equal(t, int64(300), response.Services[0].AlertWindow)
 		assert.Equal(t, int64(1000), response.Services[0].AllowedResponseTime)
+		assert.Zero(t, response.Services[0].LatencyThreshold)
 		assert.Equal(t, []string{"first@oncaller.com"}, response.Services[0].Oncallers)
 
 		assert.Equal(t, uint64(2), response.Services[1].ServiceId)
 		assert.Equal(t, int64(600), response.Services[1].AlertWindow)
 		assert.Equal(t, int64(500), response.Services[1].AllowedResponseTime)
+		assert.Equal(t, int64(250), response.Services[1].LatencyThreshold)
 		assert.Equal(t, []string{"another@oncaller.com", "second@oncaller.com"}, response.Services[1].Oncallers)
+		assert.Empty(t, response.Services[1].Escalation)
+
+		assert.Empty(t, response.Services[2].Oncallers)
+		if assert.Len(t, response.Services[2].Escalation, 2) {
+			assert.Equal(t, []string{"first@oncaller.com", "second@oncaller.com"}, response.Services[2].Escalation[0].Targets)
+			assert.Equal(t, int64(15), response.Services[2].Escalation[1].Timeout)
+			assert.Empty(t, response.Services[2].Escalation[0].Schedules)
+			if assert.Len(t, response.Services[2].Escalation[1].Schedules, 1) {
+				rpcSchedule := response.Services[2].Escalation[1].Schedules[0]
+				assert.Equal(t, "Europe/Warsaw", rpcSchedule.Timezone)
+				assert.Equal(t, []string{"week@oncaller.com"}, rpcSchedule.Layers[0].Users)
+				assert.Equal(t, int64(7), rpcSchedule.Layers[0].RotationDays)
+			}
+		}
+		assert.Equal(t, int64(2), response.Services[2].EscalationRepeat)
 
 		mockRepo.AssertExpectations(t)
 	})
@@ -1,9 +1,24 @@
 package utils
 
 import (
+	"alerting-platform/api/db"
+	"alerting-platform/common/config"
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/secrets"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrMissingAuthSecret  = errors.New("authSecret is required for basic and bearer auth")
+	ErrMissingHeaderValue = errors.New("header values are required for new headers")
+)
+
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
@@ -13,3 +28,85 @@ func IsPasswordHashCorrect(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// SetAuthSecret encrypts a newly submitted secret. An empty one keeps the stored
+// secret, as clients never get it back and cannot resend it on update.
+func SetAuthSecret(service *db.MonitoredService, secret string) error {
+	if service.AuthType == "" {
+		service.AuthUsername = ""
+		service.AuthSecret = ""
+		return nil
+	}
+
+	if secret == "" {
+		if service.AuthSecret == "" {
+			return ErrMissingAuthSecret
+		}
+		return nil
+	}
+
+	encrypted, err := secrets.Encrypt(secret, config.GetSecretsKey())
+	if err != nil {
+		return err
+	}
+
+	service.AuthSecret = encrypted
+	return nil
+}
+
+// SetHeaders encrypts the submitted header values, as they often carry credentials
+// like API keys. An empty value keeps the stored one, clients never get it back.
+func SetHeaders(service *db.MonitoredService, headers map[string]string) error {
+	stored := make(map[string]string, len(service.Headers))
+	for name, value := range service.Headers {
+		stored[http.CanonicalHeaderKey(name)] = value
+	}
+
+	encryptedHeaders := make(map[string]string, len(headers))
+	for name, value := range headers {
+		name = http.CanonicalHeaderKey(name)
+
+		if value == "" {
+			encrypted, exists := stored[name]
+			if !exists {
+				return fmt.Errorf("%w: %s", ErrMissingHeaderValue, name)
+			}
+			encryptedHeaders[name] = encrypted
+			continue
+		}
+
+		encrypted, err := secrets.Encrypt(value, config.GetSecretsKey())
+		if err != nil {
+			return err
+		}
+		encryptedHeaders[name] = encrypted
+	}
+
+	if len(encryptedHeaders) == 0 {
+		encryptedHeaders = nil
+	}
+	service.Headers = encryptedHeaders
+	return nil
+}
+
+// SetHeartbeatToken gives heartbeat services a token for the ping endpoint and keeps
+// it across updates, so that jobs do not need to be reconfigured
+func SetHeartbeatToken(service *db.MonitoredService) error {
+	if service.CheckType != pubsub_common.CheckTypeHeartbeat {
+		service.HeartbeatToken = nil
+		return nil
+	}
+
+	if service.HeartbeatToken != nil {
+		return nil
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+
+	encoded := hex.EncodeToString(token)
+	service.HeartbeatToken = &encoded
+	return nil
+}
@@ -4,24 +4,263 @@ import (
 	"alerting-platform/api/db"
 	"alerting-platform/api/dto"
 	"alerting-platform/common/db/firestore"
+	"alerting-platform/common/oncall"
+	pubsub_common "alerting-platform/common/pubsub"
+	"maps"
+	"net/http"
+	"slices"
 	"time"
 )
 
+// MapRequestToService copies user editable fields, leaving ownership and IDs untouched
+func MapRequestToService(input dto.MonitoredServiceRequest, service *db.MonitoredService) {
+	service.Name = input.Name
+	service.URL = input.URL
+	service.Port = input.Port
+	service.CheckType = input.CheckType
+	if service.CheckType == "" {
+		service.CheckType = pubsub_common.CheckTypeHTTP
+	}
+	service.HeartbeatGrace = input.HeartbeatGrace
+	service.GRPCServiceName = input.GRPCServiceName
+	service.GRPCUseTLS = input.GRPCUseTLS
+	service.GRPCTimeout = input.GRPCTimeout
+	service.DNSRecordType = input.DNSRecordType
+	service.DNSResolvers = input.DNSResolvers
+	service.DNSExpected = input.DNSExpected
+	service.Steps = mapStepsFromDTO(input.Steps)
+	service.ExpectedStatusCodes = input.ExpectedStatusCodes
+	service.BodyContains = input.BodyContains
+	service.BodyRegex = input.BodyRegex
+	service.JSONPath = input.JSONPath
+	service.JSONPathEquals = input.JSONPathEquals
+	service.Method = input.Method
+	if service.Method == "" {
+		service.Method = http.MethodGet
+	}
+	service.RequestBody = input.RequestBody
+	service.AuthType = input.AuthType
+	service.AuthUsername = input.AuthUsername
+	service.Locations = input.Locations
+	service.LocationQuorum = input.LocationQuorum
+	service.Retries = input.Retries
+	service.RetryBackoff = input.RetryBackoff
+	service.AttemptTimeout = input.AttemptTimeout
+	service.HealthCheckInterval = input.HealthCheckInterval
+	service.FailingInterval = input.FailingInterval
+	service.CronSchedule = input.CronSchedule
+	service.CronTimezone = input.CronTimezone
+	service.AlertWindow = input.AlertWindow
+	service.AllowedResponseTime = input.AllowedResponseTime
+	service.LatencyThreshold = input.LatencyThreshold
+	service.CertExpiryDays = input.CertExpiryDays
+	service.AutoResolve = input.AutoResolve
+	service.RecoveryChecks = input.RecoveryChecks
+	service.NotifyRecovery = input.NotifyRecovery
+	service.FlapWindow = input.FlapWindow
+	service.FlapThreshold = input.FlapThreshold
+	service.FirstOncallerEmail = input.FirstOncallerEmail
+	service.SecondOncallerEmail = input.SecondOncallerEmail
+	service.EscalationPolicyID = input.EscalationPolicyID
+}
+
 func MapServiceToDTO(service db.MonitoredService, status string) dto.MonitoredServiceDTO {
+	heartbeatToken := ""
+	if service.HeartbeatToken != nil {
+		heartbeatToken = *service.HeartbeatToken
+	}
+
 	return dto.MonitoredServiceDTO{
 		ID:                  service.ID,
 		Name:                service.Name,
 		URL:                 service.URL,
 		Port:                service.Port,
+		CheckType:           service.CheckType,
+		HeartbeatToken:      heartbeatToken,
+		HeartbeatGrace:      service.HeartbeatGrace,
+		GRPCServiceName:     service.GRPCServiceName,
+		GRPCUseTLS:          service.GRPCUseTLS,
+		GRPCTimeout:         service.GRPCTimeout,
+		DNSRecordType:       service.DNSRecordType,
+		DNSResolvers:        service.DNSResolvers,
+		DNSExpected:         service.DNSExpected,
+		Steps:               mapStepsToDTO(service.Steps),
+		ExpectedStatusCodes: service.ExpectedStatusCodes,
+		BodyContains:        service.BodyContains,
+		BodyRegex:           service.BodyRegex,
+		JSONPath:            service.JSONPath,
+		JSONPathEquals:      service.JSONPathEquals,
+		Method:              service.Method,
+		HeaderNames:         headerNames(service.Headers),
+		RequestBody:         service.RequestBody,
+		AuthType:            service.AuthType,
+		AuthUsername:        service.AuthUsername,
+		HasAuthSecret:       service.AuthSecret != "",
+		Locations:           service.Locations,
+		LocationQuorum:      service.LocationQuorum,
+		Retries:             service.Retries,
+		RetryBackoff:        service.RetryBackoff,
+		AttemptTimeout:      service.AttemptTimeout,
 		HealthCheckInterval: service.HealthCheckInterval,
+		FailingInterval:     service.FailingInterval,
+		CronSchedule:        service.CronSchedule,
+		CronTimezone:        service.CronTimezone,
 		AlertWindow:         service.AlertWindow,
 		AllowedResponseTime: service.AllowedResponseTime,
+		LatencyThreshold:    service.LatencyThreshold,
+		CertExpiryDays:      service.CertExpiryDays,
+		AutoResolve:         service.AutoResolve,
+		RecoveryChecks:      service.RecoveryChecks,
+		NotifyRecovery:      service.NotifyRecovery,
+		FlapWindow:          service.FlapWindow,
+		FlapThreshold:       service.FlapThreshold,
 		FirstOncallerEmail:  service.FirstOncallerEmail,
 		SecondOncallerEmail: service.SecondOncallerEmail,
+		EscalationPolicyID:  service.EscalationPolicyID,
 		Status:              status,
 	}
 }
 
+// MapRequestToEscalationPolicy copies user editable fields, leaving ownership and IDs untouched
+func MapRequestToEscalationPolicy(input dto.EscalationPolicyRequest, policy *db.EscalationPolicy) {
+	policy.Name = input.Name
+	policy.Repeat = input.Repeat
+	policy.Levels = make([]pubsub_common.EscalationLevel, len(input.Levels))
+	for i, level := range input.Levels {
+		policy.Levels[i] = pubsub_common.EscalationLevel{
+			Targets:     level.Targets,
+			ScheduleIDs: level.ScheduleIDs,
+			Timeout:     level.Timeout,
+		}
+	}
+}
+
+func MapEscalationPolicyToDTO(policy db.EscalationPolicy) dto.EscalationPolicyDTO {
+	levels := make([]dto.EscalationLevelDTO, len(policy.Levels))
+	for i, level := range policy.Levels {
+		levels[i] = dto.EscalationLevelDTO{
+			Targets:     level.Targets,
+			ScheduleIDs: level.ScheduleIDs,
+			Timeout:     level.Timeout,
+		}
+	}
+
+	return dto.EscalationPolicyDTO{
+		ID:     policy.ID,
+		Name:   policy.Name,
+		Levels: levels,
+		Repeat: policy.Repeat,
+	}
+}
+
+// MapRequestToSchedule copies user editable fields, leaving ownership and IDs untouched
+func MapRequestToSchedule(input dto.ScheduleRequest, schedule *db.Schedule) {
+	schedule.Name = input.Name
+	schedule.Timezone = input.Timezone
+
+	schedule.Layers = make([]oncall.Layer, len(input.Layers))
+	for i, layer := range input.Layers {
+		schedule.Layers[i] = oncall.Layer(layer)
+	}
+
+	schedule.Overrides = make([]oncall.Override, len(input.Overrides))
+	for i, override := range input.Overrides {
+		schedule.Overrides[i] = oncall.Override(override)
+	}
+}
+
+func MapScheduleToDTO(schedule db.Schedule) dto.ScheduleDTO {
+	layers := make([]dto.ScheduleLayerDTO, len(schedule.Layers))
+	for i, layer := range schedule.Layers {
+		layers[i] = dto.ScheduleLayerDTO(layer)
+	}
+
+	overrides := make([]dto.ScheduleOverrideDTO, len(schedule.Overrides))
+	for i, override := range schedule.Overrides {
+		overrides[i] = dto.ScheduleOverrideDTO(override)
+	}
+
+	return dto.ScheduleDTO{
+		ID:        schedule.ID,
+		Name:      schedule.Name,
+		Timezone:  schedule.Timezone,
+		Layers:    layers,
+		Overrides: overrides,
+	}
+}
+
+func MapShiftToDTO(shift oncall.Shift) dto.ShiftDTO {
+	result := dto.ShiftDTO{User: shift.User}
+	if !shift.Start.IsZero() {
+		result.Start = &shift.Start
+	}
+	if !shift.End.IsZero() {
+		result.End = &shift.End
+	}
+	return result
+}
+
+// headerNames lists the configured headers without their values, which may carry credentials
+func headerNames(headers map[string]string) []string {
+	names := slices.Collect(maps.Keys(headers))
+	slices.Sort(names)
+	return names
+}
+
+func mapStepsFromDTO(steps []dto.SyntheticStepDTO) []pubsub_common.SyntheticStep {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	result := make([]pubsub_common.SyntheticStep, len(steps))
+	for i, step := range steps {
+		extract := make([]pubsub_common.VariableExtraction, len(step.Extract))
+		for j, extraction := range step.Extract {
+			extract[j] = pubsub_common.VariableExtraction(extraction)
+		}
+
+		result[i] = pubsub_common.SyntheticStep{
+			Name:                step.Name,
+			Method:              step.Method,
+			URL:                 step.URL,
+			Headers:             step.Headers,
+			Body:                step.Body,
+			ExpectedStatusCodes: step.ExpectedStatusCodes,
+			BodyContains:        step.BodyContains,
+			BodyRegex:           step.BodyRegex,
+			JSONPath:            step.JSONPath,
+			JSONPathEquals:      step.JSONPathEquals,
+			Extract:             extract,
+		}
+	}
+	return result
+}
+
+func mapStepsToDTO(steps []pubsub_common.SyntheticStep) []dto.SyntheticStepDTO {
+	result := make([]dto.SyntheticStepDTO, len(steps))
+	for i, step := range steps {
+		extract := make([]dto.VariableExtractionDTO, len(step.Extract))
+		for j, extraction := range step.Extract {
+			extract[j] = dto.VariableExtractionDTO(extraction)
+		}
+
+		result[i] = dto.SyntheticStepDTO{
+			Name:                step.Name,
+			Method:              step.Method,
+			URL:                 step.URL,
+			Headers:             step.Headers,
+			Body:                step.Body,
+			ExpectedStatusCodes: step.ExpectedStatusCodes,
+			BodyContains:        step.BodyContains,
+			BodyRegex:           step.BodyRegex,
+			JSONPath:            step.JSONPath,
+			JSONPathEquals:      step.JSONPathEquals,
+			Extract:             extract,
+		}
+	}
+	return result
+}
+
 func MapIncidentToDTO(logs []firestore.IncidentLog) dto.IncidentDTO {
 	events := make([]dto.IncidentEventDTO, len(logs))
 	for i, log := range logs {
@@ -32,9 +271,19 @@ func MapIncidentToDTO(logs []firestore.IncidentLog) dto.IncidentDTO {
 		}
 	}
 
+	// Only some events carry the kind, and incidents logged before kinds existed are DOWN
+	kind := pubsub_common.IncidentKindDown
+	for _, log := range logs {
+		if log.Kind != "" {
+			kind = log.Kind
+			break
+		}
+	}
+
 	return dto.IncidentDTO{
 		ID:        logs[0].IncidentID,
 		ServiceID: uint(logs[0].ServiceID),
+		Kind:      kind,
 		Events:    events,
 	}
 }
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	pubsub_common "alerting-platform/common/pubsub"
+)
+
+// escalation returns the levels notified one after the other until somebody acknowledges.
+// Services without a policy escalate from the first to the second oncaller, giving each
+// the allowed response time.
+func (service ServiceInfo) escalation() []pubsub_common.EscalationLevel {
+	if len(service.Escalation) > 0 {
+		return service.Escalation
+	}
+
+	levels := make([]pubsub_common.EscalationLevel, 0, len(service.Oncallers))
+	for _, oncaller := range service.Oncallers {
+		levels = append(levels, pubsub_common.EscalationLevel{
+			Targets: []string{oncaller},
+			Timeout: service.AllowedResponseTime,
+		})
+	}
+	return levels
+}
+
+// incidentEscalation reads the escalation copied into the incident hash, setting the
+// level, cycle and repeat of the incident. Incidents opened before escalation policies
+// keep their first and second oncaller as two levels.
+func incidentEscalation(incidentInfo *IncidentInfo, incident map[string]string) ([]pubsub_common.EscalationLevel, error) {
+	if incidentInfo.State != IncidentStateWaitingForAck {
+		return legacyEscalation(incidentInfo, incident)
+	}
+
+	var levels []pubsub_common.EscalationLevel
+	if err := json.Unmarshal([]byte(incident["escalation"]), &levels); err != nil {
+		return nil, fmt.Errorf("invalid escalation of incident %s: %w", incidentInfo.IncidentID, err)
+	}
+
+	incidentInfo.Level, _ = strconv.Atoi(incident["level"])
+	incidentInfo.Cycle, _ = strconv.Atoi(incident["cycle"])
+	incidentInfo.EscalationRepeat, _ = strconv.Atoi(incident["escalation_repeat"])
+
+	return levels, nil
+}
+
+func legacyEscalation(incidentInfo *IncidentInfo, incident map[string]string) ([]pubsub_common.EscalationLevel, error) {
+	allowedResponseTime, err := strconv.Atoi(incident["allowed_response_time"])
+	if err != nil {
+		return nil, err
+	}
+	incidentInfo.AllowedResponseTime = allowedResponseTime
+
+	service := ServiceInfo{AllowedResponseTime: allowedResponseTime}
+	for _, oncaller := range []string{incident["first_oncaller"], incident["second_oncaller"]} {
+		if oncaller != "" {
+			service.Oncallers = append(service.Oncallers, oncaller)
+		}
+	}
+
+	if incidentInfo.State == IncidentStateWaitingForSecondAck {
+		incidentInfo.Level = 1
+	}
+
+	return service.escalation(), nil
+}
+
+// levelTargets returns the targets of the level and whoever is on call in its schedules at the time
+func levelTargets(level pubsub_common.EscalationLevel, now time.Time) []string {
+	targets := slices.Clone(level.Targets)
+	for _, schedule := range level.Schedules {
+		user := schedule.ShiftAt(now).User
+		if user != "" && !slices.Contains(targets, user) {
+			targets = append(targets, user)
+		}
+	}
+	return targets
+}
+
+// notifiedTargets returns who the current level of the incident notified, resolving
+// the schedules again for incidents that did not record it
+func notifiedTargets(incident map[string]string, level pubsub_common.EscalationLevel, now time.Time) []string {
+	var targets []string
+	if err := json.Unmarshal([]byte(incident["notified"]), &targets); err != nil {
+		return levelTargets(level, now)
+	}
+	return targets
+}
+
+// notifyLevel notifies all targets of the level at once
+func (managerState *ManagerState) notifyLevel(incidentID string, serviceID uint64, kind string, targets []string, certificate *pubsub_common.CertificateInfo) {
+	if len(targets) == 0 {
+		log.Printf("[WARNING] Nobody to notify about incident %s of service %d at this level", incidentID, serviceID)
+	}
+
+	for _, target := range targets {
+		go func() {
+			err := managerState.pubSubService.SendNotifyOncallerMessage(
+				context.Background(),
+				incidentID,
+				serviceID,
+				kind,
+				target,
+				certificate,
+				time.Now().UTC(),
+			)
+
+			if err != nil {
+				log.Printf("[ERROR] Failed to send notify oncaller message for service %d: %v", serviceID, err)
+			}
+		}()
+	}
+}
+
+func (managerState *ManagerState) sendAcknowledgeTimeouts(incidentID string, serviceID uint64, targets []string) {
+	for _, target := range targets {
+		go func() {
+			err := managerState.pubSubService.SendAcknowledgeTimeoutMessage(
+				context.Background(),
+				incidentID,
+				serviceID,
+				target,
+				time.Now().UTC(),
+			)
+
+			if err != nil {
+				log.Printf("[ERROR] Failed to send acknowledge timeout message for service %d: %v", serviceID, err)
+			}
+		}()
+	}
+}
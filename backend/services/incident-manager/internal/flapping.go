@@ -0,0 +1,80 @@
+package internal
+
+import (
+	redis_keys "alerting-plafform/incident-manager/redis"
+	"context"
+	"log"
+	"time"
+
+	"alerting-platform/common/db"
+	pubsub_common "alerting-platform/common/pubsub"
+)
+
+const (
+	defaultFlapWindow    = 20 // results
+	defaultFlapThreshold = 50 // percent
+)
+
+func (service ServiceInfo) flapWindow() int {
+	if service.FlapWindow > 1 {
+		return service.FlapWindow
+	}
+	return defaultFlapWindow
+}
+
+func (service ServiceInfo) flapThreshold() int {
+	if service.FlapThreshold > 0 {
+		return service.FlapThreshold
+	}
+	return defaultFlapThreshold
+}
+
+// isFlapping reports whether the results changed between UP and DOWN at least as often
+// as the threshold, in percent of all pairs of consecutive results. Windows that are
+// not full yet never flap.
+func isFlapping(history []string, window int, threshold int) bool {
+	if len(history) < window || window < 2 {
+		return false
+	}
+
+	changes := 0
+	for i := 1; i < len(history); i++ {
+		if history[i] != history[i-1] {
+			changes++
+		}
+	}
+
+	return changes*100 >= threshold*(len(history)-1)
+}
+
+// recordFlapResult adds the settled result to the sliding window of the service and
+// reports whether the service is flapping, opening a single flapping incident for it.
+// Should be locked before calling
+func (managerState *ManagerState) recordFlapResult(ctx context.Context, service ServiceInfo, status string, eventTime time.Time) (bool, error) {
+	redisClient := db.GetRedisClient()
+	historyKey := redis_keys.GetFlapHistoryKey(service.ID)
+	window := service.flapWindow()
+
+	pipe := redisClient.TxPipeline()
+
+	pipe.LPush(ctx, historyKey, status)
+	pipe.LTrim(ctx, historyKey, 0, int64(window-1))
+	history := pipe.LRange(ctx, historyKey, 0, -1)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	if !isFlapping(history.Val(), window, service.flapThreshold()) {
+		return false, nil
+	}
+
+	open, err := managerState.isIncidentOpen(ctx, service.ID, pubsub_common.IncidentKindFlapping)
+	if err != nil || open {
+		return true, err
+	}
+
+	log.Printf("[DEBUG] Service %d is flapping", service.ID)
+
+	return true, managerState.HandleNewIncident(ctx, service.ID, pubsub_common.IncidentKindFlapping, eventTime, nil)
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	redis_keys "alerting-plafform/incident-manager/redis"
+	"context"
+	"testing"
+	"time"
+
+	pubsub_common "alerting-platform/common/pubsub"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIsFlapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		history   []string
+		window    int
+		threshold int
+		want      bool
+	}{
+		{"Alternating", []string{"UP", "DOWN", "UP", "DOWN", "UP"}, 5, 50, true},
+		{"Stable", []string{"DOWN", "DOWN", "DOWN", "DOWN", "DOWN"}, 5, 50, false},
+		{"Single Outage", []string{"UP", "UP", "DOWN", "DOWN", "UP"}, 5, 50, true},
+		{"Below Threshold", []string{"UP", "UP", "DOWN", "DOWN", "UP"}, 5, 60, false},
+		{"Window Not Full", []string{"UP", "DOWN", "UP"}, 5, 50, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isFlapping(tt.history, tt.window, tt.threshold))
+		})
+	}
+}
+
+func TestServiceFlapping(t *testing.T) {
+	ctx := context.Background()
+	serviceID := uint64(1)
+	payload := pubsub_common.PubSubPayload{ServiceID: serviceID}
+
+	flappingService := ServiceInfo{
+		ID:                  serviceID,
+		AlertWindow:         1,
+		AllowedResponseTime: 10,
+		Oncallers:           []string{"test@oncaller.com"},
+		FlapWindow:          4,
+		AutoResolve:         true,
+	}
+
+	t.Run("Single Flapping Incident", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = flappingService
+
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindFlapping, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindFlapping, "test@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+
+		// Failures never last as long as the alert window, each UP resets it
+		for range 4 {
+			assert.NoError(t, managerState.HandleServiceDown(ctx, payload, time.Now()))
+			assert.NoError(t, managerState.HandleServiceUp(ctx, payload, time.Now()))
+		}
+
+		time.Sleep(100 * time.Millisecond)
+		flappingKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindFlapping)
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(flappingKey, "state"))
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("No DOWN Incident While Flapping", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = flappingService
+
+		flappingKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindFlapping)
+		s.HSet(flappingKey, "incident_id", "flapping-incident", "state", IncidentStateWaitingForAck)
+		s.Push(redis_keys.GetFlapHistoryKey(serviceID), "UP", "DOWN", "UP")
+		s.Set(redis_keys.GetDownSinceKey(serviceID), "1")
+
+		err := managerState.HandleServiceDown(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, s.Exists(redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)))
+		mockPubSub.AssertNotCalled(t, "SendIncidentStartMessage")
+	})
+
+	t.Run("Recovers Once Stable", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = flappingService
+
+		flappingKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindFlapping)
+		s.HSet(flappingKey, "incident_id", "flapping-incident", "state", IncidentStateWaitingForAck)
+		s.Push(redis_keys.GetFlapHistoryKey(serviceID), "DOWN", "UP", "DOWN", "UP")
+
+		mockPubSub.On("SendIncidentRecoveredMessage", mock.Anything, "flapping-incident", serviceID, pubsub_common.IncidentKindFlapping, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendIncidentResolvedMessage", mock.Anything, "flapping-incident", serviceID, "", mock.Anything).Return(nil).Once()
+
+		// Still flapping until only one of the three pairs changes
+		for range 2 {
+			assert.NoError(t, managerState.HandleServiceUp(ctx, payload, time.Now()))
+			assert.Equal(t, IncidentStateWaitingForAck, s.HGet(flappingKey, "state"))
+		}
+
+		assert.NoError(t, managerState.HandleServiceUp(ctx, payload, time.Now()))
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, IncidentStateResolved, s.HGet(flappingKey, "state"))
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Monitoring Gap Clears History", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		s.Push(redis_keys.GetFlapHistoryKey(serviceID), "UP", "DOWN")
+
+		err := managerState.HandleMonitoringGap(ctx, payload, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, s.Exists(redis_keys.GetFlapHistoryKey(serviceID)))
+	})
+}
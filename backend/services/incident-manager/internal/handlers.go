@@ -3,9 +3,11 @@ package internal
 import (
 	redis_keys "alerting-plafform/incident-manager/redis"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"alerting-platform/common/db"
@@ -35,6 +37,10 @@ func (managerState *ManagerState) HandleMessage(ctx context.Context, msg pubsub_
 		err = managerState.HandleServiceRemoved(ctx, *payload, *eventTime)
 	case pubsub_common.OncallerAcknowledgedTopic:
 		err = managerState.HandleOncallerAcknowledged(ctx, *payload, *eventTime)
+	case pubsub_common.OncallerResolvedTopic:
+		err = managerState.HandleOncallerResolved(ctx, *payload, *eventTime)
+	case pubsub_common.MonitoringGapTopic:
+		err = managerState.HandleMonitoringGap(ctx, *payload, *eventTime)
 	default:
 		log.Printf("[WARNING] Unknown event type: %s", eventType)
 	}
@@ -47,13 +53,62 @@ func (managerState *ManagerState) HandleMessage(ctx context.Context, msg pubsub_
 	}
 }
 
+const (
+	verdictField  = "verdict"
+	checkRoundTTL = time.Hour
+)
+
 func (managerState *ManagerState) HandleServiceUp(ctx context.Context, payload pubsub_common.PubSubPayload, eventTime time.Time) error {
 	lock := managerState.LockService(payload.ServiceID) // Will this slow down processing?
 	defer lock.Unlock()
 
+	managerState.mu.Lock()
+	service, exists := managerState.services[payload.ServiceID]
+	managerState.mu.Unlock()
+
+	if exists {
+		if err := managerState.handleCertificate(ctx, service, payload.Data.Result, eventTime); err != nil {
+			return err
+		}
+	}
+
+	settled, err := managerState.countVote(ctx, service, exists, payload.Data.Result, "UP")
+	if err != nil || !settled {
+		return err
+	}
+
+	flapping := false
+	if exists {
+		flapping, err = managerState.recordFlapResult(ctx, service, "UP", eventTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Healthy results of a flapping service don't recover anything, the flapping
+	// incident covers them. Slow responses still recover the service from being down.
+	if exists && !flapping {
+		for _, kind := range []string{pubsub_common.IncidentKindDown, pubsub_common.IncidentKindFlapping} {
+			if err := managerState.countRecovery(ctx, service, kind, eventTime); err != nil {
+				return err
+			}
+		}
+	}
+
+	if exists && isDegraded(service, payload.Data.Result) {
+		return managerState.handleServiceDegraded(ctx, service, eventTime)
+	}
+
+	if exists && !flapping {
+		if err := managerState.countRecovery(ctx, service, pubsub_common.IncidentKindDegraded, eventTime); err != nil {
+			return err
+		}
+	}
+
 	redisClient := db.GetRedisClient()
 	serviceStatusKey := redis_keys.GetServiceStatusKey(payload.ServiceID)
 	downSinceKey := redis_keys.GetDownSinceKey(payload.ServiceID)
+	degradedSinceKey := redis_keys.GetDegradedSinceKey(payload.ServiceID)
 
 	log.Printf("[DEBUG] Service %d is UP", payload.ServiceID)
 
@@ -61,28 +116,214 @@ func (managerState *ManagerState) HandleServiceUp(ctx context.Context, payload p
 
 	pipe.Set(ctx, serviceStatusKey, "UP", 0).Err()
 	pipe.Del(ctx, downSinceKey).Err()
+	pipe.Del(ctx, degradedSinceKey).Err()
+
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// countVote records the result of one location in its check round and reports
+// whether the round is settled in favour of the vote. DOWN needs the quorum of
+// locations, UP needs enough locations to make that quorum impossible. Results
+// outside a round, like missed heartbeats, and single location services settle at once.
+// Should be locked before calling
+func (managerState *ManagerState) countVote(ctx context.Context, service ServiceInfo, exists bool, result *pubsub_common.CheckResult, vote string) (bool, error) {
+	if !exists || result == nil || result.Round == 0 || service.Locations <= 1 {
+		return true, nil
+	}
+
+	location := result.Location
+	if location == "" {
+		location = pubsub_common.DefaultLocation
+	}
+
+	redisClient := db.GetRedisClient()
+	roundKey := redis_keys.GetCheckRoundKey(service.ID, result.Round)
+
+	pipe := redisClient.TxPipeline()
+
+	pipe.HSet(ctx, roundKey, location, vote)
+	pipe.Expire(ctx, roundKey, checkRoundTTL)
+	votes := pipe.HGetAll(ctx, roundKey)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	agreeing := 0
+	for field, value := range votes.Val() {
+		if field != verdictField && value == vote {
+			agreeing++
+		}
+	}
+
+	needed := service.quorum()
+	if vote == "UP" {
+		needed = service.Locations - needed + 1
+	}
+
+	if agreeing < needed {
+		log.Printf("[DEBUG] Service %d is %s in %s, %d of %d locations needed", service.ID, vote, location, agreeing, needed)
+		return false, nil
+	}
+
+	// Later results of the round must not apply the verdict again
+	return redisClient.HSetNX(ctx, roundKey, verdictField, vote).Result()
+}
+
+// isDegraded reports whether a successful check was slower than the latency threshold
+func isDegraded(service ServiceInfo, result *pubsub_common.CheckResult) bool {
+	return service.LatencyThreshold > 0 && result != nil && result.ResponseTimeMs > float64(service.LatencyThreshold)
+}
+
+// Should be locked before calling
+func (managerState *ManagerState) handleServiceDegraded(ctx context.Context, service ServiceInfo, eventTime time.Time) error {
+	redisClient := db.GetRedisClient()
+	serviceStatusKey := redis_keys.GetServiceStatusKey(service.ID)
+	downSinceKey := redis_keys.GetDownSinceKey(service.ID)
+	degradedSinceKey := redis_keys.GetDegradedSinceKey(service.ID)
+
+	log.Printf("[DEBUG] Service %d is DEGRADED", service.ID)
+
+	pipe := redisClient.TxPipeline()
+
+	pipe.Set(ctx, serviceStatusKey, "DEGRADED", 0).Err()
+	pipe.Del(ctx, downSinceKey).Err()
 
 	_, err := pipe.Exec(ctx)
 
+	if err != nil {
+		return err
+	}
+
+	err = managerState.interruptRecovery(ctx, service.ID, pubsub_common.IncidentKindDegraded)
+	if err != nil {
+		return err
+	}
+
+	degradedSinceStr, err := redisClient.Get(ctx, degradedSinceKey).Result()
+
+	if err == redis.Nil {
+		err = redisClient.Set(ctx, degradedSinceKey, eventTime.Unix(), 0).Err()
+		return err
+	}
+
+	degradedSince, err := strconv.ParseInt(degradedSinceStr, 10, 64)
+
+	if err != nil {
+		return err
+	}
+
+	currentTime := time.Now().UTC().Unix()
+
+	if currentTime-degradedSince >= int64(service.AlertWindow) {
+		open, err := managerState.isIncidentOpen(ctx, service.ID, pubsub_common.IncidentKindDegraded)
+		if err != nil || open {
+			return err
+		}
+
+		return managerState.HandleNewIncident(ctx, service.ID, pubsub_common.IncidentKindDegraded, time.Unix(degradedSince, 0), nil)
+	}
+
 	return err
 }
 
+// handleCertificate opens a certificate expiry incident once per certificate, so
+// acknowledging it silences the warning until the certificate changes.
+// Should be locked before calling
+func (managerState *ManagerState) handleCertificate(ctx context.Context, service ServiceInfo, result *pubsub_common.CheckResult, eventTime time.Time) error {
+	if service.CertExpiryDays <= 0 || result == nil || result.Certificate == nil {
+		return nil
+	}
+
+	certificate := result.Certificate
+	if time.Until(certificate.NotAfter) >= time.Duration(service.CertExpiryDays)*24*time.Hour {
+		return nil
+	}
+
+	redisClient := db.GetRedisClient()
+	certAlertedKey := redis_keys.GetCertAlertedKey(service.ID)
+	notAfter := strconv.FormatInt(certificate.NotAfter.Unix(), 10)
+
+	alerted, err := redisClient.Get(ctx, certAlertedKey).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	if alerted == notAfter {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Certificate of service %d expires at %s", service.ID, certificate.NotAfter.Format(time.RFC3339))
+
+	err = redisClient.Set(ctx, certAlertedKey, notAfter, 0).Err()
+	if err != nil {
+		return err
+	}
+
+	return managerState.HandleNewIncident(ctx, service.ID, pubsub_common.IncidentKindCertExpiry, eventTime, certificate)
+}
+
 func (managerState *ManagerState) HandleServiceDown(ctx context.Context, payload pubsub_common.PubSubPayload, eventTime time.Time) error {
 	lock := managerState.LockService(payload.ServiceID)
 	defer lock.Unlock()
 
+	managerState.mu.Lock()
+	service, exists := managerState.services[payload.ServiceID]
+	managerState.mu.Unlock()
+
+	// A failed assertion still completes the TLS handshake
+	if exists {
+		if err := managerState.handleCertificate(ctx, service, payload.Data.Result, eventTime); err != nil {
+			return err
+		}
+	}
+
+	settled, err := managerState.countVote(ctx, service, exists, payload.Data.Result, "DOWN")
+	if err != nil || !settled {
+		return err
+	}
+
 	redisClient := db.GetRedisClient()
 	serviceStatusKey := redis_keys.GetServiceStatusKey(payload.ServiceID)
 	downSinceKey := redis_keys.GetDownSinceKey(payload.ServiceID)
+	degradedSinceKey := redis_keys.GetDegradedSinceKey(payload.ServiceID)
 
 	log.Printf("[DEBUG] Service %d is DOWN", payload.ServiceID)
 
-	err := redisClient.Set(ctx, serviceStatusKey, "DOWN", 0).Err()
+	pipe := redisClient.TxPipeline()
+
+	pipe.Set(ctx, serviceStatusKey, "DOWN", 0).Err()
+	pipe.Del(ctx, degradedSinceKey).Err()
+
+	_, err = pipe.Exec(ctx)
 
 	if err != nil {
 		return err
 	}
 
+	flapping := false
+	if exists {
+		flapping, err = managerState.recordFlapResult(ctx, service, "DOWN", eventTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Incidents recovered in between stay quiet while the service flaps
+	interrupted := []string{pubsub_common.IncidentKindFlapping}
+	if !flapping {
+		interrupted = append(interrupted, pubsub_common.IncidentKindDown, pubsub_common.IncidentKindDegraded)
+	}
+
+	for _, kind := range interrupted {
+		err = managerState.interruptRecovery(ctx, payload.ServiceID, kind)
+		if err != nil {
+			return err
+		}
+	}
+
 	downSinceStr, err := redisClient.Get(ctx, downSinceKey).Result()
 
 	if err == redis.Nil {
@@ -98,10 +339,6 @@ func (managerState *ManagerState) HandleServiceDown(ctx context.Context, payload
 
 	currentTime := time.Now().UTC().Unix()
 
-	managerState.mu.Lock()
-	service, exists := managerState.services[payload.ServiceID]
-	managerState.mu.Unlock()
-
 	if !exists {
 		log.Printf("[WARNING] Service %d not found in configuration", payload.ServiceID)
 		return nil
@@ -110,14 +347,43 @@ func (managerState *ManagerState) HandleServiceDown(ctx context.Context, payload
 	alertWindow := int64(service.AlertWindow)
 
 	if currentTime-downSince >= alertWindow {
-		incidentKey := redis_keys.GetIncidentKey(payload.ServiceID)
-		exists := redisClient.Exists(ctx, incidentKey).Val()
+		if flapping {
+			log.Printf("[DEBUG] Service %d is flapping, not opening a DOWN incident", payload.ServiceID)
+			return nil
+		}
 
-		if exists == 0 {
-			err = managerState.HandleNewIncident(ctx, payload.ServiceID, time.Unix(downSince, 0))
+		open, err := managerState.isIncidentOpen(ctx, payload.ServiceID, pubsub_common.IncidentKindDown)
+		if err != nil || open {
+			return err
 		}
+
+		return managerState.HandleNewIncident(ctx, payload.ServiceID, pubsub_common.IncidentKindDown, time.Unix(downSince, 0), nil)
+	}
+
+	return err
+}
+
+// HandleMonitoringGap marks the status as unknown until the next result arrives.
+// Failures before the gap no longer count towards the alert window or flapping, as
+// the service may have recovered in between. Open incidents stay open.
+func (managerState *ManagerState) HandleMonitoringGap(ctx context.Context, payload pubsub_common.PubSubPayload, eventTime time.Time) error {
+	lock := managerState.LockService(payload.ServiceID)
+	defer lock.Unlock()
+
+	if gap := payload.Data.Gap; gap != nil {
+		log.Printf("[DEBUG] Service %d was not monitored from %s to %s", payload.ServiceID, gap.Since.Format(time.RFC3339), gap.Until.Format(time.RFC3339))
 	}
 
+	redisClient := db.GetRedisClient()
+
+	pipe := redisClient.TxPipeline()
+
+	pipe.Set(ctx, redis_keys.GetServiceStatusKey(payload.ServiceID), pubsub_common.StatusNoData, 0)
+	pipe.Del(ctx, redis_keys.GetDownSinceKey(payload.ServiceID))
+	pipe.Del(ctx, redis_keys.GetDegradedSinceKey(payload.ServiceID))
+	pipe.Del(ctx, redis_keys.GetFlapHistoryKey(payload.ServiceID))
+
+	_, err := pipe.Exec(ctx)
 	return err
 }
 
@@ -131,7 +397,18 @@ func (managerState *ManagerState) HandleServiceCreated(ctx context.Context, payl
 		ID:                  payload.ServiceID,
 		AlertWindow:         payload.Data.AlertWindow,
 		AllowedResponseTime: payload.Data.AllowedResponseTime,
+		LatencyThreshold:    payload.Data.LatencyThreshold,
+		CertExpiryDays:      payload.Data.CertExpiryDays,
+		Locations:           len(payload.Data.Locations),
+		LocationQuorum:      payload.Data.LocationQuorum,
 		Oncallers:           payload.Data.Oncallers,
+		Escalation:          payload.Data.Escalation,
+		EscalationRepeat:    payload.Data.EscalationRepeat,
+		AutoResolve:         payload.Data.AutoResolve,
+		RecoveryChecks:      payload.Data.RecoveryChecks,
+		NotifyRecovery:      payload.Data.NotifyRecovery,
+		FlapWindow:          payload.Data.FlapWindow,
+		FlapThreshold:       payload.Data.FlapThreshold,
 	}
 
 	managerState.services[service.ID] = service
@@ -153,7 +430,18 @@ func (managerState *ManagerState) HandleServiceModified(ctx context.Context, pay
 
 	service.AlertWindow = payload.Data.AlertWindow
 	service.AllowedResponseTime = payload.Data.AllowedResponseTime
+	service.LatencyThreshold = payload.Data.LatencyThreshold
+	service.CertExpiryDays = payload.Data.CertExpiryDays
+	service.Locations = len(payload.Data.Locations)
+	service.LocationQuorum = payload.Data.LocationQuorum
 	service.Oncallers = payload.Data.Oncallers
+	service.Escalation = payload.Data.Escalation
+	service.EscalationRepeat = payload.Data.EscalationRepeat
+	service.AutoResolve = payload.Data.AutoResolve
+	service.RecoveryChecks = payload.Data.RecoveryChecks
+	service.NotifyRecovery = payload.Data.NotifyRecovery
+	service.FlapWindow = payload.Data.FlapWindow
+	service.FlapThreshold = payload.Data.FlapThreshold
 
 	managerState.services[service.ID] = service
 	return nil
@@ -170,46 +458,124 @@ func (managerState *ManagerState) HandleServiceRemoved(ctx context.Context, payl
 	managerState.mu.Unlock()
 
 	redisClient := db.GetRedisClient()
-	incidentKey := redis_keys.GetIncidentKey(payload.ServiceID)
+	oncallerDeadlineSetKey := redis_keys.GetOncallerDeadlineSetKey()
+
+	for _, kind := range incidentKinds {
+		incidentKey := redis_keys.GetIncidentKey(payload.ServiceID, kind)
+
+		exists := redisClient.Exists(ctx, incidentKey).Val()
 
-	exists := redisClient.Exists(ctx, incidentKey).Val()
+		if exists != 0 {
+			err := redisClient.Del(ctx, incidentKey).Err()
+			if err != nil {
+				log.Printf("[ERROR] Failed to delete %s incident for removed service %d: %v", kind, payload.ServiceID, err)
+				return err
+			}
+			log.Printf("[DEBUG] Deleted ongoing %s incident for removed service %d", kind, payload.ServiceID)
+		}
 
-	if exists != 0 {
-		err := redisClient.Del(ctx, incidentKey).Err()
+		err := redisClient.ZRem(ctx, oncallerDeadlineSetKey, redis_keys.GetDeadlineMember(payload.ServiceID, kind)).Err()
 		if err != nil {
-			log.Printf("[ERROR] Failed to delete incident for removed service %d: %v", payload.ServiceID, err)
+			log.Printf("[ERROR] Failed to remove service %d from oncaller deadline set: %v", payload.ServiceID, err)
 			return err
 		}
-		log.Printf("[DEBUG] Deleted ongoing incident for removed service %d", payload.ServiceID)
 	}
 
-	oncallerDeadlineSetKey := redis_keys.GetOncallerDeadlineSetKey()
-	err := redisClient.ZRem(ctx, oncallerDeadlineSetKey, payload.ServiceID).Err()
-	if err != nil {
-		log.Printf("[ERROR] Failed to remove service %d from oncaller deadline set: %v", payload.ServiceID, err)
+	return redisClient.Del(ctx, redis_keys.GetCertAlertedKey(payload.ServiceID), redis_keys.GetFlapHistoryKey(payload.ServiceID)).Err()
+}
+
+// HandleOncallerAcknowledged stops the escalation, the incident stays open until resolved
+func (managerState *ManagerState) HandleOncallerAcknowledged(ctx context.Context, payload pubsub_common.PubSubPayload, eventTime time.Time) error {
+	log.Printf("[DEBUG] Oncaller %s acknowledged incident for service %d", payload.OnCaller, payload.ServiceID)
+
+	lock := managerState.LockService(payload.ServiceID)
+	defer lock.Unlock()
+
+	kind, err := managerState.findIncidentKind(ctx, payload.ServiceID, payload.IncidentID)
+	if err != nil || kind == "" {
 		return err
 	}
 
-	return nil
+	return managerState.handleIncidentAcknowledged(ctx, payload.ServiceID, kind, payload.OnCaller)
 }
 
-func (managerState *ManagerState) HandleOncallerAcknowledged(ctx context.Context, payload pubsub_common.PubSubPayload, eventTime time.Time) error {
-	log.Printf("[DEBUG] Oncaller %s acknowledged incident for service %d", payload.OnCaller, payload.ServiceID)
+func (managerState *ManagerState) HandleOncallerResolved(ctx context.Context, payload pubsub_common.PubSubPayload, eventTime time.Time) error {
+	log.Printf("[DEBUG] Oncaller %s resolved incident for service %d", payload.OnCaller, payload.ServiceID)
 
 	lock := managerState.LockService(payload.ServiceID)
 	defer lock.Unlock()
 
-	return managerState.handleIncidentResolved(ctx, payload.ServiceID, payload.OnCaller)
+	kind, err := managerState.findIncidentKind(ctx, payload.ServiceID, payload.IncidentID)
+	if err != nil || kind == "" {
+		return err
+	}
+
+	return managerState.handleIncidentResolved(ctx, payload.ServiceID, kind, payload.OnCaller)
 }
 
-// Should be locked before calling
-func (managerState *ManagerState) HandleNewIncident(ctx context.Context, serviceID uint64, incidentStartTime time.Time) error {
+// findIncidentKind returns the kind of the incident with the given ID, or an empty
+// string when it is gone, so links to old incidents don't touch newer ones.
+// Payloads without an ID fall back to DOWN.
+func (managerState *ManagerState) findIncidentKind(ctx context.Context, serviceID uint64, incidentID string) (string, error) {
+	if incidentID == "" {
+		return pubsub_common.IncidentKindDown, nil
+	}
+
 	redisClient := db.GetRedisClient()
-	incidentKey := redis_keys.GetIncidentKey(serviceID)
+
+	for _, kind := range incidentKinds {
+		openIncidentID, err := redisClient.HGet(ctx, redis_keys.GetIncidentKey(serviceID, kind), "incident_id").Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if openIncidentID == incidentID {
+			return kind, nil
+		}
+	}
+
+	log.Printf("[WARNING] Incident %s of service %d not found", incidentID, serviceID)
+	return "", nil
+}
+
+// isIncidentOpen reports whether the service has an incident of the kind that is not resolved yet
+func (managerState *ManagerState) isIncidentOpen(ctx context.Context, serviceID uint64, kind string) (bool, error) {
+	redisClient := db.GetRedisClient()
+
+	fields, err := redisClient.HMGet(ctx, redis_keys.GetIncidentKey(serviceID, kind), "incident_id", "state").Result()
+	if err != nil {
+		return false, err
+	}
+
+	return fields[0] != nil && fields[1] != IncidentStateResolved, nil
+}
+
+// getSinceKey returns the key tracking how long the condition behind the incident
+// lasts, or an empty string when the incident kind has none
+func getSinceKey(serviceID uint64, kind string) string {
+	switch kind {
+	case pubsub_common.IncidentKindDown:
+		return redis_keys.GetDownSinceKey(serviceID)
+	case pubsub_common.IncidentKindDegraded:
+		return redis_keys.GetDegradedSinceKey(serviceID)
+	}
+	return ""
+}
+
+// Should be locked before calling. The certificate is only set for certificate expiry incidents.
+func (managerState *ManagerState) HandleNewIncident(ctx context.Context, serviceID uint64, kind string, incidentStartTime time.Time, certificate *pubsub_common.CertificateInfo) error {
+	redisClient := db.GetRedisClient()
+	incidentKey := redis_keys.GetIncidentKey(serviceID, kind)
 
 	incidentID := fmt.Sprintf("%d-%d", serviceID, incidentStartTime.Unix())
+	if kind != pubsub_common.IncidentKindDown {
+		incidentID += "-" + strings.ToLower(kind)
+	}
 
-	log.Printf("[DEBUG] Starting incident %s for service %d", incidentID, serviceID)
+	log.Printf("[DEBUG] Starting %s incident %s for service %d", kind, incidentID, serviceID)
 
 	managerState.mu.Lock()
 	service, exists := managerState.services[serviceID]
@@ -220,33 +586,59 @@ func (managerState *ManagerState) HandleNewIncident(ctx context.Context, service
 		return nil
 	}
 
-	secondOncaller := ""
-	if len(service.Oncallers) > 1 {
-		secondOncaller = service.Oncallers[1]
+	levels := service.escalation()
+	if len(levels) == 0 {
+		log.Printf("[WARNING] Service %d has nobody to notify", serviceID)
+		return nil
+	}
+
+	escalation, err := json.Marshal(levels)
+	if err != nil {
+		return err
+	}
+
+	targets := levelTargets(levels[0], time.Now().UTC())
+	notified, err := json.Marshal(targets)
+	if err != nil {
+		return err
 	}
 
 	incidentInfo := IncidentInfo{
 		IncidentID:          incidentID,
 		ServiceID:           serviceID,
-		State:               IncidentStateWaitingForFirstAck,
+		Kind:                kind,
+		State:               IncidentStateWaitingForAck,
 		IncidentStartTime:   incidentStartTime.Unix(),
 		AllowedResponseTime: service.AllowedResponseTime,
-		FirstOncaller:       service.Oncallers[0],
-		SecondOncaller:      secondOncaller,
+		Escalation:          string(escalation),
+		EscalationRepeat:    service.EscalationRepeat,
+		Notified:            string(notified),
 	}
 
-	err := redisClient.HSet(ctx, incidentKey, incidentInfo).Err()
+	if certificate != nil {
+		incidentInfo.CertNotAfter = certificate.NotAfter.Unix()
+		incidentInfo.CertIssuer = certificate.Issuer
+		incidentInfo.CertSubject = certificate.Subject
+	}
+
+	// Replaces a resolved incident of the same kind, along with its expiry
+	pipe := redisClient.TxPipeline()
+
+	pipe.Del(ctx, incidentKey)
+	pipe.HSet(ctx, incidentKey, incidentInfo)
+
+	_, err = pipe.Exec(ctx)
 
 	if err != nil {
 		return err
 	}
 
 	oncallerDeadlineSetKey := redis_keys.GetOncallerDeadlineSetKey()
-	oncallerResponseDeadline := incidentStartTime.Add(time.Duration(incidentInfo.AllowedResponseTime) * time.Minute).Unix()
+	oncallerResponseDeadline := incidentStartTime.Add(time.Duration(levels[0].Timeout) * time.Minute).Unix()
 
 	err = redisClient.ZAdd(ctx, oncallerDeadlineSetKey, redis.Z{
 		Score:  float64(oncallerResponseDeadline),
-		Member: incidentInfo.ServiceID,
+		Member: redis_keys.GetDeadlineMember(serviceID, kind),
 	}).Err()
 
 	if err != nil {
@@ -258,6 +650,7 @@ func (managerState *ManagerState) HandleNewIncident(ctx context.Context, service
 			context.Background(),
 			incidentInfo.IncidentID,
 			incidentInfo.ServiceID,
+			kind,
 			incidentStartTime,
 		)
 
@@ -266,32 +659,21 @@ func (managerState *ManagerState) HandleNewIncident(ctx context.Context, service
 		}
 	}()
 
-	go func() {
-		err := managerState.pubSubService.SendNotifyOncallerMessage(
-			context.Background(),
-			incidentInfo.IncidentID,
-			incidentInfo.ServiceID,
-			incidentInfo.FirstOncaller,
-			time.Now().UTC(),
-		)
-
-		if err != nil {
-			log.Printf("[ERROR] Failed to send notify oncaller message for service %d: %v", serviceID, err)
-		}
-	}()
+	managerState.notifyLevel(incidentInfo.IncidentID, serviceID, kind, targets, certificate)
 
 	return nil
 }
 
-func (managerState *ManagerState) HandleExpiredDeadline(ctx context.Context, serviceID uint64) error {
+func (managerState *ManagerState) HandleExpiredDeadline(ctx context.Context, serviceID uint64, kind string) error {
 	lock := managerState.LockService(serviceID)
 	defer lock.Unlock()
 
 	redisClient := db.GetRedisClient()
-	incidentKey := redis_keys.GetIncidentKey(serviceID)
+	incidentKey := redis_keys.GetIncidentKey(serviceID, kind)
 	oncallerDeadlineSetKey := redis_keys.GetOncallerDeadlineSetKey()
+	deadlineMember := redis_keys.GetDeadlineMember(serviceID, kind)
 
-	err := redisClient.ZRem(ctx, oncallerDeadlineSetKey, serviceID).Err()
+	err := redisClient.ZRem(ctx, oncallerDeadlineSetKey, deadlineMember).Err()
 
 	if err != nil {
 		if err == redis.Nil {
@@ -307,9 +689,9 @@ func (managerState *ManagerState) HandleExpiredDeadline(ctx context.Context, ser
 		return err
 	}
 
-	allowedResponseTime, err := strconv.Atoi(incident["allowed_response_time"])
-	if err != nil {
-		return err
+	if len(incident) == 0 {
+		log.Printf("[WARNING] No ongoing incident found for service %d", serviceID)
+		return nil
 	}
 
 	incidentStartTime, err := strconv.ParseInt(incident["incident_start_time"], 10, 64)
@@ -318,95 +700,108 @@ func (managerState *ManagerState) HandleExpiredDeadline(ctx context.Context, ser
 	}
 
 	incidentInfo := IncidentInfo{
-		IncidentID:          incident["incident_id"],
-		ServiceID:           serviceID,
-		State:               incident["state"],
-		IncidentStartTime:   incidentStartTime,
-		AllowedResponseTime: allowedResponseTime,
-		FirstOncaller:       incident["first_oncaller"],
-		SecondOncaller:      incident["second_oncaller"],
+		IncidentID:        incident["incident_id"],
+		ServiceID:         serviceID,
+		Kind:              kind,
+		State:             incident["state"],
+		IncidentStartTime: incidentStartTime,
+		CertIssuer:        incident["cert_issuer"],
+		CertSubject:       incident["cert_subject"],
+	}
+
+	var certificate *pubsub_common.CertificateInfo
+	if kind == pubsub_common.IncidentKindCertExpiry {
+		incidentInfo.CertNotAfter, _ = strconv.ParseInt(incident["cert_not_after"], 10, 64)
+		certificate = &pubsub_common.CertificateInfo{
+			NotAfter: time.Unix(incidentInfo.CertNotAfter, 0).UTC(),
+			Issuer:   incidentInfo.CertIssuer,
+			Subject:  incidentInfo.CertSubject,
+		}
 	}
 
-	var requestedOncaller string
 	switch incidentInfo.State {
-	case IncidentStateWaitingForFirstAck:
-		requestedOncaller = incidentInfo.FirstOncaller
-	case IncidentStateWaitingForSecondAck:
-		requestedOncaller = incidentInfo.SecondOncaller
+	case IncidentStateWaitingForAck, IncidentStateWaitingForFirstAck, IncidentStateWaitingForSecondAck:
+	case IncidentStateAcknowledged, IncidentStateResolved:
+		log.Printf("[DEBUG] Incident %s for service %d is %s, not escalating", incidentInfo.IncidentID, serviceID, incidentInfo.State)
+		return nil
 	default:
 		log.Printf("[WARNING] Unknown incident state for service %d: %s", serviceID, incidentInfo.State)
 		return nil
 	}
 
-	log.Printf("[DEBUG] Deadline expired for service %d, oncaller %s", serviceID, requestedOncaller)
+	levels, err := incidentEscalation(&incidentInfo, incident)
+	if err != nil {
+		return err
+	}
 
-	go func() {
-		err := managerState.pubSubService.SendAcknowledgeTimeoutMessage(
-			context.Background(),
-			incidentInfo.IncidentID,
-			serviceID,
-			requestedOncaller,
-			time.Now().UTC(),
-		)
+	if incidentInfo.Level >= len(levels) {
+		log.Printf("[WARNING] Unknown escalation level of service %d: %d", serviceID, incidentInfo.Level)
+		return nil
+	}
 
-		if err != nil {
-			log.Printf("[ERROR] Failed to send acknowledge timeout message for service %d: %v", serviceID, err)
-		}
-	}()
+	log.Printf("[DEBUG] Deadline expired for service %d at escalation level %d", serviceID, incidentInfo.Level)
 
-	switch incidentInfo.State {
-	case IncidentStateWaitingForFirstAck:
+	now := time.Now().UTC()
+	managerState.sendAcknowledgeTimeouts(incidentInfo.IncidentID, serviceID, notifiedTargets(incident, levels[incidentInfo.Level], now))
 
-		err = redisClient.HSet(ctx, incidentKey, "state", IncidentStateWaitingForSecondAck).Err()
-		if err != nil {
-			return err
+	level, cycle := incidentInfo.Level+1, incidentInfo.Cycle
+	if level == len(levels) {
+		if cycle >= incidentInfo.EscalationRepeat {
+			log.Printf("[DEBUG] Nobody responded in time. Marking incident as unresolved")
+			return managerState.handleIncidentUnresolved(ctx, serviceID, kind)
 		}
+		level, cycle = 0, cycle+1
+	}
 
-		if incidentInfo.SecondOncaller == "" {
-			log.Printf("[DEBUG] No second oncaller. Marking incident as unresolved")
-			return managerState.handleIncidentUnresolved(ctx, serviceID)
-		}
+	log.Printf("[DEBUG] Escalating incident %s to level %d, cycle %d", incidentInfo.IncidentID, level, cycle)
+
+	targets := levelTargets(levels[level], now)
+	notified, err := json.Marshal(targets)
+	if err != nil {
+		return err
+	}
 
-		log.Printf("[DEBUG] Second oncaller configured. Notifying %s", incidentInfo.SecondOncaller)
+	fields := []any{"state", IncidentStateWaitingForAck, "level", level, "cycle", cycle, "notified", string(notified)}
 
-		oncallerResponseDeadline := time.Now().UTC().Add(time.Duration(incidentInfo.AllowedResponseTime) * time.Minute).Unix()
+	// Incidents opened before escalation policies keep their oncallers as levels from now on
+	if incidentInfo.State != IncidentStateWaitingForAck {
+		escalation, err := json.Marshal(levels)
+		if err != nil {
+			return err
+		}
 
-		err = redisClient.ZAdd(ctx, oncallerDeadlineSetKey, redis.Z{
-			Score:  float64(oncallerResponseDeadline),
-			Member: incidentInfo.ServiceID,
-		}).Err()
+		fields = append(fields,
+			"escalation", string(escalation),
+			"escalation_repeat", incidentInfo.EscalationRepeat,
+			"allowed_response_time", incidentInfo.AllowedResponseTime,
+		)
+	}
 
-		go func() {
-			err := managerState.pubSubService.SendNotifyOncallerMessage(
-				context.Background(),
-				incidentInfo.IncidentID,
-				serviceID,
-				incidentInfo.SecondOncaller,
-				time.Now().UTC(),
-			)
+	err = redisClient.HSet(ctx, incidentKey, fields...).Err()
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				log.Printf("[ERROR] Failed to send notify oncaller message for service %d: %v", serviceID, err)
-			}
-		}()
+	oncallerResponseDeadline := now.Add(time.Duration(levels[level].Timeout) * time.Minute).Unix()
 
+	err = redisClient.ZAdd(ctx, oncallerDeadlineSetKey, redis.Z{
+		Score:  float64(oncallerResponseDeadline),
+		Member: deadlineMember,
+	}).Err()
+	if err != nil {
 		return err
-	case IncidentStateWaitingForSecondAck:
-		log.Printf("[DEBUG] Second oncaller did not respond in time. Marking incident as unresolved")
-		return managerState.handleIncidentUnresolved(ctx, serviceID)
-	default:
-		log.Printf("[WARNING] Unknown incident state for service %d: %s", serviceID, incidentInfo.State)
 	}
 
-	return nil
+	managerState.notifyLevel(incidentInfo.IncidentID, serviceID, kind, targets, certificate)
 
+	return nil
 }
 
 // Should be locked before calling
-func (managerState *ManagerState) handleIncidentUnresolved(ctx context.Context, serviceID uint64) error {
+func (managerState *ManagerState) handleIncidentUnresolved(ctx context.Context, serviceID uint64, kind string) error {
 	redisClient := db.GetRedisClient()
-	incidentKey := redis_keys.GetIncidentKey(serviceID)
-	downSinceKey := redis_keys.GetDownSinceKey(serviceID)
+	incidentKey := redis_keys.GetIncidentKey(serviceID, kind)
+	sinceKey := getSinceKey(serviceID, kind)
 
 	incidentID, err := redisClient.HGet(ctx, incidentKey, "incident_id").Result()
 
@@ -423,7 +818,9 @@ func (managerState *ManagerState) handleIncidentUnresolved(ctx context.Context,
 	pipe := redisClient.TxPipeline()
 
 	pipe.Del(ctx, incidentKey).Err()
-	pipe.Del(ctx, downSinceKey).Err()
+	if sinceKey != "" {
+		pipe.Del(ctx, sinceKey).Err()
+	}
 
 	_, err = pipe.Exec(ctx)
 
@@ -448,27 +845,101 @@ func (managerState *ManagerState) handleIncidentUnresolved(ctx context.Context,
 }
 
 // Should be locked before calling
-func (managerState *ManagerState) handleIncidentResolved(ctx context.Context, serviceID uint64, oncaller string) error {
+func (managerState *ManagerState) handleIncidentAcknowledged(ctx context.Context, serviceID uint64, kind string, oncaller string) error {
 	redisClient := db.GetRedisClient()
-	incidentKey := redis_keys.GetIncidentKey(serviceID)
-	downSinceKey := redis_keys.GetDownSinceKey(serviceID)
+	incidentKey := redis_keys.GetIncidentKey(serviceID, kind)
 
-	incidentID, err := redisClient.HGet(ctx, incidentKey, "incident_id").Result()
+	fields, err := redisClient.HMGet(ctx, incidentKey, "incident_id", "state").Result()
+	if err != nil {
+		return err
+	}
+
+	incidentID, _ := fields[0].(string)
+	if incidentID == "" {
+		log.Printf("[WARNING] No ongoing incident found for service %d", serviceID)
+		return nil
+	}
+
+	if state, _ := fields[1].(string); state == IncidentStateAcknowledged || state == IncidentStateResolved {
+		log.Printf("[DEBUG] Incident %s for service %d is already %s", incidentID, serviceID, state)
+		return nil
+	}
+
+	log.Printf("[DEBUG] Incident %s for service %d was acknowledged by %s", incidentID, serviceID, oncaller)
+
+	now := time.Now().UTC()
+
+	pipe := redisClient.TxPipeline()
+
+	pipe.HSet(ctx, incidentKey, "state", IncidentStateAcknowledged, "acknowledged_by", oncaller, "acknowledged_at", now.Unix())
+	pipe.ZRem(ctx, redis_keys.GetOncallerDeadlineSetKey(), redis_keys.GetDeadlineMember(serviceID, kind))
+
+	_, err = pipe.Exec(ctx)
 
 	if err != nil {
-		if err == redis.Nil {
-			log.Printf("[WARNING] No ongoing incident found for service %d", serviceID)
-			return nil
+		return err
+	}
+
+	go func() {
+		err := managerState.pubSubService.SendIncidentAcknowledgedMessage(
+			context.Background(),
+			incidentID,
+			serviceID,
+			oncaller,
+			now,
+		)
+
+		if err != nil {
+			log.Printf("[ERROR] Failed to send incident acknowledged message for service %d: %v", serviceID, err)
 		}
+	}()
+
+	return nil
+}
+
+// resolvedIncidentTTL keeps resolved incidents as long as the links in their notifications are valid
+const resolvedIncidentTTL = 72 * time.Hour
+
+// handleIncidentResolved closes the incident, acknowledged or not. The oncaller
+// is empty when the incident is resolved automatically.
+// Should be locked before calling
+func (managerState *ManagerState) handleIncidentResolved(ctx context.Context, serviceID uint64, kind string, oncaller string) error {
+	redisClient := db.GetRedisClient()
+	incidentKey := redis_keys.GetIncidentKey(serviceID, kind)
+	sinceKey := getSinceKey(serviceID, kind)
+
+	fields, err := redisClient.HMGet(ctx, incidentKey, "incident_id", "state").Result()
+	if err != nil {
 		return err
 	}
 
-	log.Printf("[DEBUG] Incident %s for service %d was resolved in time by %s", incidentID, serviceID, oncaller)
+	incidentID, _ := fields[0].(string)
+	if incidentID == "" {
+		log.Printf("[WARNING] No ongoing incident found for service %d", serviceID)
+		return nil
+	}
+
+	if state, _ := fields[1].(string); state == IncidentStateResolved {
+		log.Printf("[DEBUG] Incident %s for service %d is already resolved", incidentID, serviceID)
+		return nil
+	}
+
+	if oncaller == "" {
+		log.Printf("[DEBUG] Incident %s for service %d was resolved automatically", incidentID, serviceID)
+	} else {
+		log.Printf("[DEBUG] Incident %s for service %d was resolved by %s", incidentID, serviceID, oncaller)
+	}
+
+	now := time.Now().UTC()
 
 	pipe := redisClient.TxPipeline()
 
-	pipe.Del(ctx, incidentKey).Err()
-	pipe.Del(ctx, downSinceKey).Err()
+	pipe.HSet(ctx, incidentKey, "state", IncidentStateResolved, "resolved_by", oncaller, "resolved_at", now.Unix())
+	pipe.Expire(ctx, incidentKey, resolvedIncidentTTL)
+	pipe.ZRem(ctx, redis_keys.GetOncallerDeadlineSetKey(), redis_keys.GetDeadlineMember(serviceID, kind))
+	if sinceKey != "" {
+		pipe.Del(ctx, sinceKey)
+	}
 
 	_, err = pipe.Exec(ctx)
 
@@ -482,7 +953,7 @@ func (managerState *ManagerState) handleIncidentResolved(ctx context.Context, se
 			incidentID,
 			serviceID,
 			oncaller,
-			time.Now().UTC(),
+			now,
 		)
 
 		if err != nil {
@@ -13,6 +13,7 @@ import (
 
 	pubsub_internal "alerting-plafform/incident-manager/pubsub"
 	"alerting-platform/common/db"
+	"alerting-platform/common/oncall"
 	pubsub_common "alerting-platform/common/pubsub"
 
 	"github.com/alicebob/miniredis/v2"
@@ -65,6 +66,50 @@ func TestHandleServiceUp(t *testing.T) {
 		assert.False(t, s.Exists(downSinceKey))
 	})
 
+	t.Run("Auto Resolve", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = ServiceInfo{ID: serviceID, AutoResolve: true}
+
+		downKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		certKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindCertExpiry)
+		s.HSet(downKey, "incident_id", "down-incident", "state", IncidentStateAcknowledged)
+		s.HSet(certKey, "incident_id", "cert-incident", "state", IncidentStateWaitingForAck)
+
+		mockPubSub.On("SendIncidentRecoveredMessage", mock.Anything, "down-incident", serviceID, pubsub_common.IncidentKindDown, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendIncidentResolvedMessage", mock.Anything, "down-incident", serviceID, "", mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleServiceUp(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, IncidentStateResolved, s.HGet(downKey, "state"))
+		assert.Equal(t, "", s.HGet(downKey, "resolved_by"))
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(certKey, "state"))
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Without Auto Resolve", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = ServiceInfo{ID: serviceID}
+
+		downKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(downKey, "incident_id", "down-incident", "state", IncidentStateAcknowledged)
+
+		mockPubSub.On("SendIncidentRecoveredMessage", mock.Anything, "down-incident", serviceID, pubsub_common.IncidentKindDown, mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleServiceUp(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, IncidentStateAcknowledged, s.HGet(downKey, "state"))
+		mockPubSub.AssertNotCalled(t, "SendIncidentResolvedMessage")
+		mockPubSub.AssertExpectations(t)
+	})
+
 	t.Run("Error on Redis Exec", func(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
@@ -76,6 +121,262 @@ func TestHandleServiceUp(t *testing.T) {
 	})
 }
 
+func TestHandleServiceDegraded(t *testing.T) {
+	ctx := context.Background()
+	serviceID := uint64(1)
+	alertWindow := 5
+	service := ServiceInfo{
+		ID:                  serviceID,
+		AlertWindow:         alertWindow,
+		AllowedResponseTime: 10,
+		LatencyThreshold:    500,
+		Oncallers:           []string{"test@oncaller.com"},
+	}
+	slowPayload := pubsub_common.PubSubPayload{
+		ServiceID: serviceID,
+		Data:      pubsub_common.PubSubPayloadData{Result: &pubsub_common.CheckResult{ResponseTimeMs: 800}},
+	}
+
+	t.Run("Slow Check Marks Service Degraded", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = service
+		s.Set(redis_keys.GetDownSinceKey(serviceID), "12345")
+
+		now := time.Now()
+		err := managerState.HandleServiceUp(ctx, slowPayload, now)
+		assert.NoError(t, err)
+
+		status, err := s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.NoError(t, err)
+		assert.Equal(t, "DEGRADED", status)
+		assert.False(t, s.Exists(redis_keys.GetDownSinceKey(serviceID)))
+
+		degradedSince, err := s.Get(redis_keys.GetDegradedSinceKey(serviceID))
+		assert.NoError(t, err)
+		assert.Equal(t, strconv.FormatInt(now.Unix(), 10), degradedSince)
+	})
+
+	t.Run("Fast Check Clears Degraded", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = service
+		s.Set(redis_keys.GetDegradedSinceKey(serviceID), "12345")
+
+		fastPayload := pubsub_common.PubSubPayload{
+			ServiceID: serviceID,
+			Data:      pubsub_common.PubSubPayloadData{Result: &pubsub_common.CheckResult{ResponseTimeMs: 100}},
+		}
+
+		err := managerState.HandleServiceUp(ctx, fastPayload, time.Now())
+		assert.NoError(t, err)
+
+		status, err := s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.NoError(t, err)
+		assert.Equal(t, "UP", status)
+		assert.False(t, s.Exists(redis_keys.GetDegradedSinceKey(serviceID)))
+	})
+
+	t.Run("No Threshold Keeps Service Up", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		noThreshold := service
+		noThreshold.LatencyThreshold = 0
+		managerState.services[serviceID] = noThreshold
+
+		err := managerState.HandleServiceUp(ctx, slowPayload, time.Now())
+		assert.NoError(t, err)
+
+		status, err := s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.NoError(t, err)
+		assert.Equal(t, "UP", status)
+	})
+
+	t.Run("Already Degraded, Create Incident", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = service
+		degradedSince := time.Now().UTC().Add(-time.Duration(alertWindow+1) * time.Second)
+		s.Set(redis_keys.GetDegradedSinceKey(serviceID), strconv.FormatInt(degradedSince.Unix(), 10))
+
+		incidentID := fmt.Sprintf("%d-%d-degraded", serviceID, degradedSince.Unix())
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, incidentID, serviceID, pubsub_common.IncidentKindDegraded, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, incidentID, serviceID, pubsub_common.IncidentKindDegraded, "test@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleServiceUp(ctx, slowPayload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.True(t, s.Exists(redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDegraded)))
+		assert.False(t, s.Exists(redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)))
+
+		members, err := s.ZMembers(redis_keys.GetOncallerDeadlineSetKey())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{redis_keys.GetDeadlineMember(serviceID, pubsub_common.IncidentKindDegraded)}, members)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Down Resets Degraded Since", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = service
+		s.Set(redis_keys.GetDegradedSinceKey(serviceID), "12345")
+
+		err := managerState.HandleServiceDown(ctx, pubsub_common.PubSubPayload{ServiceID: serviceID}, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, s.Exists(redis_keys.GetDegradedSinceKey(serviceID)))
+	})
+}
+
+func TestHandleCertificateExpiry(t *testing.T) {
+	ctx := context.Background()
+	serviceID := uint64(1)
+	service := ServiceInfo{
+		ID:                  serviceID,
+		AlertWindow:         60,
+		AllowedResponseTime: 10,
+		CertExpiryDays:      14,
+		Oncallers:           []string{"first@oncaller.com", "second@oncaller.com"},
+	}
+	certPayload := func(notAfter time.Time) pubsub_common.PubSubPayload {
+		return pubsub_common.PubSubPayload{
+			ServiceID: serviceID,
+			Data: pubsub_common.PubSubPayloadData{Result: &pubsub_common.CheckResult{
+				Certificate: &pubsub_common.CertificateInfo{
+					NotAfter: notAfter,
+					Issuer:   "CN=Test CA",
+					Subject:  "CN=example.com",
+				},
+			}},
+		}
+	}
+	certKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindCertExpiry)
+
+	t.Run("Expiring Soon Opens Incident Once", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = service
+		payload := certPayload(time.Now().Add(3 * 24 * time.Hour).Truncate(time.Second))
+
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindCertExpiry, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindCertExpiry, "first@oncaller.com", payload.Data.Result.Certificate, mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleServiceUp(ctx, payload, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, "CN=Test CA", s.HGet(certKey, "cert_issuer"))
+
+		// The same certificate does not open another incident, even after acknowledgement
+		s.Del(certKey)
+		err = managerState.HandleServiceDown(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, s.Exists(certKey))
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Far From Expiry", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = service
+
+		err := managerState.HandleServiceUp(ctx, certPayload(time.Now().Add(30*24*time.Hour)), time.Now())
+		assert.NoError(t, err)
+		assert.False(t, s.Exists(certKey))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		disabled := service
+		disabled.CertExpiryDays = 0
+		managerState.services[serviceID] = disabled
+
+		err := managerState.HandleServiceUp(ctx, certPayload(time.Now().Add(time.Hour)), time.Now())
+		assert.NoError(t, err)
+		assert.False(t, s.Exists(certKey))
+	})
+
+	t.Run("Escalation Repeats Certificate", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+		s.HSet(certKey,
+			"incident_id", "cert-incident",
+			"state", IncidentStateWaitingForFirstAck,
+			"incident_start_time", "0",
+			"allowed_response_time", "10",
+			"first_oncaller", "first@oncaller.com",
+			"second_oncaller", "second@oncaller.com",
+			"cert_not_after", strconv.FormatInt(notAfter.Unix(), 10),
+			"cert_issuer", "CN=Test CA",
+			"cert_subject", "CN=example.com",
+		)
+
+		certificate := &pubsub_common.CertificateInfo{NotAfter: notAfter, Issuer: "CN=Test CA", Subject: "CN=example.com"}
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "cert-incident", serviceID, "first@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, "cert-incident", serviceID, pubsub_common.IncidentKindCertExpiry, "second@oncaller.com", certificate, mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindCertExpiry)
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertExpectations(t)
+	})
+}
+
+func TestHandleMonitoringGap(t *testing.T) {
+	ctx := context.Background()
+	serviceID := uint64(1)
+	now := time.Now().UTC()
+	payload := pubsub_common.PubSubPayload{
+		ServiceID: serviceID,
+		Data: pubsub_common.PubSubPayloadData{
+			Gap: &pubsub_common.MonitoringGap{Since: now.Add(-10 * time.Minute), Until: now},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		serviceStatusKey := redis_keys.GetServiceStatusKey(serviceID)
+		downSinceKey := redis_keys.GetDownSinceKey(serviceID)
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.Set(serviceStatusKey, "UP")
+		s.Set(downSinceKey, "12345")
+		s.Set(incidentKey, "incident-1")
+
+		err := managerState.HandleMonitoringGap(ctx, payload, now)
+		assert.NoError(t, err)
+
+		status, err := s.Get(serviceStatusKey)
+		assert.NoError(t, err)
+		assert.Equal(t, pubsub_common.StatusNoData, status)
+		assert.False(t, s.Exists(downSinceKey))
+		assert.True(t, s.Exists(incidentKey))
+	})
+
+	t.Run("Error on Redis Exec", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		s.SetError("redis error")
+
+		err := managerState.HandleMonitoringGap(ctx, payload, now)
+		assert.Error(t, err)
+	})
+}
+
 func TestHandleServiceCreated(t *testing.T) {
 	ctx := context.Background()
 	payload := pubsub_common.PubSubPayload{
@@ -84,6 +385,8 @@ func TestHandleServiceCreated(t *testing.T) {
 			AlertWindow:         300,
 			AllowedResponseTime: 5,
 			Oncallers:           []string{"test@oncaller.com"},
+			Escalation:          []pubsub_common.EscalationLevel{{Targets: []string{"test@oncaller.com"}, Timeout: 3}},
+			EscalationRepeat:    2,
 		},
 	}
 
@@ -98,6 +401,8 @@ func TestHandleServiceCreated(t *testing.T) {
 		assert.Equal(t, payload.Data.AlertWindow, service.AlertWindow)
 		assert.Equal(t, payload.Data.AllowedResponseTime, service.AllowedResponseTime)
 		assert.Equal(t, payload.Data.Oncallers, service.Oncallers)
+		assert.Equal(t, payload.Data.Escalation, service.Escalation)
+		assert.Equal(t, 2, service.EscalationRepeat)
 	})
 }
 
@@ -170,17 +475,73 @@ func TestHandleServiceDown(t *testing.T) {
 		}
 
 		s.Set(redis_keys.GetDownSinceKey(serviceID), strconv.FormatInt(downSince.Unix(), 10))
-		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 		err := managerState.HandleServiceDown(ctx, payload, time.Now())
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
-		assert.True(t, s.Exists(redis_keys.GetIncidentKey(serviceID)))
+		assert.True(t, s.Exists(redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)))
 		mockPubSub.AssertExpectations(t)
 	})
 
+	t.Run("Resolved Incident Replaced", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		downSince := time.Now().UTC().Add(-time.Minute)
+		managerState.services[serviceID] = ServiceInfo{
+			ID:                  serviceID,
+			AlertWindow:         5,
+			AllowedResponseTime: 10,
+			Oncallers:           []string{"test@oncaller.com"},
+		}
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey, "incident_id", "old-incident", "state", IncidentStateResolved, "resolved_by", "test@oncaller.com")
+		s.SetTTL(incidentKey, resolvedIncidentTTL)
+		s.Set(redis_keys.GetDownSinceKey(serviceID), strconv.FormatInt(downSince.Unix(), 10))
+
+		newIncidentID := fmt.Sprintf("%d-%d", serviceID, downSince.Unix())
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, newIncidentID, serviceID, pubsub_common.IncidentKindDown, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, newIncidentID, serviceID, pubsub_common.IncidentKindDown, "test@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleServiceDown(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, newIncidentID, s.HGet(incidentKey, "incident_id"))
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(incidentKey, "state"))
+		assert.Equal(t, "", s.HGet(incidentKey, "resolved_by"))
+		assert.Zero(t, s.TTL(incidentKey))
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Acknowledged Incident Stays Open", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		downSince := time.Now().UTC().Add(-time.Minute)
+		managerState.services[serviceID] = ServiceInfo{
+			ID:                  serviceID,
+			AlertWindow:         5,
+			AllowedResponseTime: 10,
+			Oncallers:           []string{"test@oncaller.com"},
+		}
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey, "incident_id", "test-incident", "state", IncidentStateAcknowledged)
+		s.Set(redis_keys.GetDownSinceKey(serviceID), strconv.FormatInt(downSince.Unix(), 10))
+
+		err := managerState.HandleServiceDown(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, "test-incident", s.HGet(incidentKey, "incident_id"))
+		mockPubSub.AssertNotCalled(t, "SendIncidentStartMessage")
+	})
+
 	t.Run("Error on Set Status", func(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
@@ -209,6 +570,99 @@ func TestHandleServiceDown(t *testing.T) {
 	})
 }
 
+func TestHandleServiceLocationQuorum(t *testing.T) {
+	ctx := context.Background()
+	serviceID := uint64(1)
+	round := int64(1700000000000)
+
+	resultPayload := func(location string) pubsub_common.PubSubPayload {
+		return pubsub_common.PubSubPayload{
+			ServiceID: serviceID,
+			Data: pubsub_common.PubSubPayloadData{
+				Result: &pubsub_common.CheckResult{Location: location, Round: round},
+			},
+		}
+	}
+
+	setup := func(t *testing.T) (*miniredis.Miniredis, *ManagerState) {
+		s, _, _, managerState := setupTestState(t)
+		managerState.services[serviceID] = ServiceInfo{
+			ID:          serviceID,
+			AlertWindow: 60,
+			Locations:   3,
+		}
+		s.Set(redis_keys.GetServiceStatusKey(serviceID), "UP")
+		return s, managerState
+	}
+
+	t.Run("Single location down is ignored", func(t *testing.T) {
+		s, managerState := setup(t)
+		defer s.Close()
+
+		assert.NoError(t, managerState.HandleServiceDown(ctx, resultPayload("eu-west"), time.Now()))
+		assert.NoError(t, managerState.HandleServiceUp(ctx, resultPayload("us-east"), time.Now()))
+
+		status, _ := s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.Equal(t, "UP", status)
+		assert.False(t, s.Exists(redis_keys.GetDownSinceKey(serviceID)))
+	})
+
+	t.Run("Quorum of locations down", func(t *testing.T) {
+		s, managerState := setup(t)
+		defer s.Close()
+
+		assert.NoError(t, managerState.HandleServiceDown(ctx, resultPayload("eu-west"), time.Now()))
+		assert.False(t, s.Exists(redis_keys.GetDownSinceKey(serviceID)))
+
+		assert.NoError(t, managerState.HandleServiceDown(ctx, resultPayload("us-east"), time.Now()))
+
+		status, _ := s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.Equal(t, "DOWN", status)
+		assert.True(t, s.Exists(redis_keys.GetDownSinceKey(serviceID)))
+
+		// The last location cannot overturn a settled round
+		assert.NoError(t, managerState.HandleServiceUp(ctx, resultPayload("ap-south"), time.Now()))
+
+		status, _ = s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.Equal(t, "DOWN", status)
+	})
+
+	t.Run("Redelivered result counts once", func(t *testing.T) {
+		s, managerState := setup(t)
+		defer s.Close()
+
+		assert.NoError(t, managerState.HandleServiceDown(ctx, resultPayload("eu-west"), time.Now()))
+		assert.NoError(t, managerState.HandleServiceDown(ctx, resultPayload("eu-west"), time.Now()))
+
+		status, _ := s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.Equal(t, "UP", status)
+	})
+
+	t.Run("Custom quorum", func(t *testing.T) {
+		s, managerState := setup(t)
+		defer s.Close()
+
+		service := managerState.services[serviceID]
+		service.LocationQuorum = 1
+		managerState.services[serviceID] = service
+
+		assert.NoError(t, managerState.HandleServiceDown(ctx, resultPayload("eu-west"), time.Now()))
+
+		status, _ := s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.Equal(t, "DOWN", status)
+	})
+
+	t.Run("Results without a round apply at once", func(t *testing.T) {
+		s, managerState := setup(t)
+		defer s.Close()
+
+		assert.NoError(t, managerState.HandleServiceDown(ctx, pubsub_common.PubSubPayload{ServiceID: serviceID}, time.Now()))
+
+		status, _ := s.Get(redis_keys.GetServiceStatusKey(serviceID))
+		assert.Equal(t, "DOWN", status)
+	})
+}
+
 func TestHandleServiceRemoved(t *testing.T) {
 	ctx := context.Background()
 	serviceID := uint64(1)
@@ -219,7 +673,7 @@ func TestHandleServiceRemoved(t *testing.T) {
 		defer s.Close()
 
 		managerState.services[serviceID] = ServiceInfo{ID: serviceID}
-		s.Set(redis_keys.GetIncidentKey(serviceID), "incident-data")
+		s.Set(redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown), "incident-data")
 		s.ZAdd(redis_keys.GetOncallerDeadlineSetKey(), 1, fmt.Sprintf("%d", serviceID))
 
 		err := managerState.HandleServiceRemoved(ctx, payload, time.Now())
@@ -227,7 +681,7 @@ func TestHandleServiceRemoved(t *testing.T) {
 
 		_, exists := managerState.services[serviceID]
 		assert.False(t, exists)
-		assert.False(t, s.Exists(redis_keys.GetIncidentKey(serviceID)))
+		assert.False(t, s.Exists(redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)))
 		members, err := rclient.ZRange(t.Context(), redis_keys.GetOncallerDeadlineSetKey(), 0, -1).Result()
 		assert.NoError(t, err)
 		assert.Empty(t, members)
@@ -237,7 +691,7 @@ func TestHandleServiceRemoved(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
 
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
 		s.Set(incidentKey, "some-data")
 
 		s.SetError("redis error")
@@ -264,27 +718,153 @@ func TestHandleServiceRemoved(t *testing.T) {
 
 func TestHandleOncallerAcknowledged(t *testing.T) {
 	ctx := context.Background()
-	payload := pubsub_common.PubSubPayload{
-		ServiceID: 1,
-		OnCaller:  "test@oncaller.com",
-	}
+	serviceID := uint64(1)
+	oncaller := "test@oncaller.com"
+	payload := pubsub_common.PubSubPayload{ServiceID: serviceID, IncidentID: "test-incident", OnCaller: oncaller}
 
 	t.Run("Success", func(t *testing.T) {
 		s, _, mockPubSub, managerState := setupTestState(t)
 		defer s.Close()
 
-		incidentKey := redis_keys.GetIncidentKey(payload.ServiceID)
-		s.HSet(incidentKey, "incident_id", "test-incident")
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		downSinceKey := redis_keys.GetDownSinceKey(serviceID)
+		oncallerDeadlineSetKey := redis_keys.GetOncallerDeadlineSetKey()
+		deadlineMember := redis_keys.GetDeadlineMember(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey, "incident_id", "test-incident", "state", IncidentStateWaitingForAck)
+		s.Set(downSinceKey, "12345")
+		s.ZAdd(oncallerDeadlineSetKey, float64(time.Now().Add(time.Minute).Unix()), deadlineMember)
 
-		mockPubSub.On("SendIncidentResolvedMessage", mock.Anything, "test-incident", payload.ServiceID, payload.OnCaller, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendIncidentAcknowledgedMessage", mock.Anything, "test-incident", serviceID, oncaller, mock.Anything).Return(nil).Once()
 
 		err := managerState.HandleOncallerAcknowledged(ctx, payload, time.Now())
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
-		assert.False(t, s.Exists(incidentKey))
+		assert.Equal(t, IncidentStateAcknowledged, s.HGet(incidentKey, "state"))
+		assert.Equal(t, oncaller, s.HGet(incidentKey, "acknowledged_by"))
+		assert.True(t, s.Exists(downSinceKey))
+		members, _ := s.ZMembers(oncallerDeadlineSetKey)
+		assert.NotContains(t, members, deadlineMember)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Already Acknowledged", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey, "incident_id", "test-incident", "state", IncidentStateAcknowledged, "acknowledged_by", "first@oncaller.com")
+
+		err := managerState.HandleOncallerAcknowledged(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, "first@oncaller.com", s.HGet(incidentKey, "acknowledged_by"))
+		mockPubSub.AssertNotCalled(t, "SendIncidentAcknowledgedMessage")
+	})
+
+	t.Run("Link To Older Incident", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey, "incident_id", "newer-incident", "state", IncidentStateWaitingForAck)
+
+		err := managerState.HandleOncallerAcknowledged(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(incidentKey, "state"))
+		mockPubSub.AssertNotCalled(t, "SendIncidentAcknowledgedMessage")
+	})
+
+	t.Run("Error on HGet", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+		s.SetError("redis error")
+		err := managerState.HandleOncallerAcknowledged(ctx, payload, time.Now())
+		assert.Error(t, err)
+		s.SetError("")
+	})
+}
+
+func TestHandleOncallerAcknowledgedDegraded(t *testing.T) {
+	ctx := context.Background()
+	serviceID := uint64(1)
+	oncaller := "test@oncaller.com"
+
+	s, _, mockPubSub, managerState := setupTestState(t)
+	defer s.Close()
+
+	downKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+	degradedKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDegraded)
+	s.HSet(downKey, "incident_id", "down-incident", "state", IncidentStateWaitingForAck)
+	s.HSet(degradedKey, "incident_id", "degraded-incident", "state", IncidentStateWaitingForAck)
+
+	mockPubSub.On("SendIncidentAcknowledgedMessage", mock.Anything, "degraded-incident", serviceID, oncaller, mock.Anything).Return(nil).Once()
+
+	payload := pubsub_common.PubSubPayload{ServiceID: serviceID, IncidentID: "degraded-incident", OnCaller: oncaller}
+	err := managerState.HandleOncallerAcknowledged(ctx, payload, time.Now())
+	assert.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, IncidentStateWaitingForAck, s.HGet(downKey, "state"))
+	assert.Equal(t, IncidentStateAcknowledged, s.HGet(degradedKey, "state"))
+	mockPubSub.AssertExpectations(t)
+}
+
+func TestHandleOncallerResolved(t *testing.T) {
+	ctx := context.Background()
+	serviceID := uint64(1)
+	oncaller := "test@oncaller.com"
+	payload := pubsub_common.PubSubPayload{ServiceID: serviceID, IncidentID: "test-incident", OnCaller: oncaller}
+
+	t.Run("Acknowledged Incident", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey, "incident_id", "test-incident", "state", IncidentStateAcknowledged)
+
+		mockPubSub.On("SendIncidentResolvedMessage", mock.Anything, "test-incident", serviceID, oncaller, mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleOncallerResolved(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, IncidentStateResolved, s.HGet(incidentKey, "state"))
+		assert.Equal(t, oncaller, s.HGet(incidentKey, "resolved_by"))
 		mockPubSub.AssertExpectations(t)
 	})
+
+	t.Run("Already Resolved", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey, "incident_id", "test-incident", "state", IncidentStateResolved)
+
+		err := managerState.HandleOncallerResolved(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertNotCalled(t, "SendIncidentResolvedMessage")
+	})
+
+	t.Run("Acknowledge After Resolve", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey, "incident_id", "test-incident", "state", IncidentStateResolved)
+
+		err := managerState.HandleOncallerAcknowledged(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, IncidentStateResolved, s.HGet(incidentKey, "state"))
+		mockPubSub.AssertNotCalled(t, "SendIncidentAcknowledgedMessage")
+	})
 }
 
 func TestHandleNewIncident(t *testing.T) {
@@ -292,6 +872,77 @@ func TestHandleNewIncident(t *testing.T) {
 	serviceID := uint64(1)
 	incidentStartTime := time.Now().UTC()
 
+	t.Run("Escalation Policy", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = ServiceInfo{
+			ID:                  serviceID,
+			AllowedResponseTime: 5,
+			Oncallers:           []string{"ignored@oncaller.com"},
+			Escalation: []pubsub_common.EscalationLevel{
+				{Targets: []string{"first@oncaller.com", "backup@oncaller.com"}, Timeout: 2},
+				{Targets: []string{"lead@oncaller.com"}, Timeout: 10},
+			},
+			EscalationRepeat: 1,
+		}
+
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindDown, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindDown, "first@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindDown, "backup@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleNewIncident(ctx, serviceID, pubsub_common.IncidentKindDown, incidentStartTime, nil)
+		assert.NoError(t, err)
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(incidentKey, "state"))
+		assert.Equal(t, "0", s.HGet(incidentKey, "level"))
+		assert.Equal(t, "1", s.HGet(incidentKey, "escalation_repeat"))
+		assert.Contains(t, s.HGet(incidentKey, "escalation"), "lead@oncaller.com")
+
+		deadline, err := s.ZScore(redis_keys.GetOncallerDeadlineSetKey(), redis_keys.GetDeadlineMember(serviceID, pubsub_common.IncidentKindDown))
+		assert.NoError(t, err)
+		assert.Equal(t, float64(incidentStartTime.Add(2*time.Minute).Unix()), deadline)
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Schedule Resolved At Alert Time", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		rotationStart := time.Now().UTC().Add(-24 * time.Hour)
+		managerState.services[serviceID] = ServiceInfo{
+			ID: serviceID,
+			Escalation: []pubsub_common.EscalationLevel{{
+				Targets: []string{"lead@oncaller.com"},
+				Schedules: []oncall.Schedule{{
+					Layers: []oncall.Layer{{
+						Users:        []string{"week@oncaller.com", "next@oncaller.com"},
+						Start:        rotationStart.Format(oncall.LocalTimeLayout),
+						RotationDays: 7,
+					}},
+					Overrides: []oncall.Override{{User: "holiday@oncaller.com", Start: rotationStart.Add(-time.Hour), End: rotationStart.Add(time.Hour)}},
+				}},
+				Timeout: 5,
+			}},
+		}
+
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindDown, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindDown, "lead@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, serviceID, pubsub_common.IncidentKindDown, "week@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleNewIncident(ctx, serviceID, pubsub_common.IncidentKindDown, incidentStartTime, nil)
+		assert.NoError(t, err)
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		assert.JSONEq(t, `["lead@oncaller.com","week@oncaller.com"]`, s.HGet(incidentKey, "notified"))
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertExpectations(t)
+	})
+
 	t.Run("Error on HSet", func(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
@@ -303,7 +954,7 @@ func TestHandleNewIncident(t *testing.T) {
 
 		s.SetError("redis error")
 
-		err := managerState.HandleNewIncident(ctx, serviceID, incidentStartTime)
+		err := managerState.HandleNewIncident(ctx, serviceID, pubsub_common.IncidentKindDown, incidentStartTime, nil)
 		assert.Error(t, err)
 		s.SetError("")
 	})
@@ -319,7 +970,7 @@ func TestHandleNewIncident(t *testing.T) {
 
 		s.SetError("redis error")
 
-		err := managerState.HandleNewIncident(ctx, serviceID, incidentStartTime)
+		err := managerState.HandleNewIncident(ctx, serviceID, pubsub_common.IncidentKindDown, incidentStartTime, nil)
 		assert.Error(t, err)
 		s.SetError("")
 	})
@@ -334,10 +985,10 @@ func TestHandleNewIncident(t *testing.T) {
 			Oncallers:           []string{"test@oncaller.com"},
 		}
 
-		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("pubsub error")).Once()
-		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("pubsub error")).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 
-		err := managerState.HandleNewIncident(ctx, serviceID, incidentStartTime)
+		err := managerState.HandleNewIncident(ctx, serviceID, pubsub_common.IncidentKindDown, incidentStartTime, nil)
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
@@ -354,10 +1005,10 @@ func TestHandleNewIncident(t *testing.T) {
 			Oncallers:           []string{"test@oncaller.com"},
 		}
 
-		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
-		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("pubsub error")).Once()
+		mockPubSub.On("SendIncidentStartMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("pubsub error")).Once()
 
-		err := managerState.HandleNewIncident(ctx, serviceID, incidentStartTime)
+		err := managerState.HandleNewIncident(ctx, serviceID, pubsub_common.IncidentKindDown, incidentStartTime, nil)
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
@@ -369,54 +1020,76 @@ func TestHandleExpiredDeadline(t *testing.T) {
 	ctx := context.Background()
 	serviceID := uint64(1)
 
-	t.Run("Success - Escalate to Second Oncaller", func(t *testing.T) {
+	escalatingIncident := func(s *miniredis.Miniredis, level int, cycle int, repeat int) string {
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey,
+			"incident_id", "test-incident",
+			"state", IncidentStateWaitingForAck,
+			"incident_start_time", strconv.Itoa(int(time.Now().Unix())),
+			"escalation", `[{"targets":["first@oncaller.com","backup@oncaller.com"],"timeout":5},{"targets":["lead@oncaller.com"],"timeout":15}]`,
+			"escalation_repeat", strconv.Itoa(repeat),
+			"level", strconv.Itoa(level),
+			"cycle", strconv.Itoa(cycle),
+		)
+		return incidentKey
+	}
+
+	t.Run("Success - Escalate to Next Level", func(t *testing.T) {
 		s, _, mockPubSub, managerState := setupTestState(t)
 		defer s.Close()
 
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
-		incidentInfo := IncidentInfo{
-			IncidentID:          "test-incident",
-			State:               IncidentStateWaitingForFirstAck,
-			AllowedResponseTime: 5,
-			FirstOncaller:       "first@oncaller.com",
-			SecondOncaller:      "second@oncaller.com",
-		}
-		s.HSet(incidentKey, "incident_id", incidentInfo.IncidentID, "state", incidentInfo.State, "allowed_response_time", strconv.Itoa(incidentInfo.AllowedResponseTime), "first_oncaller", incidentInfo.FirstOncaller, "second_oncaller", incidentInfo.SecondOncaller, "incident_start_time", strconv.Itoa(int(time.Now().Unix())))
-		// Set the incident start time to the current time
-		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, incidentInfo.IncidentID, serviceID, incidentInfo.FirstOncaller, mock.Anything).Return(nil).Once()
-		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, incidentInfo.IncidentID, serviceID, incidentInfo.SecondOncaller, mock.Anything).Return(nil).Once()
+		incidentKey := escalatingIncident(s, 0, 0, 0)
+
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "first@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "backup@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, "lead@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
 
-		err := managerState.HandleExpiredDeadline(ctx, serviceID)
+		before := time.Now()
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.NoError(t, err)
 
-		state := s.HGet(incidentKey, "state")
-		assert.Equal(t, IncidentStateWaitingForSecondAck, state)
+		assert.Equal(t, "1", s.HGet(incidentKey, "level"))
+		assert.Equal(t, "0", s.HGet(incidentKey, "cycle"))
 
-		deadlineSetKey := redis_keys.GetOncallerDeadlineSetKey()
-		assert.True(t, s.Exists(deadlineSetKey))
+		// The deadline follows the timeout of the new level
+		deadline, err := s.ZScore(redis_keys.GetOncallerDeadlineSetKey(), redis_keys.GetDeadlineMember(serviceID, pubsub_common.IncidentKindDown))
+		assert.NoError(t, err)
+		assert.InDelta(t, float64(before.Add(15*time.Minute).Unix()), deadline, 1)
 
 		time.Sleep(100 * time.Millisecond)
 		mockPubSub.AssertExpectations(t)
 	})
 
-	t.Run("Success - Unresolved", func(t *testing.T) {
+	t.Run("Success - Repeat From First Level", func(t *testing.T) {
 		s, _, mockPubSub, managerState := setupTestState(t)
 		defer s.Close()
 
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
-		incidentInfo := IncidentInfo{
-			IncidentID:          "test-incident",
-			State:               IncidentStateWaitingForSecondAck,
-			AllowedResponseTime: 5,
-			FirstOncaller:       "first@oncaller.com",
-			SecondOncaller:      "second@oncaller.com",
-		}
-		s.HSet(incidentKey, "incident_id", incidentInfo.IncidentID, "state", incidentInfo.State, "allowed_response_time", strconv.Itoa(incidentInfo.AllowedResponseTime), "first_oncaller", incidentInfo.FirstOncaller, "second_oncaller", incidentInfo.SecondOncaller, "incident_start_time", strconv.Itoa(int(time.Now().Unix())))
+		incidentKey := escalatingIncident(s, 1, 0, 1)
+
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "lead@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, "first@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, "backup@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
 
-		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, incidentInfo.IncidentID, serviceID, incidentInfo.SecondOncaller, mock.Anything).Return(nil).Once()
-		mockPubSub.On("SendIncidentUnresolvedMessage", mock.Anything, incidentInfo.IncidentID, serviceID, mock.Anything).Return(nil).Once()
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "0", s.HGet(incidentKey, "level"))
+		assert.Equal(t, "1", s.HGet(incidentKey, "cycle"))
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Success - Unresolved After Last Repeat", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
 
-		err := managerState.HandleExpiredDeadline(ctx, serviceID)
+		incidentKey := escalatingIncident(s, 1, 1, 1)
+
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "lead@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendIncidentUnresolvedMessage", mock.Anything, "test-incident", serviceID, mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
@@ -424,11 +1097,110 @@ func TestHandleExpiredDeadline(t *testing.T) {
 		mockPubSub.AssertExpectations(t)
 	})
 
+	t.Run("Success - Timeout Goes To Whoever Was Notified", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		// The rotation handed off since the level was notified
+		incidentKey := escalatingIncident(s, 1, 0, 0)
+		s.HSet(incidentKey, "notified", `["lead@oncaller.com","last-week@oncaller.com"]`)
+
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "lead@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "last-week@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendIncidentUnresolvedMessage", mock.Anything, "test-incident", serviceID, mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Success - Incident Opened Before Escalation Policies", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey,
+			"incident_id", "test-incident",
+			"state", IncidentStateWaitingForFirstAck,
+			"allowed_response_time", "5",
+			"first_oncaller", "first@oncaller.com",
+			"second_oncaller", "second@oncaller.com",
+			"incident_start_time", strconv.Itoa(int(time.Now().Unix())),
+		)
+
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "first@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyOncallerMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, "second@oncaller.com", (*pubsub_common.CertificateInfo)(nil), mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
+		assert.NoError(t, err)
+
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(incidentKey, "state"))
+		assert.Equal(t, "1", s.HGet(incidentKey, "level"))
+		assert.NotEmpty(t, s.HGet(incidentKey, "escalation"))
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertExpectations(t)
+
+		// The upgraded incident keeps escalating until nobody is left
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "second@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendIncidentUnresolvedMessage", mock.Anything, "test-incident", serviceID, mock.Anything).Return(nil).Once()
+
+		err = managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, s.Exists(incidentKey))
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Success - Unresolved", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey,
+			"incident_id", "test-incident",
+			"state", IncidentStateWaitingForSecondAck,
+			"allowed_response_time", "5",
+			"first_oncaller", "first@oncaller.com",
+			"second_oncaller", "second@oncaller.com",
+			"incident_start_time", strconv.Itoa(int(time.Now().Unix())),
+		)
+
+		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, "test-incident", serviceID, "second@oncaller.com", mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendIncidentUnresolvedMessage", mock.Anything, "test-incident", serviceID, mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, s.Exists(incidentKey))
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Acknowledged Incident Not Escalated", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		incidentKey := escalatingIncident(s, 0, 0, 0)
+		s.HSet(incidentKey, "state", IncidentStateAcknowledged)
+
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, "0", s.HGet(incidentKey, "level"))
+		mockPubSub.AssertNotCalled(t, "SendAcknowledgeTimeoutMessage")
+		mockPubSub.AssertNotCalled(t, "SendNotifyOncallerMessage")
+	})
+
 	t.Run("Error on ZRem", func(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
 		s.SetError("redis error")
-		err := managerState.HandleExpiredDeadline(ctx, serviceID)
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.Error(t, err)
 		s.SetError("")
 	})
@@ -437,7 +1209,7 @@ func TestHandleExpiredDeadline(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
 		s.SetError("redis error")
-		err := managerState.HandleExpiredDeadline(ctx, serviceID)
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.Error(t, err)
 		s.SetError("")
 	})
@@ -446,22 +1218,12 @@ func TestHandleExpiredDeadline(t *testing.T) {
 		s, _, mockPubSub, managerState := setupTestState(t)
 		defer s.Close()
 
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
-		incidentInfo := IncidentInfo{
-			IncidentID:          "test-incident",
-			ServiceID:           serviceID,
-			State:               IncidentStateWaitingForFirstAck,
-			IncidentStartTime:   time.Now().Unix(),
-			AllowedResponseTime: 5,
-			FirstOncaller:       "first@oncaller.com",
-			SecondOncaller:      "",
-		}
-		s.HSet(incidentKey, "incident_id", incidentInfo.IncidentID, "state", incidentInfo.State, "allowed_response_time", strconv.Itoa(incidentInfo.AllowedResponseTime), "incident_start_time", strconv.FormatInt(incidentInfo.IncidentStartTime, 10), "first_oncaller", incidentInfo.FirstOncaller, "second_oncaller", incidentInfo.SecondOncaller)
+		escalatingIncident(s, 1, 0, 0)
 
 		mockPubSub.On("SendAcknowledgeTimeoutMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("pubsub error")).Once()
 		mockPubSub.On("SendIncidentUnresolvedMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 
-		err := managerState.HandleExpiredDeadline(ctx, serviceID)
+		err := managerState.HandleExpiredDeadline(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
@@ -477,14 +1239,14 @@ func TestHandleIncidentUnresolved(t *testing.T) {
 		s, _, mockPubSub, managerState := setupTestState(t)
 		defer s.Close()
 
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
 		downSinceKey := redis_keys.GetDownSinceKey(serviceID)
 		s.HSet(incidentKey, "incident_id", "test-incident")
 		s.Set(downSinceKey, "12345")
 
 		mockPubSub.On("SendIncidentUnresolvedMessage", mock.Anything, "test-incident", serviceID, mock.Anything).Return(nil).Once()
 
-		err := managerState.handleIncidentUnresolved(ctx, serviceID)
+		err := managerState.handleIncidentUnresolved(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
@@ -497,7 +1259,7 @@ func TestHandleIncidentUnresolved(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
 		s.SetError("redis error")
-		err := managerState.handleIncidentUnresolved(ctx, serviceID)
+		err := managerState.handleIncidentUnresolved(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.Error(t, err)
 		s.SetError("")
 	})
@@ -505,10 +1267,10 @@ func TestHandleIncidentUnresolved(t *testing.T) {
 	t.Run("Error on pipeline exec", func(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
 		s.HSet(incidentKey, "incident_id", "test-incident")
 		s.SetError("redis error")
-		err := managerState.handleIncidentUnresolved(ctx, serviceID)
+		err := managerState.handleIncidentUnresolved(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.Error(t, err)
 		s.SetError("")
 	})
@@ -516,12 +1278,12 @@ func TestHandleIncidentUnresolved(t *testing.T) {
 	t.Run("Error sending unresolved message", func(t *testing.T) {
 		s, _, mockPubSub, managerState := setupTestState(t)
 		defer s.Close()
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
 		s.HSet(incidentKey, "incident_id", "test-incident")
 
 		mockPubSub.On("SendIncidentUnresolvedMessage", mock.Anything, "test-incident", serviceID, mock.Anything).Return(errors.New("pubsub error")).Once()
 
-		err := managerState.handleIncidentUnresolved(ctx, serviceID)
+		err := managerState.handleIncidentUnresolved(ctx, serviceID, pubsub_common.IncidentKindDown)
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
@@ -538,18 +1300,19 @@ func TestHandleIncidentResolved(t *testing.T) {
 		s, _, mockPubSub, managerState := setupTestState(t)
 		defer s.Close()
 
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
 		downSinceKey := redis_keys.GetDownSinceKey(serviceID)
 		s.HSet(incidentKey, "incident_id", "test-incident")
 		s.Set(downSinceKey, "12345")
 
 		mockPubSub.On("SendIncidentResolvedMessage", mock.Anything, "test-incident", serviceID, oncaller, mock.Anything).Return(nil).Once()
 
-		err := managerState.handleIncidentResolved(ctx, serviceID, oncaller)
+		err := managerState.handleIncidentResolved(ctx, serviceID, pubsub_common.IncidentKindDown, oncaller)
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
-		assert.False(t, s.Exists(incidentKey))
+		assert.Equal(t, IncidentStateResolved, s.HGet(incidentKey, "state"))
+		assert.Equal(t, resolvedIncidentTTL, s.TTL(incidentKey))
 		assert.False(t, s.Exists(downSinceKey))
 		mockPubSub.AssertExpectations(t)
 	})
@@ -558,7 +1321,7 @@ func TestHandleIncidentResolved(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
 		s.SetError("redis error")
-		err := managerState.handleIncidentResolved(ctx, serviceID, oncaller)
+		err := managerState.handleIncidentResolved(ctx, serviceID, pubsub_common.IncidentKindDown, oncaller)
 		assert.Error(t, err)
 		s.SetError("")
 	})
@@ -566,10 +1329,10 @@ func TestHandleIncidentResolved(t *testing.T) {
 	t.Run("Error on pipeline exec", func(t *testing.T) {
 		s, _, _, managerState := setupTestState(t)
 		defer s.Close()
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
 		s.HSet(incidentKey, "incident_id", "test-incident")
 		s.SetError("redis error")
-		err := managerState.handleIncidentResolved(ctx, serviceID, oncaller)
+		err := managerState.handleIncidentResolved(ctx, serviceID, pubsub_common.IncidentKindDown, oncaller)
 		assert.Error(t, err)
 		s.SetError("")
 	})
@@ -577,12 +1340,12 @@ func TestHandleIncidentResolved(t *testing.T) {
 	t.Run("Error sending resolved message", func(t *testing.T) {
 		s, _, mockPubSub, managerState := setupTestState(t)
 		defer s.Close()
-		incidentKey := redis_keys.GetIncidentKey(serviceID)
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
 		s.HSet(incidentKey, "incident_id", "test-incident")
 
 		mockPubSub.On("SendIncidentResolvedMessage", mock.Anything, "test-incident", serviceID, oncaller, mock.Anything).Return(errors.New("pubsub error")).Once()
 
-		err := managerState.handleIncidentResolved(ctx, serviceID, oncaller)
+		err := managerState.handleIncidentResolved(ctx, serviceID, pubsub_common.IncidentKindDown, oncaller)
 		assert.NoError(t, err)
 
 		time.Sleep(100 * time.Millisecond)
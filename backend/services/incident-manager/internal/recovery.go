@@ -0,0 +1,185 @@
+package internal
+
+import (
+	redis_keys "alerting-plafform/incident-manager/redis"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"alerting-platform/common/db"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// recoveryChecks is the number of consecutive healthy results after which the service counts as recovered
+func (service ServiceInfo) recoveryChecks() int {
+	if service.RecoveryChecks > 0 {
+		return service.RecoveryChecks
+	}
+	return 1
+}
+
+// countRecovery counts a healthy result towards the recovery of the open incident of the kind.
+// Once recovered the incident stops escalating and is resolved or reported to its oncallers
+// as configured for the service.
+// Should be locked before calling
+func (managerState *ManagerState) countRecovery(ctx context.Context, service ServiceInfo, kind string, eventTime time.Time) error {
+	redisClient := db.GetRedisClient()
+	incidentKey := redis_keys.GetIncidentKey(service.ID, kind)
+
+	incident, err := redisClient.HGetAll(ctx, incidentKey).Result()
+	if err != nil {
+		return err
+	}
+
+	if len(incident) == 0 || incident["state"] == IncidentStateResolved {
+		return nil
+	}
+
+	if recoveredAt, _ := strconv.ParseInt(incident["recovered_at"], 10, 64); recoveredAt > 0 {
+		return nil
+	}
+
+	upStreak, _ := strconv.Atoi(incident["up_streak"])
+	upSince, _ := strconv.ParseInt(incident["up_since"], 10, 64)
+	if upStreak == 0 {
+		upSince = eventTime.Unix()
+	}
+	upStreak++
+
+	if upStreak < service.recoveryChecks() {
+		log.Printf("[DEBUG] Service %d is healthy, %d of %d results needed to recover", service.ID, upStreak, service.recoveryChecks())
+		return redisClient.HSet(ctx, incidentKey, "up_streak", upStreak, "up_since", upSince).Err()
+	}
+
+	return managerState.handleServiceRecovered(ctx, service, kind, incident, time.Unix(upSince, 0).UTC())
+}
+
+// Should be locked before calling
+func (managerState *ManagerState) handleServiceRecovered(ctx context.Context, service ServiceInfo, kind string, incident map[string]string, upSince time.Time) error {
+	redisClient := db.GetRedisClient()
+	incidentKey := redis_keys.GetIncidentKey(service.ID, kind)
+	incidentID := incident["incident_id"]
+
+	log.Printf("[DEBUG] Service %d recovered from %s incident %s", service.ID, kind, incidentID)
+
+	pipe := redisClient.TxPipeline()
+
+	pipe.HSet(ctx, incidentKey, "recovered_at", upSince.Unix(), "up_streak", 0, "up_since", 0)
+	pipe.ZRem(ctx, redis_keys.GetOncallerDeadlineSetKey(), redis_keys.GetDeadlineMember(service.ID, kind))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		err := managerState.pubSubService.SendIncidentRecoveredMessage(
+			context.Background(),
+			incidentID,
+			service.ID,
+			kind,
+			upSince,
+		)
+
+		if err != nil {
+			log.Printf("[ERROR] Failed to send incident recovered message for service %d: %v", service.ID, err)
+		}
+	}()
+
+	if service.NotifyRecovery {
+		managerState.notifyRecovery(incidentID, service.ID, kind, recoveryTargets(incident))
+	}
+
+	if service.AutoResolve {
+		return managerState.handleIncidentResolved(ctx, service.ID, kind, "")
+	}
+
+	return nil
+}
+
+// interruptRecovery starts counting healthy results from zero again. Incidents that
+// already recovered and were not acknowledged continue escalating at their level.
+// Should be locked before calling
+func (managerState *ManagerState) interruptRecovery(ctx context.Context, serviceID uint64, kind string) error {
+	redisClient := db.GetRedisClient()
+	incidentKey := redis_keys.GetIncidentKey(serviceID, kind)
+
+	incident, err := redisClient.HGetAll(ctx, incidentKey).Result()
+	if err != nil {
+		return err
+	}
+
+	if len(incident) == 0 || incident["state"] == IncidentStateResolved {
+		return nil
+	}
+
+	upStreak, _ := strconv.Atoi(incident["up_streak"])
+	recoveredAt, _ := strconv.ParseInt(incident["recovered_at"], 10, 64)
+	if upStreak == 0 && recoveredAt == 0 {
+		return nil
+	}
+
+	pipe := redisClient.TxPipeline()
+
+	pipe.HSet(ctx, incidentKey, "up_streak", 0, "up_since", 0, "recovered_at", 0)
+
+	if recoveredAt > 0 && incident["state"] != IncidentStateAcknowledged {
+		incidentInfo := IncidentInfo{IncidentID: incident["incident_id"], State: incident["state"]}
+
+		levels, err := incidentEscalation(&incidentInfo, incident)
+		if err != nil {
+			return err
+		}
+
+		if incidentInfo.Level < len(levels) {
+			log.Printf("[DEBUG] Service %d is failing again, resuming escalation of incident %s", serviceID, incidentInfo.IncidentID)
+
+			pipe.ZAdd(ctx, redis_keys.GetOncallerDeadlineSetKey(), redis.Z{
+				Score:  float64(time.Now().UTC().Add(time.Duration(levels[incidentInfo.Level].Timeout) * time.Minute).Unix()),
+				Member: redis_keys.GetDeadlineMember(serviceID, kind),
+			})
+		}
+	}
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// recoveryTargets returns whoever acknowledged the incident, or everybody its current level notified
+func recoveryTargets(incident map[string]string) []string {
+	if acknowledgedBy := incident["acknowledged_by"]; acknowledgedBy != "" {
+		return []string{acknowledgedBy}
+	}
+
+	incidentInfo := IncidentInfo{IncidentID: incident["incident_id"], State: incident["state"]}
+
+	levels, err := incidentEscalation(&incidentInfo, incident)
+	if err != nil || incidentInfo.Level >= len(levels) {
+		var targets []string
+		_ = json.Unmarshal([]byte(incident["notified"]), &targets)
+		return targets
+	}
+
+	return notifiedTargets(incident, levels[incidentInfo.Level], time.Now().UTC())
+}
+
+func (managerState *ManagerState) notifyRecovery(incidentID string, serviceID uint64, kind string, targets []string) {
+	for _, target := range targets {
+		go func() {
+			err := managerState.pubSubService.SendNotifyRecoveryMessage(
+				context.Background(),
+				incidentID,
+				serviceID,
+				kind,
+				target,
+				time.Now().UTC(),
+			)
+
+			if err != nil {
+				log.Printf("[ERROR] Failed to send notify recovery message for service %d: %v", serviceID, err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	redis_keys "alerting-plafform/incident-manager/redis"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	pubsub_common "alerting-platform/common/pubsub"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServiceRecovery(t *testing.T) {
+	ctx := context.Background()
+	serviceID := uint64(1)
+	payload := pubsub_common.PubSubPayload{ServiceID: serviceID}
+
+	escalatingIncident := func(s *miniredis.Miniredis) string {
+		incidentKey := redis_keys.GetIncidentKey(serviceID, pubsub_common.IncidentKindDown)
+		s.HSet(incidentKey,
+			"incident_id", "test-incident",
+			"state", IncidentStateWaitingForAck,
+			"escalation", `[{"targets":["first@oncaller.com"],"timeout":5},{"targets":["lead@oncaller.com"],"timeout":15}]`,
+			"level", "1",
+			"notified", `["lead@oncaller.com"]`,
+		)
+		s.ZAdd(redis_keys.GetOncallerDeadlineSetKey(), float64(time.Now().Add(time.Minute).Unix()), redis_keys.GetDeadlineMember(serviceID, pubsub_common.IncidentKindDown))
+		return incidentKey
+	}
+
+	t.Run("Recovered After Consecutive Checks", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = ServiceInfo{ID: serviceID, RecoveryChecks: 3, AutoResolve: true}
+		incidentKey := escalatingIncident(s)
+
+		firstUp := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+		mockPubSub.On("SendIncidentRecoveredMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, firstUp).Return(nil).Once()
+		mockPubSub.On("SendIncidentResolvedMessage", mock.Anything, "test-incident", serviceID, "", mock.Anything).Return(nil).Once()
+
+		for i := range 2 {
+			err := managerState.HandleServiceUp(ctx, payload, firstUp.Add(time.Duration(i)*30*time.Second))
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, "2", s.HGet(incidentKey, "up_streak"))
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(incidentKey, "state"))
+
+		err := managerState.HandleServiceUp(ctx, payload, firstUp.Add(time.Minute))
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, IncidentStateResolved, s.HGet(incidentKey, "state"))
+		assert.Equal(t, strconv.FormatInt(firstUp.Unix(), 10), s.HGet(incidentKey, "recovered_at"))
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Failure Restarts Count", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = ServiceInfo{ID: serviceID, AlertWindow: 60, RecoveryChecks: 2, AutoResolve: true}
+		incidentKey := escalatingIncident(s)
+
+		err := managerState.HandleServiceUp(ctx, payload, time.Now())
+		assert.NoError(t, err)
+		err = managerState.HandleServiceDown(ctx, payload, time.Now())
+		assert.NoError(t, err)
+		err = managerState.HandleServiceUp(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, "1", s.HGet(incidentKey, "up_streak"))
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(incidentKey, "state"))
+		mockPubSub.AssertNotCalled(t, "SendIncidentRecoveredMessage")
+	})
+
+	t.Run("Notify Oncallers And Stop Escalating", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = ServiceInfo{ID: serviceID, NotifyRecovery: true}
+		incidentKey := escalatingIncident(s)
+
+		mockPubSub.On("SendIncidentRecoveredMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyRecoveryMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, "lead@oncaller.com", mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleServiceUp(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, IncidentStateWaitingForAck, s.HGet(incidentKey, "state"))
+		members, _ := s.ZMembers(redis_keys.GetOncallerDeadlineSetKey())
+		assert.Empty(t, members)
+		mockPubSub.AssertNotCalled(t, "SendIncidentResolvedMessage")
+		mockPubSub.AssertExpectations(t)
+
+		// Further healthy results don't report the recovery again
+		err = managerState.HandleServiceUp(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Notify Whoever Acknowledged", func(t *testing.T) {
+		s, _, mockPubSub, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = ServiceInfo{ID: serviceID, NotifyRecovery: true}
+		incidentKey := escalatingIncident(s)
+		s.HSet(incidentKey, "state", IncidentStateAcknowledged, "acknowledged_by", "first@oncaller.com")
+
+		mockPubSub.On("SendIncidentRecoveredMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, mock.Anything).Return(nil).Once()
+		mockPubSub.On("SendNotifyRecoveryMessage", mock.Anything, "test-incident", serviceID, pubsub_common.IncidentKindDown, "first@oncaller.com", mock.Anything).Return(nil).Once()
+
+		err := managerState.HandleServiceUp(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		mockPubSub.AssertExpectations(t)
+	})
+
+	t.Run("Escalation Resumes When Failing Again", func(t *testing.T) {
+		s, _, _, managerState := setupTestState(t)
+		defer s.Close()
+
+		managerState.services[serviceID] = ServiceInfo{ID: serviceID, AlertWindow: 60}
+		incidentKey := escalatingIncident(s)
+		s.HSet(incidentKey, "recovered_at", strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10))
+		s.ZRem(redis_keys.GetOncallerDeadlineSetKey(), redis_keys.GetDeadlineMember(serviceID, pubsub_common.IncidentKindDown))
+
+		before := time.Now()
+		err := managerState.HandleServiceDown(ctx, payload, time.Now())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "0", s.HGet(incidentKey, "recovered_at"))
+
+		// The current level gets its full timeout again
+		deadline, err := s.ZScore(redis_keys.GetOncallerDeadlineSetKey(), redis_keys.GetDeadlineMember(serviceID, pubsub_common.IncidentKindDown))
+		assert.NoError(t, err)
+		assert.InDelta(t, float64(before.Add(15*time.Minute).Unix()), deadline, 1)
+	})
+}
@@ -2,10 +2,14 @@ package internal
 
 import (
 	"alerting-plafform/incident-manager/rpc"
+	rpc_common "alerting-platform/common/rpc"
 	"context"
 	"sync"
+	"time"
 
 	pubsub_internal "alerting-plafform/incident-manager/pubsub"
+	"alerting-platform/common/oncall"
+	pubsub_common "alerting-platform/common/pubsub"
 
 	"cloud.google.com/go/pubsub"
 )
@@ -15,11 +19,44 @@ type ServiceInfo struct {
 	// DownSince           int64 // stored in Redis
 	AlertWindow         int // in seconds
 	AllowedResponseTime int // in minutes
+	LatencyThreshold    int // in milliseconds, 0 disables degraded incidents
+	CertExpiryDays      int // 0 disables certificate expiry incidents
+	Locations           int // number of locations checking the service
+	LocationQuorum      int // locations that must agree on DOWN, majority if 0
 	Oncallers           []string
+	Escalation          []pubsub_common.EscalationLevel // replaces the oncallers if set
+	EscalationRepeat    int                             // times the whole escalation is repeated
+	AutoResolve         bool                            // resolve open incidents when the service recovers
+	RecoveryChecks      int                             // consecutive healthy results before the service counts as recovered, 1 if 0
+	NotifyRecovery      bool                            // tell the oncallers of open incidents when the service recovers
+	FlapWindow          int                             // recent results checked for flapping, defaultFlapWindow if 0
+	FlapThreshold       int                             // percentage of changes between them that counts as flapping, defaultFlapThreshold if 0
+}
+
+// quorum is the number of locations that must report DOWN in one round
+func (service ServiceInfo) quorum() int {
+	if service.LocationQuorum > 0 && service.LocationQuorum <= service.Locations {
+		return service.LocationQuorum
+	}
+	return service.Locations/2 + 1
+}
+
+// incidentKinds lists every kind of incident a service can have open at the same time
+var incidentKinds = []string{
+	pubsub_common.IncidentKindDown,
+	pubsub_common.IncidentKindDegraded,
+	pubsub_common.IncidentKindCertExpiry,
+	pubsub_common.IncidentKindFlapping,
 }
 
 const (
-	IncidentStateStarted             = "STARTED"
+	IncidentStateStarted       = "STARTED"
+	IncidentStateWaitingForAck = "WAITING_FOR_ACK"
+	IncidentStateAcknowledged  = "ACKNOWLEDGED" // no longer escalating, open until resolved
+	IncidentStateResolved      = "RESOLVED"     // closed, kept for a while so late links are ignored
+
+	// Written before escalation policies, incidents still open with these states
+	// wait at the first or second level of their first and second oncaller
 	IncidentStateWaitingForFirstAck  = "WAITING_FOR_FIRST_ACK"
 	IncidentStateWaitingForSecondAck = "WAITING_FOR_SECOND_ACK"
 )
@@ -27,13 +64,32 @@ const (
 type IncidentInfo struct {
 	IncidentID        string `redis:"incident_id"`
 	ServiceID         uint64 `redis:"service_id"`
+	Kind              string `redis:"kind"`
 	State             string `redis:"state"`
 	IncidentStartTime int64  `redis:"incident_start_time"`
 
 	// Copied in case service info is changed through API
 	AllowedResponseTime int    `redis:"allowed_response_time"`
-	FirstOncaller       string `redis:"first_oncaller"`
-	SecondOncaller      string `redis:"second_oncaller"`
+	Escalation          string `redis:"escalation"` // JSON encoded levels
+	EscalationRepeat    int    `redis:"escalation_repeat"`
+
+	Level    int    `redis:"level"`    // index of the escalation level waiting for an ack
+	Cycle    int    `redis:"cycle"`    // times the whole escalation was repeated so far
+	Notified string `redis:"notified"` // JSON encoded targets of the level, schedules resolved when it was notified
+
+	AcknowledgedBy string `redis:"acknowledged_by"`
+	AcknowledgedAt int64  `redis:"acknowledged_at"`
+	ResolvedBy     string `redis:"resolved_by"` // empty if resolved automatically
+	ResolvedAt     int64  `redis:"resolved_at"`
+
+	UpStreak    int   `redis:"up_streak"`    // consecutive healthy results so far
+	UpSince     int64 `redis:"up_since"`     // first healthy result of the streak
+	RecoveredAt int64 `redis:"recovered_at"` // when the service came back, escalation stops until it fails again
+
+	// Certificate expiry incidents only, repeated in every notification
+	CertNotAfter int64  `redis:"cert_not_after"`
+	CertIssuer   string `redis:"cert_issuer"`
+	CertSubject  string `redis:"cert_subject"`
 }
 
 type ManagerState struct {
@@ -58,7 +114,18 @@ func NewManagerState(ctx context.Context, psClient *pubsub.Client) *ManagerState
 			ID:                  svc.ServiceId,
 			AlertWindow:         int(svc.AlertWindow),
 			AllowedResponseTime: int(svc.AllowedResponseTime),
+			LatencyThreshold:    int(svc.LatencyThreshold),
+			CertExpiryDays:      int(svc.CertExpiryDays),
+			Locations:           len(svc.Locations),
+			LocationQuorum:      int(svc.LocationQuorum),
 			Oncallers:           svc.Oncallers,
+			Escalation:          fromRPCEscalation(svc.Escalation),
+			EscalationRepeat:    int(svc.EscalationRepeat),
+			AutoResolve:         svc.AutoResolve,
+			RecoveryChecks:      int(svc.RecoveryChecks),
+			NotifyRecovery:      svc.NotifyRecovery,
+			FlapWindow:          int(svc.FlapWindow),
+			FlapThreshold:       int(svc.FlapThreshold),
 		}
 
 		state.services[service.ID] = service
@@ -82,3 +149,54 @@ func (managerState *ManagerState) LockService(serviceID uint64) *sync.Mutex {
 
 	return lock
 }
+
+func fromRPCEscalation(levels []*rpc_common.EscalationLevel) []pubsub_common.EscalationLevel {
+	if len(levels) == 0 {
+		return nil
+	}
+
+	result := make([]pubsub_common.EscalationLevel, len(levels))
+	for i, level := range levels {
+		result[i] = pubsub_common.EscalationLevel{
+			Targets:   level.Targets,
+			Schedules: fromRPCSchedules(level.Schedules),
+			Timeout:   int(level.Timeout),
+		}
+	}
+	return result
+}
+
+func fromRPCSchedules(schedules []*rpc_common.Schedule) []oncall.Schedule {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	result := make([]oncall.Schedule, len(schedules))
+	for i, schedule := range schedules {
+		layers := make([]oncall.Layer, len(schedule.Layers))
+		for j, layer := range schedule.Layers {
+			layers[j] = oncall.Layer{
+				Users:        layer.Users,
+				Start:        layer.Start,
+				End:          layer.End,
+				RotationDays: int(layer.RotationDays),
+			}
+		}
+
+		overrides := make([]oncall.Override, len(schedule.Overrides))
+		for j, override := range schedule.Overrides {
+			overrides[j] = oncall.Override{
+				User:  override.User,
+				Start: time.Unix(override.Start, 0).UTC(),
+				End:   time.Unix(override.End, 0).UTC(),
+			}
+		}
+
+		result[i] = oncall.Schedule{
+			Timezone:  schedule.Timezone,
+			Layers:    layers,
+			Overrides: overrides,
+		}
+	}
+	return result
+}
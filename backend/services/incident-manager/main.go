@@ -47,9 +47,11 @@ func StartPubSubListener(ctx context.Context, wg *sync.WaitGroup, psClient *pubs
 		"incident-manager-service-removed":       pubsub_common.ServiceRemovedTopic,
 		"incident-manager-service-modified":      pubsub_common.ServiceModifiedTopic,
 		"incident-manager-oncaller-acknowledged": pubsub_common.OncallerAcknowledgedTopic,
+		"incident-manager-oncaller-resolved":     pubsub_common.OncallerResolvedTopic,
+		"incident-manager-monitoring-gap":        pubsub_common.MonitoringGapTopic,
 	}
 
-	pubsub_common.CreateSubscriptionsAndTopics(psClient, subscriptions, []string{pubsub_common.NotifyOncallerTopic})
+	pubsub_common.CreateSubscriptionsAndTopics(psClient, subscriptions, []string{pubsub_common.NotifyOncallerTopic, pubsub_common.NotifyRecoveryTopic})
 	pubsub_common.SetupSubscriptionListeners(ctx, psClient, subscriptions, wg, func(ctx context.Context, msg pubsub_common.PubSubMessage, eventType string) {
 		managerState.HandleMessage(ctx, msg, eventType)
 	})
@@ -78,17 +80,17 @@ func StartIncidentManager(ctx context.Context, managerState *internal.ManagerSta
 				continue
 			}
 
-			for _, serviceID := range expiredDeadlines {
-				serviceIDInt, err := strconv.ParseUint(serviceID, 10, 64)
+			for _, member := range expiredDeadlines {
+				serviceID, kind, err := redis_keys.ParseDeadlineMember(member)
 				if err != nil {
-					log.Printf("[ERROR] Invalid service ID %s: %v", serviceID, err)
+					log.Printf("[ERROR] %v", err)
 					continue
 				}
 
 				go func() {
-					err := managerState.HandleExpiredDeadline(ctx, serviceIDInt)
+					err := managerState.HandleExpiredDeadline(ctx, serviceID, kind)
 					if err != nil {
-						log.Printf("[ERROR] Failed to handle expired deadline for service %d: %v", serviceIDInt, err)
+						log.Printf("[ERROR] Failed to handle expired deadline for service %d: %v", serviceID, err)
 					}
 				}()
 			}
@@ -11,11 +11,14 @@ import (
 )
 
 type PubSubServiceI interface {
-	SendIncidentStartMessage(ctx context.Context, incidentID string, serviceID uint64, timestamp time.Time) error
+	SendIncidentStartMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, timestamp time.Time) error
 	SendAcknowledgeTimeoutMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error
-	SendNotifyOncallerMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error
+	SendNotifyOncallerMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, oncaller string, certificate *pubsub_common.CertificateInfo, timestamp time.Time) error
 	SendIncidentUnresolvedMessage(ctx context.Context, incidentID string, serviceID uint64, timestamp time.Time) error
+	SendIncidentAcknowledgedMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error
 	SendIncidentResolvedMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error
+	SendIncidentRecoveredMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, timestamp time.Time) error
+	SendNotifyRecoveryMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, oncaller string, timestamp time.Time) error
 }
 
 type PubSubService struct {
@@ -26,13 +29,14 @@ func NewPubSubService(client *pubsub.Client) *PubSubService {
 	return &PubSubService{client: client}
 }
 
-func (ps *PubSubService) SendIncidentStartMessage(ctx context.Context, incidentID string, serviceID uint64, timestamp time.Time) error {
+func (ps *PubSubService) SendIncidentStartMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, timestamp time.Time) error {
 	var payload pubsub_common.PubSubPayload
 
 	log.Printf("[DEBUG] Sending IncidentStart message")
 
 	payload.IncidentID = incidentID
 	payload.ServiceID = serviceID
+	payload.Data.IncidentKind = kind
 	payload.Timestamp = timestamp.Format(time.RFC3339)
 
 	return pubsub_common.SendPayload(ctx, ps.client, pubsub_common.IncidentStartTopic, payload, incidentID)
@@ -51,13 +55,15 @@ func (ps *PubSubService) SendAcknowledgeTimeoutMessage(ctx context.Context, inci
 	return pubsub_common.SendPayload(ctx, ps.client, pubsub_common.IncidentAcknowledgeTimeoutTopic, payload, incidentID)
 }
 
-func (ps *PubSubService) SendNotifyOncallerMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error {
+func (ps *PubSubService) SendNotifyOncallerMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, oncaller string, certificate *pubsub_common.CertificateInfo, timestamp time.Time) error {
 	var payload pubsub_common.PubSubPayload
 
 	log.Printf("[DEBUG] Sending NotifyOncaller message")
 
 	payload.IncidentID = incidentID
 	payload.ServiceID = serviceID
+	payload.Data.IncidentKind = kind
+	payload.Data.Certificate = certificate
 	payload.OnCaller = oncaller
 	payload.Timestamp = timestamp.Format(time.RFC3339)
 
@@ -76,6 +82,19 @@ func (ps *PubSubService) SendIncidentUnresolvedMessage(ctx context.Context, inci
 	return pubsub_common.SendPayload(ctx, ps.client, pubsub_common.IncidentUnresolvedTopic, payload, incidentID)
 }
 
+func (ps *PubSubService) SendIncidentAcknowledgedMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error {
+	var payload pubsub_common.PubSubPayload
+
+	log.Printf("[DEBUG] Sending IncidentAcknowledged message")
+
+	payload.IncidentID = incidentID
+	payload.ServiceID = serviceID
+	payload.OnCaller = oncaller
+	payload.Timestamp = timestamp.Format(time.RFC3339)
+
+	return pubsub_common.SendPayload(ctx, ps.client, pubsub_common.IncidentAcknowledgedTopic, payload, incidentID)
+}
+
 func (ps *PubSubService) SendIncidentResolvedMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error {
 	var payload pubsub_common.PubSubPayload
 
@@ -88,3 +107,30 @@ func (ps *PubSubService) SendIncidentResolvedMessage(ctx context.Context, incide
 
 	return pubsub_common.SendPayload(ctx, ps.client, pubsub_common.IncidentResolvedTopic, payload, incidentID)
 }
+
+func (ps *PubSubService) SendIncidentRecoveredMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, timestamp time.Time) error {
+	var payload pubsub_common.PubSubPayload
+
+	log.Printf("[DEBUG] Sending IncidentRecovered message")
+
+	payload.IncidentID = incidentID
+	payload.ServiceID = serviceID
+	payload.Data.IncidentKind = kind
+	payload.Timestamp = timestamp.Format(time.RFC3339)
+
+	return pubsub_common.SendPayload(ctx, ps.client, pubsub_common.IncidentRecoveredTopic, payload, incidentID)
+}
+
+func (ps *PubSubService) SendNotifyRecoveryMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, oncaller string, timestamp time.Time) error {
+	var payload pubsub_common.PubSubPayload
+
+	log.Printf("[DEBUG] Sending NotifyRecovery message")
+
+	payload.IncidentID = incidentID
+	payload.ServiceID = serviceID
+	payload.Data.IncidentKind = kind
+	payload.OnCaller = oncaller
+	payload.Timestamp = timestamp.Format(time.RFC3339)
+
+	return pubsub_common.SendPayload(ctx, ps.client, pubsub_common.NotifyRecoveryTopic, payload, incidentID)
+}
@@ -1,6 +1,7 @@
 package pubsub
 
 import (
+	pubsub_common "alerting-platform/common/pubsub"
 	"context"
 	"time"
 
@@ -11,8 +12,8 @@ type MockPubSubService struct {
 	mock.Mock
 }
 
-func (m *MockPubSubService) SendIncidentStartMessage(ctx context.Context, incidentID string, serviceID uint64, timestamp time.Time) error {
-	args := m.Called(ctx, incidentID, serviceID, timestamp)
+func (m *MockPubSubService) SendIncidentStartMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, timestamp time.Time) error {
+	args := m.Called(ctx, incidentID, serviceID, kind, timestamp)
 	return args.Error(0)
 }
 
@@ -21,8 +22,8 @@ func (m *MockPubSubService) SendAcknowledgeTimeoutMessage(ctx context.Context, i
 	return args.Error(0)
 }
 
-func (m *MockPubSubService) SendNotifyOncallerMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error {
-	args := m.Called(ctx, incidentID, serviceID, oncaller, timestamp)
+func (m *MockPubSubService) SendNotifyOncallerMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, oncaller string, certificate *pubsub_common.CertificateInfo, timestamp time.Time) error {
+	args := m.Called(ctx, incidentID, serviceID, kind, oncaller, certificate, timestamp)
 	return args.Error(0)
 }
 
@@ -31,7 +32,22 @@ func (m *MockPubSubService) SendIncidentUnresolvedMessage(ctx context.Context, i
 	return args.Error(0)
 }
 
+func (m *MockPubSubService) SendIncidentAcknowledgedMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error {
+	args := m.Called(ctx, incidentID, serviceID, oncaller, timestamp)
+	return args.Error(0)
+}
+
 func (m *MockPubSubService) SendIncidentResolvedMessage(ctx context.Context, incidentID string, serviceID uint64, oncaller string, timestamp time.Time) error {
 	args := m.Called(ctx, incidentID, serviceID, oncaller, timestamp)
 	return args.Error(0)
 }
+
+func (m *MockPubSubService) SendIncidentRecoveredMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, timestamp time.Time) error {
+	args := m.Called(ctx, incidentID, serviceID, kind, timestamp)
+	return args.Error(0)
+}
+
+func (m *MockPubSubService) SendNotifyRecoveryMessage(ctx context.Context, incidentID string, serviceID uint64, kind string, oncaller string, timestamp time.Time) error {
+	args := m.Called(ctx, incidentID, serviceID, kind, oncaller, timestamp)
+	return args.Error(0)
+}
@@ -2,7 +2,10 @@ package redis
 
 import (
 	"alerting-platform/common/config"
+	pubsub_common "alerting-platform/common/pubsub"
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 func GetDownSinceKey(serviceID uint64) string {
@@ -10,9 +13,39 @@ func GetDownSinceKey(serviceID uint64) string {
 	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":down_since"
 }
 
-func GetIncidentKey(serviceID uint64) string {
+func GetDegradedSinceKey(serviceID uint64) string {
 	cfg := config.GetConfig()
-	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":incident"
+	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":degraded_since"
+}
+
+// GetCertAlertedKey holds the expiry of the last certificate an incident was opened for
+func GetCertAlertedKey(serviceID uint64) string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":cert_alerted"
+}
+
+// GetFlapHistoryKey holds the latest settled results of the service, newest first
+func GetFlapHistoryKey(serviceID uint64) string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":flap_history"
+}
+
+// GetIncidentKey keeps the original key for DOWN incidents, so that incidents
+// opened before degraded ones existed are still found
+func GetIncidentKey(serviceID uint64, kind string) string {
+	cfg := config.GetConfig()
+	key := cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":incident"
+	if kind == pubsub_common.IncidentKindDown {
+		return key
+	}
+	return key + ":" + strings.ToLower(kind)
+}
+
+// GetCheckRoundKey holds the result of every location for one check round,
+// and the verdict once enough of them agree
+func GetCheckRoundKey(serviceID uint64, round int64) string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":round:" + strconv.FormatInt(round, 10)
 }
 
 func GetServiceStatusKey(serviceID uint64) string {
@@ -23,3 +56,26 @@ func GetOncallerDeadlineSetKey() string {
 	cfg := config.GetConfig()
 	return cfg.RedisPrefix + ":oncaller_deadlines"
 }
+
+// GetDeadlineMember identifies an incident in the oncaller deadline set.
+// DOWN incidents use the bare service ID, as they did before incident kinds.
+func GetDeadlineMember(serviceID uint64, kind string) string {
+	if kind == pubsub_common.IncidentKindDown {
+		return strconv.FormatUint(serviceID, 10)
+	}
+	return strconv.FormatUint(serviceID, 10) + ":" + kind
+}
+
+func ParseDeadlineMember(member string) (uint64, string, error) {
+	id, kind, found := strings.Cut(member, ":")
+	if !found {
+		kind = pubsub_common.IncidentKindDown
+	}
+
+	serviceID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid deadline member %q: %w", member, err)
+	}
+
+	return serviceID, kind, nil
+}
@@ -12,10 +12,13 @@ var EventTypeToStatus = map[string]string{
 	pubsub.ServiceUpTopic:                  "UP",
 	pubsub.ServiceDownTopic:                "DOWN",
 	pubsub.IncidentStartTopic:              "START",
+	pubsub.IncidentAcknowledgedTopic:       "ACKNOWLEDGED",
+	pubsub.IncidentRecoveredTopic:          "RECOVERED",
 	pubsub.IncidentResolvedTopic:           "RESOLVED",
 	pubsub.IncidentAcknowledgeTimeoutTopic: "TIMEOUT",
 	pubsub.IncidentUnresolvedTopic:         "UNRESOLVED",
 	pubsub.NotifyOncallerTopic:             "NOTIFIED",
+	pubsub.MonitoringGapTopic:              pubsub.StatusNoData,
 }
 
 func HandleMessage(
@@ -33,19 +36,44 @@ func HandleMessage(
 
 	switch eventType {
 	case pubsub.ServiceUpTopic, pubsub.ServiceDownTopic:
-		err = repo.SaveMetric(ctx, firestore.MetricLog{
+		metric := firestore.MetricLog{
 			ServiceID: int64(payload.ServiceID),
 			Timestamp: *eventTime,
 			Type:      EventTypeToStatus[eventType],
+		}
+
+		if result := payload.Data.Result; result != nil {
+			metric.ResponseTimeMs = result.ResponseTimeMs
+			metric.StatusCode = result.StatusCode
+			metric.ErrorType = result.ErrorType
+			metric.Error = result.Error
+			metric.WorkerID = result.WorkerID
+			metric.Attempts = result.Attempts
+		}
+
+		err = repo.SaveMetric(ctx, metric)
+	case pubsub.MonitoringGapTopic:
+		gap := payload.Data.Gap
+		if gap == nil {
+			log.Printf("[WARNING] Monitoring gap of service %d without a period, dropping", payload.ServiceID)
+			break
+		}
+
+		err = repo.SaveMetric(ctx, firestore.MetricLog{
+			ServiceID: int64(payload.ServiceID),
+			Timestamp: gap.Until,
+			Type:      EventTypeToStatus[eventType],
+			GapSince:  gap.Since,
 		})
-	case pubsub.IncidentStartTopic, pubsub.IncidentResolvedTopic, pubsub.IncidentAcknowledgeTimeoutTopic,
-		pubsub.IncidentUnresolvedTopic, pubsub.NotifyOncallerTopic:
+	case pubsub.IncidentStartTopic, pubsub.IncidentAcknowledgedTopic, pubsub.IncidentRecoveredTopic, pubsub.IncidentResolvedTopic,
+		pubsub.IncidentAcknowledgeTimeoutTopic, pubsub.IncidentUnresolvedTopic, pubsub.NotifyOncallerTopic:
 		err = repo.SaveLog(ctx, firestore.IncidentLog{
 			IncidentID: payload.IncidentID,
 			ServiceID:  int64(payload.ServiceID),
 			Oncaller:   payload.OnCaller,
 			Timestamp:  *eventTime,
 			Type:       EventTypeToStatus[eventType],
+			Kind:       payload.Data.IncidentKind,
 		})
 	default:
 		log.Printf("[WARNING] Unhandled event type: %s", eventType)
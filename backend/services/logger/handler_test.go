@@ -61,6 +61,54 @@ func TestHandleMessage_Incident_Ack(t *testing.T) {
 	assert.False(t, msg.Nacked, "Message should NOT be NACKed")
 }
 
+func TestHandleMessage_IncidentKind(t *testing.T) {
+	repo := &mockRepo{}
+
+	msg := &pubsub.FakeMessage{
+		Data:        []byte(`{"incident_id":"inc-1-degraded", "service_id": 1, "data": {"incident_kind": "DEGRADED"}}`),
+		PublishTime: time.Now().UTC(),
+	}
+
+	HandleMessage(context.Background(), msg, pubsub.IncidentStartTopic, repo)
+
+	assert.True(t, repo.saveLogCalled, "SaveLog should be called")
+	assert.Equal(t, pubsub.IncidentKindDegraded, repo.lastIncident.Kind)
+	assert.True(t, msg.Acked, "Message should be ACKed")
+}
+
+func TestHandleMessage_IncidentAcknowledged(t *testing.T) {
+	repo := &mockRepo{}
+
+	msg := &pubsub.FakeMessage{
+		Data:        []byte(`{"incident_id":"inc-1", "service_id": 1, "oncaller": "oncaller@example.com"}`),
+		PublishTime: time.Now().UTC(),
+	}
+
+	HandleMessage(context.Background(), msg, pubsub.IncidentAcknowledgedTopic, repo)
+
+	assert.True(t, repo.saveLogCalled, "SaveLog should be called")
+	assert.Equal(t, "ACKNOWLEDGED", repo.lastIncident.Type)
+	assert.Equal(t, "oncaller@example.com", repo.lastIncident.Oncaller)
+	assert.True(t, msg.Acked, "Message should be ACKed")
+}
+
+func TestHandleMessage_IncidentRecovered(t *testing.T) {
+	repo := &mockRepo{}
+
+	recoveredAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	msg := &pubsub.FakeMessage{
+		Data:        []byte(`{"incident_id":"inc-1", "service_id": 1, "timestamp": "2026-03-01T12:00:00Z", "data": {"incident_kind": "DOWN"}}`),
+		PublishTime: time.Now().UTC(),
+	}
+
+	HandleMessage(context.Background(), msg, pubsub.IncidentRecoveredTopic, repo)
+
+	assert.True(t, repo.saveLogCalled, "SaveLog should be called")
+	assert.Equal(t, "RECOVERED", repo.lastIncident.Type)
+	assert.True(t, recoveredAt.Equal(repo.lastIncident.Timestamp), "Should log when the service came back")
+	assert.True(t, msg.Acked, "Message should be ACKed")
+}
+
 func TestHandleMessage_DB_NackOnError(t *testing.T) {
 	repo := &mockRepo{
 		err: errors.New("db error"),
@@ -141,3 +189,56 @@ func TestHandleMessage_UsesNowIfZero(t *testing.T) {
 		"Timestamp should be set to current time if zero",
 	)
 }
+
+func TestHandleMessage_MetricWithResult(t *testing.T) {
+	repo := &mockRepo{}
+
+	msg := &pubsub.FakeMessage{
+		Data: []byte(`{
+            "service_id": 4,
+            "data": {
+                "result": {
+                    "response_time_ms": 812.5,
+                    "status_code": 503,
+                    "error_type": "assertion",
+                    "error": "assertion status_code failed: got 503, expected 2xx",
+                    "worker_id": "worker-abc",
+                    "attempts": 3
+                }
+            }
+        }`),
+		PublishTime: time.Now().UTC(),
+	}
+
+	HandleMessage(context.Background(), msg, pubsub.ServiceDownTopic, repo)
+
+	assert.True(t, repo.saveMetricCalled, "SaveMetric should be called")
+	assert.Equal(t, "DOWN", repo.lastMetric.Type)
+	assert.Equal(t, 812.5, repo.lastMetric.ResponseTimeMs)
+	assert.Equal(t, 503, repo.lastMetric.StatusCode)
+	assert.Equal(t, "assertion", repo.lastMetric.ErrorType)
+	assert.Equal(t, "assertion status_code failed: got 503, expected 2xx", repo.lastMetric.Error)
+	assert.Equal(t, "worker-abc", repo.lastMetric.WorkerID)
+	assert.Equal(t, 3, repo.lastMetric.Attempts)
+	assert.True(t, msg.Acked, "Message should be ACKed")
+}
+
+func TestHandleMessage_MonitoringGap(t *testing.T) {
+	repo := &mockRepo{}
+
+	msg := &pubsub.FakeMessage{
+		Data: []byte(`{
+			"service_id": 7,
+			"data": {"gap": {"since": "2025-01-01T10:00:00Z", "until": "2025-01-01T10:30:00Z"}}
+		}`),
+		PublishTime: time.Now().UTC(),
+	}
+
+	HandleMessage(context.Background(), msg, pubsub.MonitoringGapTopic, repo)
+
+	assert.True(t, repo.saveMetricCalled, "SaveMetric should be called")
+	assert.Equal(t, pubsub.StatusNoData, repo.lastMetric.Type)
+	assert.Equal(t, time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), repo.lastMetric.GapSince)
+	assert.Equal(t, time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC), repo.lastMetric.Timestamp)
+	assert.True(t, msg.Acked, "Message should be ACKed")
+}
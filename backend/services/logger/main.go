@@ -25,13 +25,16 @@ func main() {
 	defer psClient.Close()
 
 	subscriptions := map[string]string{
-		"logger-incident-start":      pubsub_common.IncidentStartTopic,
-		"logger-incident-resolved":   pubsub_common.IncidentResolvedTopic,
-		"logger-incident-timeout":    pubsub_common.IncidentAcknowledgeTimeoutTopic,
-		"logger-incident-unresolved": pubsub_common.IncidentUnresolvedTopic,
-		"logger-service-up":          pubsub_common.ServiceUpTopic,
-		"logger-service-down":        pubsub_common.ServiceDownTopic,
-		"logger-notify-oncaller":     pubsub_common.NotifyOncallerTopic,
+		"logger-incident-start":        pubsub_common.IncidentStartTopic,
+		"logger-monitoring-gap":        pubsub_common.MonitoringGapTopic,
+		"logger-incident-acknowledged": pubsub_common.IncidentAcknowledgedTopic,
+		"logger-incident-recovered":    pubsub_common.IncidentRecoveredTopic,
+		"logger-incident-resolved":     pubsub_common.IncidentResolvedTopic,
+		"logger-incident-timeout":      pubsub_common.IncidentAcknowledgeTimeoutTopic,
+		"logger-incident-unresolved":   pubsub_common.IncidentUnresolvedTopic,
+		"logger-service-up":            pubsub_common.ServiceUpTopic,
+		"logger-service-down":          pubsub_common.ServiceDownTopic,
+		"logger-notify-oncaller":       pubsub_common.NotifyOncallerTopic,
 	}
 
 	pubsub_common.CreateSubscriptionsAndTopics(psClient, subscriptions, []string{})
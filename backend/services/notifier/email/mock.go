@@ -1,17 +1,33 @@
 package email
 
+import "alerting-platform/common/pubsub"
+
 type MockMailer struct {
 	SendCalled     bool
+	RecoveryCalled bool
 	LastTo         string
 	LastIncidentID string
 	LastServiceID  uint64
+	LastKind       string
+	LastCert       *pubsub.CertificateInfo
 	Err            error
 }
 
-func (m *MockMailer) SendNotification(toEmail string, incidentID string, serviceID uint64) error {
+func (m *MockMailer) SendNotification(toEmail string, incidentID string, serviceID uint64, kind string, certificate *pubsub.CertificateInfo) error {
 	m.SendCalled = true
 	m.LastTo = toEmail
 	m.LastIncidentID = incidentID
 	m.LastServiceID = serviceID
+	m.LastKind = kind
+	m.LastCert = certificate
+	return m.Err
+}
+
+func (m *MockMailer) SendRecoveryNotification(toEmail string, incidentID string, serviceID uint64, kind string) error {
+	m.RecoveryCalled = true
+	m.LastTo = toEmail
+	m.LastIncidentID = incidentID
+	m.LastServiceID = serviceID
+	m.LastKind = kind
 	return m.Err
 }
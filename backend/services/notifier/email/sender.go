@@ -4,12 +4,15 @@ import (
 	"alerting-platform/common/config"
 	"context"
 	"fmt"
+	"html"
 	"log"
 	"strconv"
+	"time"
 
 	"gopkg.in/gomail.v2"
 
 	magic_link "alerting-platform/common/magic_link"
+	pubsub_common "alerting-platform/common/pubsub"
 )
 
 type Mailer struct {
@@ -35,9 +38,22 @@ func Init(ctx context.Context) (*Mailer, error) {
 	}, nil
 }
 
-func (m *Mailer) SendNotification(toEmail string, incidentID string, serviceID uint64) error {
+func (m *Mailer) SendNotification(toEmail string, incidentID string, serviceID uint64, kind string, certificate *pubsub_common.CertificateInfo) error {
 	cfg := config.GetConfig()
 
+	acknowledgeLink, err := magic_link.GenerateAcknowledgeLink(
+		incidentID,
+		serviceID,
+		toEmail,
+		[]byte(cfg.Secret),
+		cfg.APIHost,
+		cfg.REST_APIPort,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to generate acknowledge link: %w", err)
+	}
+
 	resolveLink, err := magic_link.GenerateResolveLink(
 		incidentID,
 		serviceID,
@@ -55,34 +71,70 @@ func (m *Mailer) SendNotification(toEmail string, incidentID string, serviceID u
 
 	msg.SetHeader("From", m.from)
 	msg.SetHeader("To", toEmail)
-	msg.SetHeader("Subject", fmt.Sprintf("[ALERT] New Incident: %s", incidentID))
+	level, subject, heading := "ALERT", "New Incident", "You've got a new incident!"
+	details := ""
+	switch kind {
+	case pubsub_common.IncidentKindDegraded:
+		subject, heading = "Degraded Performance", "A service is responding slower than its latency threshold!"
+	case pubsub_common.IncidentKindFlapping:
+		subject, heading = "Service Flapping", "A service keeps changing between up and down!"
+	case pubsub_common.IncidentKindCertExpiry:
+		level, subject, heading = "WARNING", "Certificate Expiring", "A TLS certificate expires soon!"
+		if certificate != nil {
+			details = fmt.Sprintf(`
+            <p><strong>Expires:</strong> %s</p>
+            <p><strong>Subject:</strong> %s</p>
+            <p><strong>Issuer:</strong> %s</p>`,
+				certificate.NotAfter.UTC().Format(time.RFC1123),
+				html.EscapeString(certificate.Subject),
+				html.EscapeString(certificate.Issuer),
+			)
+		}
+	}
+
+	msg.SetHeader("Subject", fmt.Sprintf("[%s] %s: %s", level, subject, incidentID))
 
 	body := fmt.Sprintf(`
         <div style="font-family: Arial, sans-serif; padding: 20px; max-width: 600px;">
-            <h2 style="color: #d9534f;">You've got a new incident!</h2>
+            <h2 style="color: #d9534f;">%s</h2>
             <p><strong>ID:</strong> %s</p>
-            <p><strong>Service:</strong> %s</p>
+            <p><strong>Service:</strong> %s</p>%s
             
             <div style="margin: 25px 0;">
                 <a href="%s" style="background-color: #d9534f; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; font-weight: bold; display: inline-block;">
+                    Acknowledge Incident
+                </a>
+                <a href="%s" style="background-color: #5cb85c; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; font-weight: bold; display: inline-block; margin-left: 10px;">
                     Resolve Incident
                 </a>
             </div>
 
+            <p style="font-size: 13px; color: #555;">
+                Acknowledging stops notifying further oncallers. The incident stays open until it is resolved.
+            </p>
+
             <p style="margin-top: 30px; font-size: 13px; color: #555;">
-                If the button above doesn't work, copy and paste the following URL into your browser:
+                If the buttons above don't work, copy and paste the following URLs into your browser:
             </p>
             
             <p style="font-size: 11px; color: #777; word-break: break-all; overflow-wrap: break-word; background-color: #f9f9f9; padding: 10px; border: 1px solid #eee;">
-                %s
+                Acknowledge: %s
+            </p>
+
+            <p style="font-size: 11px; color: #777; word-break: break-all; overflow-wrap: break-word; background-color: #f9f9f9; padding: 10px; border: 1px solid #eee;">
+                Resolve: %s
             </p>
 
             <p style="font-size: 12px; color: #999; margin-top: 20px;">Link is valid for 72 hours.</p>
         </div>
     `,
+		heading,
 		incidentID,
 		strconv.FormatUint(serviceID, 10),
+		details,
+		acknowledgeLink,
 		resolveLink,
+		acknowledgeLink,
 		resolveLink,
 	)
 
@@ -96,3 +148,80 @@ func (m *Mailer) SendNotification(toEmail string, incidentID string, serviceID u
 
 	return nil
 }
+
+// SendRecoveryNotification tells an oncaller that the service of an open incident
+// is healthy again, with a link to resolve the incident
+func (m *Mailer) SendRecoveryNotification(toEmail string, incidentID string, serviceID uint64, kind string) error {
+	cfg := config.GetConfig()
+
+	resolveLink, err := magic_link.GenerateResolveLink(
+		incidentID,
+		serviceID,
+		toEmail,
+		[]byte(cfg.Secret),
+		cfg.APIHost,
+		cfg.REST_APIPort,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to generate resolve link: %w", err)
+	}
+
+	heading := "A service with an open incident is up again."
+	switch kind {
+	case pubsub_common.IncidentKindDegraded:
+		heading = "A service with an open incident is responding within its latency threshold again."
+	case pubsub_common.IncidentKindFlapping:
+		heading = "A flapping service with an open incident is stable again."
+	}
+
+	msg := gomail.NewMessage()
+
+	msg.SetHeader("From", m.from)
+	msg.SetHeader("To", toEmail)
+	msg.SetHeader("Subject", fmt.Sprintf("[RECOVERED] Service Recovered: %s", incidentID))
+
+	body := fmt.Sprintf(`
+        <div style="font-family: Arial, sans-serif; padding: 20px; max-width: 600px;">
+            <h2 style="color: #5cb85c;">%s</h2>
+            <p><strong>ID:</strong> %s</p>
+            <p><strong>Service:</strong> %s</p>
+
+            <p style="font-size: 13px; color: #555;">
+                The incident stays open until it is resolved.
+            </p>
+
+            <div style="margin: 25px 0;">
+                <a href="%s" style="background-color: #5cb85c; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; font-weight: bold; display: inline-block;">
+                    Resolve Incident
+                </a>
+            </div>
+
+            <p style="margin-top: 30px; font-size: 13px; color: #555;">
+                If the button above doesn't work, copy and paste the following URL into your browser:
+            </p>
+
+            <p style="font-size: 11px; color: #777; word-break: break-all; overflow-wrap: break-word; background-color: #f9f9f9; padding: 10px; border: 1px solid #eee;">
+                %s
+            </p>
+
+            <p style="font-size: 12px; color: #999; margin-top: 20px;">Link is valid for 72 hours.</p>
+        </div>
+    `,
+		heading,
+		incidentID,
+		strconv.FormatUint(serviceID, 10),
+		resolveLink,
+		resolveLink,
+	)
+
+	msg.SetBody("text/html", body)
+
+	if err := m.dialer.DialAndSend(msg); err != nil {
+		return fmt.Errorf("failed to send email via gomail: %w", err)
+	}
+
+	log.Printf("[INFO] Recovery email sent to %s for incident %s", toEmail, incidentID)
+
+	return nil
+}
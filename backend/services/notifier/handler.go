@@ -8,11 +8,13 @@ import (
 )
 
 type EmailSender interface {
-	SendNotification(toEmail string, incidentID string, serviceID uint64) error
+	SendNotification(toEmail string, incidentID string, serviceID uint64, kind string, certificate *pubsub.CertificateInfo) error
+	SendRecoveryNotification(toEmail string, incidentID string, serviceID uint64, kind string) error
 }
 
 var EventTypeToStatus = map[string]string{
 	pubsub.NotifyOncallerTopic: "NOTIFY",
+	pubsub.NotifyRecoveryTopic: "RECOVERY",
 }
 
 func HandleMessage(
@@ -30,9 +32,13 @@ func HandleMessage(
 
 	switch eventType {
 	case pubsub.NotifyOncallerTopic:
-		if sendErr := mailer.SendNotification(payload.OnCaller, payload.IncidentID, payload.ServiceID); sendErr != nil {
+		if sendErr := mailer.SendNotification(payload.OnCaller, payload.IncidentID, payload.ServiceID, payload.Data.IncidentKind, payload.Data.Certificate); sendErr != nil {
 			log.Printf("[ERROR] Failed to notify oncaller %s: %v", payload.OnCaller, sendErr)
 		}
+	case pubsub.NotifyRecoveryTopic:
+		if sendErr := mailer.SendRecoveryNotification(payload.OnCaller, payload.IncidentID, payload.ServiceID, payload.Data.IncidentKind); sendErr != nil {
+			log.Printf("[ERROR] Failed to notify oncaller %s of recovery: %v", payload.OnCaller, sendErr)
+		}
 	default:
 		log.Printf("[WARNING] Unhandled event type: %s", eventType)
 	}
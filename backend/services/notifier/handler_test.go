@@ -29,11 +29,65 @@ func TestHandleMessage_Notify_Success(t *testing.T) {
 	assert.Equal(t, "admin@example.com", mailer.LastTo)
 	assert.Equal(t, "INC-123", mailer.LastIncidentID)
 	assert.Equal(t, uint64(99), mailer.LastServiceID)
+	assert.Empty(t, mailer.LastKind)
 
 	assert.True(t, msg.Acked, "Message should be ACKed")
 	assert.False(t, msg.Nacked, "Message should NOT be NACKed")
 }
 
+func TestHandleMessage_Notify_Degraded(t *testing.T) {
+	mailer := &email.MockMailer{}
+
+	msg := &pubsub.FakeMessage{
+		Data:        []byte(`{"oncaller": "admin@example.com", "incident_id": "INC-123", "service_id": 99, "data": {"incident_kind": "DEGRADED"}}`),
+		PublishTime: time.Now().UTC(),
+	}
+
+	HandleMessage(context.Background(), msg, pubsub.NotifyOncallerTopic, mailer)
+
+	assert.True(t, mailer.SendCalled, "Mailer should be called")
+	assert.Equal(t, pubsub.IncidentKindDegraded, mailer.LastKind)
+	assert.True(t, msg.Acked, "Message should be ACKed")
+}
+
+func TestHandleMessage_Notify_CertExpiry(t *testing.T) {
+	mailer := &email.MockMailer{}
+
+	msg := &pubsub.FakeMessage{
+		Data: []byte(`{"oncaller": "admin@example.com", "incident_id": "INC-7", "service_id": 7, "data": {
+            "incident_kind": "CERT_EXPIRY",
+            "certificate": {"not_after": "2026-11-01T00:00:00Z", "issuer": "CN=Test CA", "subject": "CN=example.com"}
+        }}`),
+		PublishTime: time.Now().UTC(),
+	}
+
+	HandleMessage(context.Background(), msg, pubsub.NotifyOncallerTopic, mailer)
+
+	assert.Equal(t, pubsub.IncidentKindCertExpiry, mailer.LastKind)
+	if assert.NotNil(t, mailer.LastCert) {
+		assert.Equal(t, "CN=Test CA", mailer.LastCert.Issuer)
+		assert.Equal(t, "CN=example.com", mailer.LastCert.Subject)
+	}
+	assert.True(t, msg.Acked, "Message should be ACKed")
+}
+
+func TestHandleMessage_NotifyRecovery(t *testing.T) {
+	mailer := &email.MockMailer{}
+
+	msg := &pubsub.FakeMessage{
+		Data:        []byte(`{"oncaller": "admin@example.com", "incident_id": "INC-123", "service_id": 99, "data": {"incident_kind": "DOWN"}}`),
+		PublishTime: time.Now().UTC(),
+	}
+
+	HandleMessage(context.Background(), msg, pubsub.NotifyRecoveryTopic, mailer)
+
+	assert.True(t, mailer.RecoveryCalled, "Recovery email should be sent")
+	assert.False(t, mailer.SendCalled, "Incident email should NOT be sent")
+	assert.Equal(t, "admin@example.com", mailer.LastTo)
+	assert.Equal(t, "INC-123", mailer.LastIncidentID)
+	assert.True(t, msg.Acked, "Message should be ACKed")
+}
+
 func TestHandleMessage_EmailError_StillAcks(t *testing.T) {
 	originalOutput := log.Writer()
 	log.SetOutput(io.Discard)
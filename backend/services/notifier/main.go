@@ -28,6 +28,7 @@ func main() {
 
 	subscriptions := map[string]string{
 		"notifier-notify-oncaller": pubsub_common.NotifyOncallerTopic,
+		"notifier-notify-recovery": pubsub_common.NotifyRecoveryTopic,
 	}
 
 	pubsub_common.CreateSubscriptionsAndTopics(psClient, subscriptions, []string{})
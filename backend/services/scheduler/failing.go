@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/rpc"
+	redis_keys "alerting-platform/scheduler/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// recoveryRounds is how many check rounds in a row must be UP before a failing
+	// service goes back to its regular interval
+	recoveryRounds = 3
+	// failingTTL forgets failing services that stopped reporting results
+	failingTTL = 24 * time.Hour
+	// failingRoundTTL keeps the results of a round until its last location reported
+	failingRoundTTL = time.Hour
+
+	verdictField = "verdict"
+)
+
+// HandleCheckResult moves services with a failing interval to it once a round is DOWN
+// in the quorum of its locations, and back after recoveryRounds UP rounds. Results
+// reach any replica while only the owner schedules the service, so the state is kept
+// in Redis and the owner reads it on every tick. A local task is woken up right away.
+func (s *scheduler) HandleCheckResult(ctx context.Context, payload pubsub_common.PubSubPayload, down bool) error {
+	s.mu.Lock()
+	service, known := s.services[payload.ServiceID]
+	task := s.activeTasks[payload.ServiceID]
+	s.mu.Unlock()
+
+	if !known || service.FailingInterval == 0 {
+		return nil
+	}
+
+	var round int64
+	if payload.Data.Result != nil {
+		round = payload.Data.Result.Round
+	}
+
+	settled, err := s.settleRound(ctx, service, payload.Data.Result, down)
+	if err != nil || !settled {
+		return err
+	}
+
+	changed, err := s.recordResult(ctx, payload.ServiceID, round, down)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		log.Printf("[DEBUG] Service %d switched its failing interval, failing: %t", payload.ServiceID, down)
+
+		if task != nil {
+			select {
+			case task.Reschedule <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	return nil
+}
+
+// settleRound records the result of one location in its check round and reports
+// whether the round is settled in favour of it, the same way the incident manager
+// counts votes. DOWN needs the quorum of locations, UP enough locations to make that
+// quorum impossible. Results outside a round and single location services settle at once.
+func (s *scheduler) settleRound(ctx context.Context, service *rpc.ServiceInfoForScheduler, result *pubsub_common.CheckResult, down bool) (bool, error) {
+	locations := len(service.Locations)
+	if result == nil || result.Round == 0 || locations <= 1 {
+		return true, nil
+	}
+
+	location := result.Location
+	if location == "" {
+		location = pubsub_common.DefaultLocation
+	}
+
+	vote := "UP"
+	if down {
+		vote = "DOWN"
+	}
+
+	roundKey := redis_keys.GetFailingRoundKey(service.ServiceId, result.Round)
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, roundKey, location, vote)
+	pipe.Expire(ctx, roundKey, failingRoundTTL)
+	votes := pipe.HGetAll(ctx, roundKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	agreeing := 0
+	for field, value := range votes.Val() {
+		if field != verdictField && value == vote {
+			agreeing++
+		}
+	}
+
+	needed := locationQuorum(service)
+	if !down {
+		needed = locations - needed + 1
+	}
+
+	if agreeing < needed {
+		return false, nil
+	}
+
+	// Later results of the round must not count it again
+	return s.redisClient.HSetNX(ctx, roundKey, verdictField, vote).Result()
+}
+
+// locationQuorum is the number of locations that must report DOWN in one round
+func locationQuorum(service *rpc.ServiceInfoForScheduler) int {
+	locations := len(service.Locations)
+	if quorum := int(service.LocationQuorum); quorum > 0 && quorum <= locations {
+		return quorum
+	}
+	return locations/2 + 1
+}
+
+// recordResult updates the failing state and reports whether the service entered or
+// left it. Results of the other locations of a counted round are ignored.
+func (s *scheduler) recordResult(ctx context.Context, serviceID uint64, round int64, down bool) (bool, error) {
+	key := redis_keys.GetFailingKey(serviceID)
+
+	if down {
+		existed, err := s.redisClient.Exists(ctx, key).Result()
+		if err != nil {
+			return false, err
+		}
+
+		pipe := s.redisClient.TxPipeline()
+		pipe.HSet(ctx, key, "round", round, "ups", 0)
+		pipe.Expire(ctx, key, failingTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return false, err
+		}
+		return existed == 0, nil
+	}
+
+	lastRound, err := s.redisClient.HGet(ctx, key, "round").Int64()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if round != 0 && round == lastRound {
+		return false, nil
+	}
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, "round", round)
+	ups := pipe.HIncrBy(ctx, key, "ups", 1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	if ups.Val() < recoveryRounds {
+		return false, nil
+	}
+
+	return true, s.redisClient.Del(ctx, key).Err()
+}
+
+// isFailing tells the task which interval to use. Errors keep the regular interval.
+func (s *scheduler) isFailing(ctx context.Context, service *rpc.ServiceInfoForScheduler) bool {
+	if service.FailingInterval == 0 || s.redisClient == nil {
+		return false
+	}
+
+	exists, err := s.redisClient.Exists(ctx, redis_keys.GetFailingKey(service.ServiceId)).Result()
+	if err != nil {
+		log.Printf("[ERROR] Failed to read failing state of service %d: %v", service.ServiceId, err)
+		return false
+	}
+
+	return exists > 0
+}
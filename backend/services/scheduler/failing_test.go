@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/rpc"
+	redis_keys "alerting-platform/scheduler/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleCheckResult(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+	}
+	defer s.Close()
+
+	sched, _ := setupTestScheduler()
+	sched.redisClient = redis.NewClient(&redis.Options{Addr: s.Addr()})
+
+	service := &rpc.ServiceInfoForScheduler{ServiceId: 1, HealthCheckInterval: 60, FailingInterval: 10}
+	task := &Task{ID: 1, Service: service, Reschedule: make(chan struct{}, 1)}
+	sched.services[1] = service
+	sched.activeTasks[1] = task
+
+	result := func(round int64) pubsub_common.PubSubPayload {
+		return pubsub_common.PubSubPayload{
+			ServiceID: 1,
+			Data:      pubsub_common.PubSubPayloadData{Result: &pubsub_common.CheckResult{Round: round}},
+		}
+	}
+
+	woken := func() bool {
+		select {
+		case <-task.Reschedule:
+			return true
+		default:
+			return false
+		}
+	}
+
+	t.Run("UP while not failing", func(t *testing.T) {
+		assert.NoError(t, sched.HandleCheckResult(ctx, result(1), false))
+
+		assert.False(t, sched.isFailing(ctx, service))
+		assert.False(t, woken())
+	})
+
+	t.Run("DOWN switches to the failing interval", func(t *testing.T) {
+		assert.NoError(t, sched.HandleCheckResult(ctx, result(2), true))
+
+		assert.True(t, sched.isFailing(ctx, service))
+		assert.True(t, woken())
+
+		// Still failing, nothing to reschedule
+		assert.NoError(t, sched.HandleCheckResult(ctx, result(3), true))
+		assert.False(t, woken())
+	})
+
+	t.Run("Sustained UP switches back", func(t *testing.T) {
+		for round := int64(4); round < 4+recoveryRounds-1; round++ {
+			assert.NoError(t, sched.HandleCheckResult(ctx, result(round), false))
+			// Other locations of the same round do not count
+			assert.NoError(t, sched.HandleCheckResult(ctx, result(round), false))
+		}
+		assert.True(t, sched.isFailing(ctx, service))
+		assert.False(t, woken())
+
+		assert.NoError(t, sched.HandleCheckResult(ctx, result(10), false))
+
+		assert.False(t, sched.isFailing(ctx, service))
+		assert.True(t, woken())
+	})
+
+	t.Run("DOWN resets the recovery", func(t *testing.T) {
+		assert.NoError(t, sched.HandleCheckResult(ctx, result(11), true))
+		assert.NoError(t, sched.HandleCheckResult(ctx, result(12), false))
+		assert.NoError(t, sched.HandleCheckResult(ctx, result(13), true))
+		assert.NoError(t, sched.HandleCheckResult(ctx, result(14), false))
+
+		assert.Equal(t, "1", s.HGet(redis_keys.GetFailingKey(1), "ups"))
+		assert.Equal(t, failingTTL, s.TTL(redis_keys.GetFailingKey(1)))
+	})
+
+	t.Run("Services without a failing interval", func(t *testing.T) {
+		s.FlushAll()
+		regular := &rpc.ServiceInfoForScheduler{ServiceId: 2, HealthCheckInterval: 60}
+		sched.services[2] = regular
+
+		assert.NoError(t, sched.HandleCheckResult(ctx, pubsub_common.PubSubPayload{ServiceID: 2}, true))
+
+		assert.False(t, s.Exists(redis_keys.GetFailingKey(2)))
+		assert.False(t, sched.isFailing(ctx, regular))
+	})
+
+	t.Run("Multiple locations need the quorum", func(t *testing.T) {
+		s.FlushAll()
+		multi := &rpc.ServiceInfoForScheduler{ServiceId: 3, HealthCheckInterval: 60, FailingInterval: 10, Locations: []string{"eu", "us", "asia"}}
+		sched.services[3] = multi
+
+		locationResult := func(round int64, location string) pubsub_common.PubSubPayload {
+			return pubsub_common.PubSubPayload{
+				ServiceID: 3,
+				Data:      pubsub_common.PubSubPayloadData{Result: &pubsub_common.CheckResult{Round: round, Location: location}},
+			}
+		}
+
+		// A single flaky location keeps the regular interval
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(1, "eu"), true))
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(1, "us"), false))
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(1, "asia"), false))
+		assert.False(t, sched.isFailing(ctx, multi))
+
+		// The majority of the locations switches to the failing interval
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(2, "eu"), true))
+		assert.False(t, sched.isFailing(ctx, multi))
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(2, "us"), true))
+		assert.True(t, sched.isFailing(ctx, multi))
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(2, "asia"), true))
+
+		// A round counts as UP once the quorum for DOWN is impossible, and only once
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(3, "eu"), false))
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(3, "us"), false))
+		assert.NoError(t, sched.HandleCheckResult(ctx, locationResult(3, "asia"), false))
+		assert.Equal(t, "1", s.HGet(redis_keys.GetFailingKey(3), "ups"))
+	})
+
+	t.Run("Redis error", func(t *testing.T) {
+		s.SetError("redis error")
+		defer s.SetError("")
+
+		assert.Error(t, sched.HandleCheckResult(ctx, result(20), true))
+		assert.False(t, sched.isFailing(ctx, service))
+	})
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/rpc"
+	redis_keys "alerting-platform/scheduler/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// nextDueTTL forgets the cadence of removed services. Outages longer than that
+// are not reported as gaps.
+const nextDueTTL = 7 * 24 * time.Hour
+
+// resumeSchedule returns the first tick of a task, continuing the cadence stored by
+// the previous owner of the service. When more than two intervals passed since the
+// last scheduled check, the start of the gap is returned as well. For cron schedules
+// the interval is the time between the missed tick and the one after it, and the gap
+// starts at the missed tick.
+func (s *scheduler) resumeSchedule(ctx context.Context, serviceID uint64, schedule tickSchedule, now time.Time) (time.Time, *time.Time, error) {
+	nextDue, err := s.redisClient.Get(ctx, redis_keys.GetNextDueKey(serviceID)).Int64()
+	if err == redis.Nil {
+		return schedule.first(now), nil, nil
+	}
+	if err != nil {
+		return schedule.first(now), nil, err
+	}
+
+	due := time.UnixMilli(nextDue)
+
+	// Cron schedules have no cadence to continue, only the missed tick matters
+	if schedule.cron != nil {
+		cronInterval := schedule.cron.Next(due).Sub(due)
+		lastCheck := due.Add(-cronInterval)
+		if now.Sub(lastCheck) > 2*cronInterval {
+			return schedule.first(now), &due, nil
+		}
+		return schedule.first(now), nil, nil
+	}
+
+	// A tick further away than one interval is left from a longer interval
+	if due.After(now) {
+		if due.Sub(now) > schedule.interval {
+			return schedule.first(now), nil, nil
+		}
+		return due, nil, nil
+	}
+
+	planned, _ := schedule.after(due, now, false)
+
+	lastCheck := due.Add(-schedule.interval)
+	if now.Sub(lastCheck) > 2*schedule.interval {
+		return planned, &lastCheck, nil
+	}
+	return planned, nil, nil
+}
+
+func (s *scheduler) storeNextDue(ctx context.Context, serviceID uint64, planned time.Time) {
+	err := s.redisClient.Set(ctx, redis_keys.GetNextDueKey(serviceID), planned.UnixMilli(), nextDueTTL).Err()
+	if err != nil {
+		log.Printf("[ERROR] Failed to store next check of service %d: %v", serviceID, err)
+	}
+}
+
+// reportMonitoringGap tells the other services that the status of the service is
+// unknown for the gap, instead of letting the last status stand
+func (s *scheduler) reportMonitoringGap(ctx context.Context, service *rpc.ServiceInfoForScheduler, since time.Time, now time.Time) {
+	log.Printf("[WARNING] Service %d was not checked since %s", service.ServiceId, since.UTC().Format(time.RFC3339))
+
+	payload := pubsub_common.PubSubPayload{
+		ServiceID: service.ServiceId,
+		Timestamp: now.UTC().Format(time.RFC3339),
+		Data: pubsub_common.PubSubPayloadData{
+			Gap: &pubsub_common.MonitoringGap{Since: since.UTC(), Until: now.UTC()},
+		},
+	}
+
+	err := pubsub_common.SendPayload(ctx, s.pubsubClient, pubsub_common.MonitoringGapTopic, payload, fmt.Sprintf("%d", service.ServiceId))
+	if err != nil {
+		log.Printf("[ERROR] Failed to report monitoring gap of service %d: %v", service.ServiceId, err)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"alerting-platform/common/crontab"
+	redis_keys "alerting-platform/scheduler/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumeSchedule(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+	}
+	defer s.Close()
+
+	sched, _ := setupTestScheduler()
+	sched.redisClient = redis.NewClient(&redis.Options{Addr: s.Addr()})
+
+	interval := time.Minute
+	schedule := newTickSchedule(1, interval, 0)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	inPhase := schedule.first(now)
+
+	tests := []struct {
+		name    string
+		nextDue *time.Time
+		planned time.Time
+		gap     bool
+	}{
+		{name: "never scheduled", planned: inPhase},
+		{name: "due soon", nextDue: ptr(now.Add(20 * time.Second)), planned: now.Add(20 * time.Second)},
+		{name: "left from a longer interval", nextDue: ptr(now.Add(5 * time.Minute)), planned: inPhase},
+		{name: "restarted within an interval", nextDue: ptr(inPhase.Add(-interval)), planned: inPhase},
+		{name: "gap", nextDue: ptr(inPhase.Add(-2 * interval)), planned: inPhase, gap: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.FlushAll()
+			if tt.nextDue != nil {
+				s.Set(redis_keys.GetNextDueKey(1), strconv.FormatInt(tt.nextDue.UnixMilli(), 10))
+			}
+
+			planned, gapSince, err := sched.resumeSchedule(ctx, 1, schedule, now)
+
+			assert.NoError(t, err)
+			assert.True(t, tt.planned.Equal(planned), "planned %s, expected %s", planned, tt.planned)
+			if tt.gap {
+				if assert.NotNil(t, gapSince) {
+					assert.WithinDuration(t, tt.nextDue.Add(-interval), *gapSince, time.Millisecond)
+				}
+			} else {
+				assert.Nil(t, gapSince)
+			}
+		})
+	}
+
+	t.Run("Cron schedule", func(t *testing.T) {
+		cronSchedule := newTickSchedule(1, interval, 0)
+		cronSchedule.cron, _ = crontab.Parse("0 * * * *", "")
+		nextHour := time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)
+
+		s.FlushAll()
+		s.Set(redis_keys.GetNextDueKey(1), strconv.FormatInt(now.Add(-30*time.Second).UnixMilli(), 10))
+
+		planned, gapSince, err := sched.resumeSchedule(ctx, 1, cronSchedule, now)
+		assert.NoError(t, err)
+		assert.True(t, nextHour.Equal(planned))
+		assert.Nil(t, gapSince)
+
+		// Missed ticks count against the cron interval, not the interval of the service
+		s.Set(redis_keys.GetNextDueKey(1), strconv.FormatInt(now.Add(-time.Hour).UnixMilli(), 10))
+
+		planned, gapSince, err = sched.resumeSchedule(ctx, 1, cronSchedule, now)
+		assert.NoError(t, err)
+		assert.True(t, nextHour.Equal(planned))
+		assert.Nil(t, gapSince)
+
+		s.Set(redis_keys.GetNextDueKey(1), strconv.FormatInt(now.Add(-2*time.Hour).UnixMilli(), 10))
+
+		planned, gapSince, err = sched.resumeSchedule(ctx, 1, cronSchedule, now)
+		assert.NoError(t, err)
+		assert.True(t, nextHour.Equal(planned))
+		if assert.NotNil(t, gapSince) {
+			assert.True(t, now.Add(-2*time.Hour).Equal(*gapSince))
+		}
+	})
+
+	t.Run("Next due is stored", func(t *testing.T) {
+		s.FlushAll()
+
+		sched.storeNextDue(ctx, 1, now)
+
+		value, err := s.Get(redis_keys.GetNextDueKey(1))
+		assert.NoError(t, err)
+		assert.Equal(t, strconv.FormatInt(now.UnixMilli(), 10), value)
+		assert.Equal(t, nextDueTTL, s.TTL(redis_keys.GetNextDueKey(1)))
+	})
+
+	t.Run("Redis error", func(t *testing.T) {
+		s.SetError("redis error")
+		defer s.SetError("")
+
+		planned, gapSince, err := sched.resumeSchedule(ctx, 1, schedule, now)
+
+		assert.Error(t, err)
+		assert.True(t, inPhase.Equal(planned))
+		assert.Nil(t, gapSince)
+	})
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/rpc"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *scheduler) HandleMessage(ctx context.Context, msg pubsub_common.PubSubMessage, eventType string) {
+	payload, _, err := pubsub_common.ExtractPayload(msg)
+	if err != nil {
+		log.Printf("[ERROR] Error extracting payload for topic %s: %v. Dropping message.", eventType, err)
+		msg.Ack()
+		return
+	}
+
+	switch eventType {
+	case pubsub_common.ServiceCreatedTopic, pubsub_common.ServiceModifiedTopic:
+		err = s.HandleServiceChanged(ctx, payload.ServiceID)
+	case pubsub_common.ServiceRemovedTopic:
+		err = s.HandleServiceRemoved(ctx, payload.ServiceID)
+	case pubsub_common.ServiceUpTopic, pubsub_common.ServiceDownTopic:
+		err = s.HandleCheckResult(ctx, *payload, eventType == pubsub_common.ServiceDownTopic)
+	default:
+		log.Printf("[WARNING] Unknown event type: %s", eventType)
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] Error handling message for topic %s: %v", eventType, err)
+		msg.Nack()
+	} else {
+		msg.Ack()
+	}
+}
+
+// HandleServiceChanged fetches the current configuration from the API instead of
+// trusting the event, so that out-of-order events cannot apply a stale config.
+func (s *scheduler) HandleServiceChanged(ctx context.Context, serviceID uint64) error {
+	log.Printf("[DEBUG] Service %d created or modified", serviceID)
+
+	rpcCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	service, err := s.client.GetSchedulerConfiguration(rpcCtx, &rpc.SchedulerConfigRequest{ServiceId: serviceID})
+	if status.Code(err) == codes.NotFound {
+		log.Printf("[WARNING] Service %d no longer exists", serviceID)
+		s.removeTask(serviceID)
+		s.announceChange(ctx)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.applyConfiguration(ctx, service)
+	s.announceChange(ctx)
+	return nil
+}
+
+func (s *scheduler) HandleServiceRemoved(ctx context.Context, serviceID uint64) error {
+	log.Printf("[DEBUG] Service %d removed", serviceID)
+
+	s.removeTask(serviceID)
+	s.announceChange(ctx)
+	return nil
+}
+
+// announceChange lets the owner of the service pick up the change on its next
+// rebalance. Failures are only logged, the periodic reconcile covers them.
+func (s *scheduler) announceChange(ctx context.Context) {
+	if s.sharder == nil {
+		return
+	}
+
+	if err := s.sharder.announceChange(ctx); err != nil {
+		log.Printf("[ERROR] Failed to announce configuration change: %v", err)
+	}
+}
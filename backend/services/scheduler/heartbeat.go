@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/rpc"
+	redis_keys "alerting-platform/scheduler/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// missedHeartbeat returns the time of the last heartbeat and whether it is older than
+// the interval plus grace. Services that never pinged are measured from startedAt,
+// so that a new monitor gets a full period before it is reported.
+func (s *scheduler) missedHeartbeat(ctx context.Context, service *rpc.ServiceInfoForScheduler, startedAt time.Time, now time.Time) (time.Time, bool, error) {
+	last := startedAt
+
+	lastUnix, err := s.redisClient.Get(ctx, redis_keys.GetLastHeartbeatKey(service.ServiceId)).Int64()
+	if err != nil && err != redis.Nil {
+		return time.Time{}, false, err
+	}
+	if err == nil {
+		last = time.Unix(lastUnix, 0)
+	}
+
+	allowed := time.Duration(service.HealthCheckInterval+service.HeartbeatGrace) * time.Second
+	return last, now.Sub(last) > allowed, nil
+}
+
+// reportMissedHeartbeat publishes the service as down on every tick while it stays
+// silent, the same way failed checks are, so the alert window applies unchanged
+func (s *scheduler) reportMissedHeartbeat(ctx context.Context, service *rpc.ServiceInfoForScheduler, startedAt time.Time) {
+	now := time.Now()
+
+	last, missed, err := s.missedHeartbeat(ctx, service, startedAt, now)
+	if err != nil {
+		log.Printf("[ERROR] Failed to read last heartbeat of service %d: %v", service.ServiceId, err)
+		return
+	}
+
+	if !missed {
+		return
+	}
+
+	log.Printf("[DEBUG] Service %d missed its heartbeat, last one at %s", service.ServiceId, last.UTC().Format(time.RFC3339))
+
+	payload := pubsub_common.PubSubPayload{
+		ServiceID: service.ServiceId,
+		Timestamp: now.UTC().Format(time.RFC3339),
+		Data: pubsub_common.PubSubPayloadData{
+			Result: &pubsub_common.CheckResult{
+				ErrorType: pubsub_common.ErrorTypeMissedHeartbeat,
+				Error:     fmt.Sprintf("no heartbeat since %s", last.UTC().Format(time.RFC3339)),
+			},
+		},
+	}
+
+	err = pubsub_common.SendPayload(ctx, s.pubsubClient, pubsub_common.ServiceDownTopic, payload, fmt.Sprintf("%d", service.ServiceId))
+	if err != nil {
+		log.Printf("[ERROR] Failed to report missed heartbeat of service %d: %v", service.ServiceId, err)
+	}
+}
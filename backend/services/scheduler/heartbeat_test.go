@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"alerting-platform/common/rpc"
+	redis_keys "alerting-platform/scheduler/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissedHeartbeat(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+	}
+	defer s.Close()
+
+	sched, _ := setupTestScheduler()
+	sched.redisClient = redis.NewClient(&redis.Options{Addr: s.Addr()})
+
+	service := &rpc.ServiceInfoForScheduler{ServiceId: 1, HealthCheckInterval: 60, HeartbeatGrace: 30}
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name          string
+		lastHeartbeat time.Duration // before now, zero when never pinged
+		startedAgo    time.Duration
+		missed        bool
+	}{
+		{name: "recent heartbeat", lastHeartbeat: 70 * time.Second, startedAgo: time.Hour, missed: false},
+		{name: "heartbeat within grace", lastHeartbeat: 90 * time.Second, startedAgo: time.Hour, missed: false},
+		{name: "heartbeat overdue", lastHeartbeat: 91 * time.Second, startedAgo: time.Hour, missed: true},
+		{name: "never pinged, just started", startedAgo: time.Minute, missed: false},
+		{name: "never pinged, overdue", startedAgo: 2 * time.Minute, missed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.FlushAll()
+			if tt.lastHeartbeat != 0 {
+				s.Set(redis_keys.GetLastHeartbeatKey(1), strconv.FormatInt(now.Add(-tt.lastHeartbeat).Unix(), 10))
+			}
+
+			_, missed, err := sched.missedHeartbeat(ctx, service, now.Add(-tt.startedAgo), now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.missed, missed)
+		})
+	}
+
+	t.Run("Redis error", func(t *testing.T) {
+		s.SetError("redis error")
+		defer s.SetError("")
+
+		_, _, err := sched.missedHeartbeat(ctx, service, now, now)
+		assert.Error(t, err)
+	})
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -12,6 +11,7 @@ import (
 	"time"
 
 	"alerting-platform/common/config"
+	"alerting-platform/common/db"
 	"alerting-platform/common/live"
 	pubsub_common "alerting-platform/common/pubsub"
 	"alerting-platform/common/rpc"
@@ -22,73 +22,6 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
-type MonitoringTask struct {
-	ServiceId uint64 `json:"service_id,omitempty"`
-	URL       string `json:"url,omitempty"`
-}
-
-type Task struct {
-	ID     uint64
-	Cancel context.CancelFunc
-}
-
-type scheduler struct {
-	activeTasks   map[uint64]*Task
-	mu            sync.Mutex
-	client        rpc.SchedulerServiceClient
-	pubsubClient  *pubsub.Client
-	incidentTopic *pubsub.Topic // the topic we write to
-}
-
-func (s *scheduler) addTask(id uint64, t *Task) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.activeTasks[id] = t
-}
-
-func (s *scheduler) scheduleMonitor(ctx context.Context, service *rpc.ServiceInfoForScheduler) {
-	goRoutineCtx, cancel := context.WithCancel(ctx)
-	serviceId := service.ServiceId
-	healthCheckInterval := service.HealthCheckInterval
-	url := service.Url
-	task := &Task{
-		ID:     serviceId,
-		Cancel: cancel,
-	}
-
-	s.addTask(service.ServiceId, task)
-	go func(ctx context.Context, interval int64, serviceId uint64, url string) {
-		d := time.Duration(interval) * time.Second
-		ticker := time.NewTicker(d)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-goRoutineCtx.Done():
-				return
-			case <-ticker.C:
-				// here the message is sent to the broker
-				monitoringTask := MonitoringTask{
-					ServiceId: serviceId,
-					URL:       url,
-				}
-
-				data, err := json.Marshal(monitoringTask)
-				if err != nil {
-					log.Printf("Error marshaling task: %v", err)
-					continue
-				}
-
-				err = pubsub_common.SendMessage(goRoutineCtx, s.pubsubClient, pubsub_common.ExecuteHealthCheckTopic, data, fmt.Sprintf("%d", serviceId))
-
-				if err != nil {
-					log.Printf("Error could not write monitoringTask to the broker: %v\n", err)
-				}
-			}
-		}
-
-	}(goRoutineCtx, healthCheckInterval, serviceId, url)
-}
-
 func main() {
 	config.Intro("Scheduler")
 	cfg := config.GetConfig()
@@ -126,22 +59,64 @@ func main() {
 
 	sched := &scheduler{
 		activeTasks:   make(map[uint64]*Task),
+		services:      make(map[uint64]*rpc.ServiceInfoForScheduler),
 		client:        client,
 		pubsubClient:  pubsubClient,
 		incidentTopic: pubsubClient.Topic(pubsub_common.ExecuteHealthCheckTopic),
+		redisClient:   db.GetRedisClient(),
+		jitterPercent: cfg.SchedulerJitter,
+		spread:        &publishSpread{},
+		sharder: newSharder(
+			db.GetRedisClient(),
+			config.GetInstanceID(),
+			cfg.SchedulerPartitions,
+			time.Duration(cfg.SchedulerLeaseTTL)*time.Second,
+		),
 	}
 
+	log.Printf("[INFO] Running as scheduler replica %s", sched.sharder.id)
+	sched.spread.publish("scheduler_publish_spread")
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	for _, service := range resp.Services {
-		sched.scheduleMonitor(ctx, service)
+		sched.applyConfiguration(ctx, service)
 	}
 
 	wg := sync.WaitGroup{}
 	live.StartLiveServer(&wg)
+	StartPubSubListener(ctx, &wg, pubsubClient, sched)
+	sched.startReconciler(ctx, time.Duration(cfg.SchedulerReconcileInterval)*time.Second)
+	shardLoopDone := sched.startShardLoop(ctx)
 
 	// its waiting for a ctrl+c signal
 	<-ctx.Done()
+	<-shardLoopDone
 	wg.Done()
 }
+
+func StartPubSubListener(ctx context.Context, wg *sync.WaitGroup, psClient *pubsub.Client, sched *scheduler) {
+	subscriptions := map[string]string{
+		"scheduler-service-created":  pubsub_common.ServiceCreatedTopic,
+		"scheduler-service-modified": pubsub_common.ServiceModifiedTopic,
+		"scheduler-service-removed":  pubsub_common.ServiceRemovedTopic,
+		"scheduler-service-up":       pubsub_common.ServiceUpTopic,
+		"scheduler-service-down":     pubsub_common.ServiceDownTopic,
+	}
+
+	topics := []string{pubsub_common.ExecuteHealthCheckTopic, pubsub_common.ServiceDownTopic, pubsub_common.MonitoringGapTopic}
+	for _, location := range config.GetCheckLocations() {
+		if topic := pubsub_common.ExecuteHealthCheckTopicFor(location); topic != pubsub_common.ExecuteHealthCheckTopic {
+			topics = append(topics, topic)
+		}
+	}
+
+	pubsub_common.CreateSubscriptionsAndTopics(psClient, subscriptions, topics)
+	pubsub_common.SetupSubscriptionListeners(ctx, psClient, subscriptions, wg, func(_ context.Context, msg pubsub_common.PubSubMessage, eventType string) {
+		// Tasks outlive the message, so they are bound to the service context instead
+		sched.HandleMessage(ctx, msg, eventType)
+	})
+
+	log.Println("[INFO] Pub/Sub listener started")
+}
@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"alerting-platform/common/config"
+	"strconv"
+)
+
+func GetMembersSetKey() string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":members"
+}
+
+func GetPartitionLeaseKey(partition int) string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":partition:" + strconv.Itoa(partition) + ":lease"
+}
+
+func GetConfigVersionKey() string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":config_version"
+}
+
+// GetLastHeartbeatKey is written by the API whenever a heartbeat service pings it
+func GetLastHeartbeatKey(serviceID uint64) string {
+	return "common:service:" + strconv.FormatUint(serviceID, 10) + ":last_heartbeat"
+}
+
+// GetNextDueKey holds the next planned tick of a service in Unix milliseconds, so
+// that the next owner of the service keeps its cadence
+func GetNextDueKey(serviceID uint64) string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":next_due"
+}
+
+// GetFailingKey exists while a service with a failing interval is failing. It holds
+// the last counted round and how many rounds in a row were UP since.
+func GetFailingKey(serviceID uint64) string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":failing"
+}
+
+// GetFailingRoundKey holds the result of every location for one check round, and
+// the verdict once enough of them agree
+func GetFailingRoundKey(serviceID uint64, round int64) string {
+	cfg := config.GetConfig()
+	return cfg.RedisPrefix + ":service:" + strconv.FormatUint(serviceID, 10) + ":failing_round:" + strconv.FormatInt(round, 10)
+}
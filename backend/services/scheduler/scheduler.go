@@ -0,0 +1,416 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"alerting-platform/common/crontab"
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/rpc"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type Task struct {
+	ID         uint64
+	Service    *rpc.ServiceInfoForScheduler
+	Cancel     context.CancelFunc
+	Reschedule chan struct{} // the service entered or left its failing interval
+}
+
+type scheduler struct {
+	activeTasks   map[uint64]*Task                        // tasks of owned services only
+	services      map[uint64]*rpc.ServiceInfoForScheduler // all known services
+	mu            sync.Mutex
+	client        rpc.SchedulerServiceClient
+	pubsubClient  *pubsub.Client
+	incidentTopic *pubsub.Topic // the topic we write to
+	sharder       *sharder      // nil owns every service, main always shards so that replicas can be added
+	redisClient   *redis.Client // last heartbeats of heartbeat services
+	jitterPercent int           // of the interval, see tickSchedule
+	spread        *publishSpread
+}
+
+func (s *scheduler) owns(serviceID uint64) bool {
+	return s.sharder == nil || s.sharder.owns(serviceID)
+}
+
+// applyConfiguration starts a task for the service, restarting the running one
+// if its configuration changed. Unchanged services keep their ticker.
+func (s *scheduler) applyConfiguration(ctx context.Context, service *rpc.ServiceInfoForScheduler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.services[service.ServiceId] = service
+
+	if !s.owns(service.ServiceId) {
+		s.cancelTask(service.ServiceId)
+		return
+	}
+
+	if task, exists := s.activeTasks[service.ServiceId]; exists {
+		if proto.Equal(task.Service, service) {
+			return
+		}
+
+		log.Printf("[INFO] Restarting task for service %d", service.ServiceId)
+		task.Cancel()
+	}
+
+	s.scheduleMonitor(ctx, service)
+}
+
+func (s *scheduler) removeTask(serviceID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.services, serviceID)
+	s.cancelTask(serviceID)
+}
+
+// Should be locked before calling
+func (s *scheduler) cancelTask(serviceID uint64) {
+	task, exists := s.activeTasks[serviceID]
+	if !exists {
+		return
+	}
+
+	log.Printf("[INFO] Cancelling task for service %d", serviceID)
+	task.Cancel()
+	delete(s.activeTasks, serviceID)
+}
+
+// rebalanceTasks starts tasks of services whose partition was acquired and
+// cancels tasks of services whose partition was lost
+func (s *scheduler) rebalanceTasks(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, service := range s.services {
+		_, running := s.activeTasks[id]
+		owned := s.owns(id)
+
+		if owned && !running {
+			s.scheduleMonitor(ctx, service)
+		} else if !owned && running {
+			s.cancelTask(id)
+		}
+	}
+}
+
+// reconcile brings the active tasks in line with the snapshot returned by the API,
+// so that events missed by the subscriptions are eventually applied.
+func (s *scheduler) reconcile(ctx context.Context) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := s.client.GetAllSchedulerConfigurations(rpcCtx, &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	configured := make(map[uint64]bool, len(resp.Services))
+	for _, service := range resp.Services {
+		configured[service.ServiceId] = true
+		s.applyConfiguration(ctx, service)
+	}
+
+	s.mu.Lock()
+	var stale []uint64
+	for id := range s.services {
+		if !configured[id] {
+			stale = append(stale, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range stale {
+		s.removeTask(id)
+	}
+
+	return nil
+}
+
+// startShardLoop keeps partition leases alive, moves tasks when ownership changes
+// and reconciles early when another replica announced a configuration change.
+// The returned channel is closed once the leases are released on shutdown.
+func (s *scheduler) startShardLoop(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+
+	if s.sharder == nil {
+		go func() {
+			<-ctx.Done()
+			close(done)
+		}()
+		return done
+	}
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(s.sharder.leaseTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			changed, err := s.sharder.rebalance(ctx)
+			if err != nil {
+				log.Printf("[ERROR] Failed to rebalance partitions: %v", err)
+			}
+
+			if changed {
+				s.rebalanceTasks(ctx)
+			}
+
+			configChanged, err := s.sharder.configChanged(ctx)
+			if err != nil {
+				log.Printf("[ERROR] Failed to check configuration version: %v", err)
+			}
+
+			if configChanged {
+				if err := s.reconcile(ctx); err != nil {
+					log.Printf("[ERROR] Failed to reconcile scheduler configurations: %v", err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				s.sharder.leave(context.Background())
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return done
+}
+
+func (s *scheduler) startReconciler(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.reconcile(ctx); err != nil {
+					log.Printf("[ERROR] Failed to reconcile scheduler configurations: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Should be locked before calling
+func (s *scheduler) scheduleMonitor(ctx context.Context, service *rpc.ServiceInfoForScheduler) {
+	goRoutineCtx, cancel := context.WithCancel(ctx)
+	serviceId := service.ServiceId
+	healthCheckInterval := service.HealthCheckInterval
+	task := &Task{
+		ID:         serviceId,
+		Service:    service,
+		Cancel:     cancel,
+		Reschedule: make(chan struct{}, 1),
+	}
+
+	s.activeTasks[serviceId] = task
+	go func(ctx context.Context, interval int64, serviceId uint64) {
+		startedAt := time.Now()
+		schedule := newTickSchedule(serviceId, time.Duration(interval)*time.Second, s.jitterPercent)
+		schedule.failing = time.Duration(service.FailingInterval) * time.Second
+		if service.CronSchedule != "" {
+			cronSchedule, err := crontab.Parse(service.CronSchedule, service.CronTimezone)
+			if err != nil {
+				log.Printf("[ERROR] Invalid schedule of service %d, using its interval: %v", serviceId, err)
+			} else {
+				schedule.cron = cronSchedule
+			}
+		}
+		planned := schedule.first(startedAt)
+		if s.redisClient != nil {
+			resumed, gapSince, err := s.resumeSchedule(ctx, serviceId, schedule, startedAt)
+			if err != nil {
+				log.Printf("[ERROR] Failed to read next check of service %d: %v", serviceId, err)
+			}
+			planned = resumed
+
+			// Heartbeats are recorded by the API, so they have no gaps
+			if gapSince != nil && service.CheckType != pubsub_common.CheckTypeHeartbeat {
+				s.reportMonitoringGap(ctx, service, *gapSince, startedAt)
+			}
+			s.storeNextDue(ctx, serviceId, planned)
+		}
+		last := startedAt
+		timer := time.NewTimer(time.Until(planned))
+		defer timer.Stop()
+		for {
+			select {
+			case <-goRoutineCtx.Done():
+				return
+			case <-task.Reschedule:
+				// Failing services are checked sooner, recovered ones keep the planned
+				// tick and return to their phase after it
+				if !s.isFailing(ctx, service) {
+					continue
+				}
+
+				if sooner, _ := schedule.after(last, time.Now(), true); sooner.Before(planned) {
+					planned = sooner
+					timer.Reset(time.Until(planned))
+				}
+			case <-timer.C:
+				failing := s.isFailing(ctx, service)
+				last = planned
+
+				var fireAt time.Time
+				planned, fireAt = schedule.after(last, time.Now(), failing)
+				timer.Reset(time.Until(fireAt))
+
+				// The partition may have been lost since the task was started
+				if !s.owns(serviceId) {
+					continue
+				}
+
+				if s.redisClient != nil {
+					s.storeNextDue(ctx, serviceId, planned)
+				}
+
+				// Heartbeat services report themselves, only their silence is checked here
+				if service.CheckType == pubsub_common.CheckTypeHeartbeat {
+					s.reportMissedHeartbeat(goRoutineCtx, service, startedAt)
+					continue
+				}
+
+				// here the messages are sent to the broker, one per location
+				for _, monitoringTask := range buildMonitoringTasks(service, time.Now()) {
+					// Workers drop tasks older than the interval they were scheduled at
+					if failing {
+						monitoringTask.HealthCheckInterval = int(service.FailingInterval)
+					}
+
+					data, err := json.Marshal(monitoringTask)
+					if err != nil {
+						log.Printf("Error marshaling task: %v", err)
+						continue
+					}
+
+					topic := pubsub_common.ExecuteHealthCheckTopicFor(monitoringTask.Location)
+					err = pubsub_common.SendMessage(goRoutineCtx, s.pubsubClient, topic, data, fmt.Sprintf("%d", serviceId))
+
+					if err != nil {
+						log.Printf("Error could not write monitoringTask to the broker: %v\n", err)
+						continue
+					}
+					s.spread.record(time.Now())
+				}
+			}
+		}
+
+	}(goRoutineCtx, healthCheckInterval, serviceId)
+}
+
+// buildMonitoringTasks creates the tasks of one tick, one per location of the service.
+// Services without locations are checked once by the default location.
+func buildMonitoringTasks(service *rpc.ServiceInfoForScheduler, scheduledAt time.Time) []pubsub_common.MonitoringTask {
+	locations := service.Locations
+	if len(locations) == 0 {
+		locations = []string{pubsub_common.DefaultLocation}
+	}
+
+	tasks := make([]pubsub_common.MonitoringTask, 0, len(locations))
+	for _, location := range locations {
+		tasks = append(tasks, pubsub_common.MonitoringTask{
+			ServiceID: service.ServiceId,
+			URL:       service.Url,
+			CheckType: service.CheckType,
+			Port:      int(service.Port),
+
+			GRPCServiceName: service.GrpcServiceName,
+			GRPCUseTLS:      service.GrpcUseTls,
+			GRPCTimeout:     int(service.GrpcTimeout),
+
+			DNSRecordType: service.DnsRecordType,
+			DNSResolvers:  service.DnsResolvers,
+			DNSExpected:   service.DnsExpected,
+
+			Steps: toSteps(service.Steps),
+
+			ExpectedStatusCodes: toInts(service.ExpectedStatusCodes),
+			BodyContains:        service.BodyContains,
+			BodyRegex:           service.BodyRegex,
+			JSONPath:            service.JsonPath,
+			JSONPathEquals:      service.JsonPathEquals,
+
+			Method:              service.Method,
+			EncryptedHeaders:    service.EncryptedHeaders,
+			RequestBody:         service.RequestBody,
+			AuthType:            service.AuthType,
+			AuthUsername:        service.AuthUsername,
+			EncryptedAuthSecret: service.EncryptedAuthSecret,
+
+			Retries:        int(service.Retries),
+			RetryBackoff:   int(service.RetryBackoff),
+			AttemptTimeout: int(service.AttemptTimeout),
+
+			Location: location,
+			Round:    scheduledAt.UnixMilli(),
+
+			ScheduledAt:         scheduledAt,
+			HealthCheckInterval: int(service.HealthCheckInterval),
+		})
+	}
+	return tasks
+}
+
+func toSteps(steps []*rpc.SyntheticStep) []pubsub_common.SyntheticStep {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	result := make([]pubsub_common.SyntheticStep, len(steps))
+	for i, step := range steps {
+		extract := make([]pubsub_common.VariableExtraction, len(step.Extract))
+		for j, extraction := range step.Extract {
+			extract[j] = pubsub_common.VariableExtraction{
+				Name:     extraction.Name,
+				JSONPath: extraction.JsonPath,
+				Header:   extraction.Header,
+			}
+		}
+
+		result[i] = pubsub_common.SyntheticStep{
+			Name:                step.Name,
+			Method:              step.Method,
+			URL:                 step.Url,
+			Headers:             step.Headers,
+			Body:                step.Body,
+			ExpectedStatusCodes: toInts(step.ExpectedStatusCodes),
+			BodyContains:        step.BodyContains,
+			BodyRegex:           step.BodyRegex,
+			JSONPath:            step.JsonPath,
+			JSONPathEquals:      step.JsonPathEquals,
+			Extract:             extract,
+		}
+	}
+	return result
+}
+
+func toInts(values []int32) []int {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]int, len(values))
+	for i, value := range values {
+		result[i] = int(value)
+	}
+	return result
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/rpc"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type fakeSchedulerClient struct {
+	services map[uint64]*rpc.ServiceInfoForScheduler
+	err      error
+}
+
+func (f *fakeSchedulerClient) GetAllSchedulerConfigurations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*rpc.SchedulerConfigResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	resp := &rpc.SchedulerConfigResponse{}
+	for _, service := range f.services {
+		resp.Services = append(resp.Services, service)
+	}
+	return resp, nil
+}
+
+func (f *fakeSchedulerClient) GetSchedulerConfiguration(ctx context.Context, in *rpc.SchedulerConfigRequest, opts ...grpc.CallOption) (*rpc.ServiceInfoForScheduler, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	service, exists := f.services[in.ServiceId]
+	if !exists {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return service, nil
+}
+
+func setupTestScheduler(services ...*rpc.ServiceInfoForScheduler) (*scheduler, *fakeSchedulerClient) {
+	client := &fakeSchedulerClient{services: make(map[uint64]*rpc.ServiceInfoForScheduler)}
+	for _, service := range services {
+		client.services[service.ServiceId] = service
+	}
+
+	sched := &scheduler{
+		activeTasks: make(map[uint64]*Task),
+		services:    make(map[uint64]*rpc.ServiceInfoForScheduler),
+		client:      client,
+	}
+
+	return sched, client
+}
+
+func newService(id uint64, url string) *rpc.ServiceInfoForScheduler {
+	// Interval is long enough for the ticker to never fire during a test
+	return &rpc.ServiceInfoForScheduler{ServiceId: id, Url: url, HealthCheckInterval: 3600}
+}
+
+func eventMessage(serviceID uint64) *pubsub_common.FakeMessage {
+	return &pubsub_common.FakeMessage{
+		Data:        []byte(fmt.Sprintf(`{"service_id": %d}`, serviceID)),
+		PublishTime: time.Now().UTC(),
+	}
+}
+
+func TestHandleServiceCreated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sched, _ := setupTestScheduler(newService(1, "http://example.com"))
+
+	msg := eventMessage(1)
+	sched.HandleMessage(ctx, msg, pubsub_common.ServiceCreatedTopic)
+
+	assert.True(t, msg.Acked)
+	assert.Contains(t, sched.activeTasks, uint64(1))
+	assert.Equal(t, "http://example.com", sched.activeTasks[1].Service.Url)
+}
+
+func TestHandleServiceModified(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("Restarts changed task", func(t *testing.T) {
+		sched, client := setupTestScheduler(newService(1, "http://old.com"))
+		sched.applyConfiguration(ctx, client.services[1])
+		oldTask := sched.activeTasks[1]
+
+		client.services[1] = newService(1, "http://new.com")
+
+		msg := eventMessage(1)
+		sched.HandleMessage(ctx, msg, pubsub_common.ServiceModifiedTopic)
+
+		assert.True(t, msg.Acked)
+		assert.NotSame(t, oldTask, sched.activeTasks[1])
+		assert.Equal(t, "http://new.com", sched.activeTasks[1].Service.Url)
+	})
+
+	t.Run("Keeps unchanged task", func(t *testing.T) {
+		sched, client := setupTestScheduler(newService(1, "http://same.com"))
+		sched.applyConfiguration(ctx, client.services[1])
+		oldTask := sched.activeTasks[1]
+
+		msg := eventMessage(1)
+		sched.HandleMessage(ctx, msg, pubsub_common.ServiceModifiedTopic)
+
+		assert.True(t, msg.Acked)
+		assert.Same(t, oldTask, sched.activeTasks[1])
+	})
+
+	t.Run("Removes task of deleted service", func(t *testing.T) {
+		sched, client := setupTestScheduler(newService(1, "http://example.com"))
+		sched.applyConfiguration(ctx, client.services[1])
+		delete(client.services, 1)
+
+		msg := eventMessage(1)
+		sched.HandleMessage(ctx, msg, pubsub_common.ServiceModifiedTopic)
+
+		assert.True(t, msg.Acked)
+		assert.NotContains(t, sched.activeTasks, uint64(1))
+	})
+
+	t.Run("RPC error Nacks", func(t *testing.T) {
+		sched, client := setupTestScheduler(newService(1, "http://example.com"))
+		client.err = errors.New("unavailable")
+
+		msg := eventMessage(1)
+		sched.HandleMessage(ctx, msg, pubsub_common.ServiceModifiedTopic)
+
+		assert.True(t, msg.Nacked)
+		assert.Empty(t, sched.activeTasks)
+	})
+}
+
+func TestHandleServiceRemoved(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sched, client := setupTestScheduler(newService(1, "http://example.com"))
+	sched.applyConfiguration(ctx, client.services[1])
+
+	msg := eventMessage(1)
+	sched.HandleMessage(ctx, msg, pubsub_common.ServiceRemovedTopic)
+
+	assert.True(t, msg.Acked)
+	assert.NotContains(t, sched.activeTasks, uint64(1))
+}
+
+func TestReconcile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("Converges to snapshot", func(t *testing.T) {
+		sched, client := setupTestScheduler(newService(1, "http://one.com"), newService(2, "http://two.com"))
+		sched.applyConfiguration(ctx, client.services[1])
+		sched.applyConfiguration(ctx, client.services[2])
+		sched.applyConfiguration(ctx, newService(3, "http://removed.com"))
+
+		client.services[2] = newService(2, "http://changed.com")
+		client.services[4] = newService(4, "http://missed.com")
+
+		err := sched.reconcile(ctx)
+		assert.NoError(t, err)
+
+		assert.Len(t, sched.activeTasks, 3)
+		assert.Equal(t, "http://one.com", sched.activeTasks[1].Service.Url)
+		assert.Equal(t, "http://changed.com", sched.activeTasks[2].Service.Url)
+		assert.Equal(t, "http://missed.com", sched.activeTasks[4].Service.Url)
+		assert.NotContains(t, sched.activeTasks, uint64(3))
+	})
+
+	t.Run("RPC error keeps tasks", func(t *testing.T) {
+		sched, client := setupTestScheduler(newService(1, "http://one.com"))
+		sched.applyConfiguration(ctx, client.services[1])
+		client.err = errors.New("unavailable")
+
+		err := sched.reconcile(ctx)
+		assert.Error(t, err)
+		assert.Contains(t, sched.activeTasks, uint64(1))
+	})
+}
+
+func TestBuildMonitoringTasks(t *testing.T) {
+	t.Run("Default location", func(t *testing.T) {
+		scheduledAt := time.UnixMilli(100)
+		tasks := buildMonitoringTasks(newService(1, "http://one"), scheduledAt)
+
+		assert.Len(t, tasks, 1)
+		assert.Equal(t, pubsub_common.DefaultLocation, tasks[0].Location)
+		assert.Equal(t, int64(100), tasks[0].Round)
+		assert.Equal(t, scheduledAt, tasks[0].ScheduledAt)
+		assert.Equal(t, 3600, tasks[0].HealthCheckInterval)
+		assert.Equal(t, "http://one", tasks[0].URL)
+	})
+
+	t.Run("One task per location", func(t *testing.T) {
+		service := newService(2, "http://two")
+		service.Locations = []string{"eu-west", "us-east", "ap-south"}
+		service.Retries = 2
+		service.RetryBackoff = 500
+		service.Steps = []*rpc.SyntheticStep{{
+			Name:    "login",
+			Url:     "https://shop.example.com/login",
+			Extract: []*rpc.VariableExtraction{{Name: "token", JsonPath: "$.token"}},
+		}}
+
+		tasks := buildMonitoringTasks(service, time.UnixMilli(200))
+
+		assert.Len(t, tasks, 3)
+		for i, location := range service.Locations {
+			assert.Equal(t, location, tasks[i].Location)
+			assert.Equal(t, int64(200), tasks[i].Round)
+			assert.Equal(t, uint64(2), tasks[i].ServiceID)
+			assert.Equal(t, 2, tasks[i].Retries)
+			assert.Equal(t, 500, tasks[i].RetryBackoff)
+			if assert.Len(t, tasks[i].Steps, 1) {
+				assert.Equal(t, "https://shop.example.com/login", tasks[i].Steps[0].URL)
+				assert.Equal(t, "$.token", tasks[i].Steps[0].Extract[0].JSONPath)
+			}
+		}
+	})
+}
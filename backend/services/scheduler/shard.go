@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	redis_keys "alerting-platform/scheduler/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Renews the lease only if it is still held by the caller
+var renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// Releases the lease only if it is still held by the caller
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// sharder splits services between scheduler replicas. Every service hashes to one
+// of a fixed number of partitions. The preferred owner of a partition is picked by
+// rendezvous hashing over the live replicas, so membership changes move as few
+// partitions as possible. A replica publishes checks only for partitions whose
+// Redis lease it holds, which guarantees at most one owner per partition even
+// while replicas disagree about membership.
+type sharder struct {
+	redisClient *redis.Client
+	id          string
+	partitions  int
+	leaseTTL    time.Duration
+	now         func() time.Time
+
+	mu            sync.RWMutex
+	owned         map[int]time.Time // partition -> local lease deadline
+	configVersion int64
+}
+
+func newSharder(redisClient *redis.Client, id string, partitions int, leaseTTL time.Duration) *sharder {
+	return &sharder{
+		redisClient: redisClient,
+		id:          id,
+		partitions:  partitions,
+		leaseTTL:    leaseTTL,
+		now:         time.Now,
+		owned:       make(map[int]time.Time),
+	}
+}
+
+func (sh *sharder) partitionOf(serviceID uint64) int {
+	return int(shardHash(strconv.FormatUint(serviceID, 10)) % uint64(sh.partitions))
+}
+
+// owns trusts the lease only until its local deadline, so a replica that stalls
+// and misses renewals stops publishing before anyone else can claim the partition
+func (sh *sharder) owns(serviceID uint64) bool {
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.now().Before(sh.owned[sh.partitionOf(serviceID)])
+}
+
+// rebalance refreshes membership, claims, renews or releases partition leases and
+// reports whether the set of owned partitions changed.
+func (sh *sharder) rebalance(ctx context.Context) (bool, error) {
+	members, err := sh.heartbeat(ctx)
+	if err != nil {
+		sh.dropAll()
+		return true, err
+	}
+
+	changed := false
+	for partition := 0; partition < sh.partitions; partition++ {
+		// Taken before talking to Redis, so the local deadline never outlives the lease
+		deadline := sh.now().Add(sh.leaseTTL)
+
+		owner := preferredOwner(members, partition)
+		owned, err := sh.updateLease(ctx, partition, owner == sh.id)
+		if err != nil {
+			log.Printf("[ERROR] Failed to update lease for partition %d: %v", partition, err)
+		}
+
+		sh.mu.Lock()
+		_, wasOwned := sh.owned[partition]
+		if wasOwned != owned {
+			changed = true
+		}
+		if owned {
+			sh.owned[partition] = deadline
+		} else {
+			delete(sh.owned, partition)
+		}
+		sh.mu.Unlock()
+	}
+
+	return changed, nil
+}
+
+// heartbeat registers this replica as alive and returns all live replicas
+func (sh *sharder) heartbeat(ctx context.Context) ([]string, error) {
+	membersKey := redis_keys.GetMembersSetKey()
+	now := sh.now()
+
+	pipe := sh.redisClient.TxPipeline()
+	pipe.ZAdd(ctx, membersKey, redis.Z{
+		Score:  float64(now.Add(sh.leaseTTL).UnixMilli()),
+		Member: sh.id,
+	})
+	pipe.ZRemRangeByScore(ctx, membersKey, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+	members := pipe.ZRange(ctx, membersKey, 0, -1)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return members.Val(), nil
+}
+
+// Should be called with ownership decided by preferredOwner
+func (sh *sharder) updateLease(ctx context.Context, partition int, preferred bool) (bool, error) {
+	leaseKey := redis_keys.GetPartitionLeaseKey(partition)
+	ttl := strconv.FormatInt(sh.leaseTTL.Milliseconds(), 10)
+
+	sh.mu.RLock()
+	_, held := sh.owned[partition]
+	sh.mu.RUnlock()
+
+	switch {
+	case preferred && held:
+		renewed, err := renewLeaseScript.Run(ctx, sh.redisClient, []string{leaseKey}, sh.id, ttl).Int()
+		return err == nil && renewed == 1, err
+	case preferred && !held:
+		// Fails while the previous owner still holds the lease; retried on the next round
+		return sh.redisClient.SetNX(ctx, leaseKey, sh.id, sh.leaseTTL).Result()
+	case held:
+		err := releaseLeaseScript.Run(ctx, sh.redisClient, []string{leaseKey}, sh.id).Err()
+		return false, err
+	}
+
+	return false, nil
+}
+
+// leave releases all leases so that survivors do not have to wait for them to expire
+func (sh *sharder) leave(ctx context.Context) {
+	sh.mu.RLock()
+	var owned []int
+	for partition := range sh.owned {
+		owned = append(owned, partition)
+	}
+	sh.mu.RUnlock()
+
+	for _, partition := range owned {
+		releaseLeaseScript.Run(ctx, sh.redisClient, []string{redis_keys.GetPartitionLeaseKey(partition)}, sh.id)
+	}
+
+	sh.redisClient.ZRem(ctx, redis_keys.GetMembersSetKey(), sh.id)
+	sh.dropAll()
+}
+
+func (sh *sharder) dropAll() {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.owned = make(map[int]time.Time)
+}
+
+// announceChange tells other replicas that a service configuration changed.
+// Only one replica receives each service event, which may not be the owner.
+func (sh *sharder) announceChange(ctx context.Context) error {
+	return sh.redisClient.Incr(ctx, redis_keys.GetConfigVersionKey()).Err()
+}
+
+// configChanged reports whether another replica announced a change since the last call
+func (sh *sharder) configChanged(ctx context.Context) (bool, error) {
+	version, err := sh.redisClient.Get(ctx, redis_keys.GetConfigVersionKey()).Int64()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	changed := version != sh.configVersion
+	sh.configVersion = version
+	return changed, nil
+}
+
+func preferredOwner(members []string, partition int) string {
+	var owner string
+	var best uint64
+
+	for _, member := range members {
+		score := shardHash(member + "/" + strconv.Itoa(partition))
+		if owner == "" || score > best {
+			owner, best = member, score
+		}
+	}
+
+	return owner
+}
+
+// shardHash must be stable across replicas and mix well, as replica IDs
+// often differ in a single character
+func shardHash(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"alerting-platform/common/rpc"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPartitions = 16
+	testLeaseTTL   = 15 * time.Second
+	testServices   = 200
+)
+
+type testCluster struct {
+	s        *miniredis.Miniredis
+	clock    time.Time
+	sharders []*sharder
+}
+
+func setupTestCluster(t *testing.T, replicas int) *testCluster {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+	}
+
+	cluster := &testCluster{s: s, clock: time.Now()}
+	for i := 0; i < replicas; i++ {
+		cluster.join(fmt.Sprintf("scheduler-%d", i))
+	}
+
+	return cluster
+}
+
+func (c *testCluster) join(id string) *sharder {
+	sh := newSharder(redis.NewClient(&redis.Options{Addr: c.s.Addr()}), id, testPartitions, testLeaseTTL)
+	sh.now = func() time.Time { return c.clock }
+	c.sharders = append(c.sharders, sh)
+	return sh
+}
+
+// kill removes the replica without releasing its leases, as if it crashed
+func (c *testCluster) kill(id string) {
+	for i, sh := range c.sharders {
+		if sh.id == id {
+			c.sharders = append(c.sharders[:i], c.sharders[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *testCluster) advance(d time.Duration) {
+	c.clock = c.clock.Add(d)
+	c.s.FastForward(d)
+}
+
+// round runs one rebalance on every replica and checks that no service
+// ever has two owners, even in the middle of a round
+func (c *testCluster) round(t *testing.T) {
+	for _, sh := range c.sharders {
+		_, err := sh.rebalance(context.Background())
+		assert.NoError(t, err)
+
+		for id := uint64(1); id <= testServices; id++ {
+			assert.LessOrEqual(t, c.owners(id), 1, "service %d has more than one owner", id)
+		}
+	}
+}
+
+// converge lets replicas that started after the first one take over their partitions
+func (c *testCluster) converge(t *testing.T) {
+	c.round(t)
+	c.round(t)
+}
+
+func (c *testCluster) owners(serviceID uint64) int {
+	owners := 0
+	for _, sh := range c.sharders {
+		if sh.owns(serviceID) {
+			owners++
+		}
+	}
+	return owners
+}
+
+func (c *testCluster) assertExactlyOneOwner(t *testing.T) {
+	for id := uint64(1); id <= testServices; id++ {
+		assert.Equal(t, 1, c.owners(id), "service %d should have exactly one owner", id)
+	}
+}
+
+func TestShardingSingleOwner(t *testing.T) {
+	cluster := setupTestCluster(t, 3)
+	defer cluster.s.Close()
+
+	cluster.converge(t)
+	cluster.assertExactlyOneOwner(t)
+
+	for _, sh := range cluster.sharders {
+		owned := 0
+		for id := uint64(1); id <= testServices; id++ {
+			if sh.owns(id) {
+				owned++
+			}
+		}
+		assert.Positive(t, owned, "replica %s should own some services", sh.id)
+	}
+}
+
+func TestShardingReplicaJoins(t *testing.T) {
+	cluster := setupTestCluster(t, 2)
+	defer cluster.s.Close()
+
+	cluster.converge(t)
+	cluster.assertExactlyOneOwner(t)
+
+	joined := cluster.join("scheduler-new")
+
+	// The first round releases partitions preferred by the new replica,
+	// the second one lets it claim them
+	cluster.round(t)
+	cluster.round(t)
+	cluster.assertExactlyOneOwner(t)
+
+	owned := 0
+	for id := uint64(1); id <= testServices; id++ {
+		if joined.owns(id) {
+			owned++
+		}
+	}
+	assert.Positive(t, owned)
+}
+
+func TestShardingReplicaDies(t *testing.T) {
+	cluster := setupTestCluster(t, 3)
+	defer cluster.s.Close()
+
+	cluster.converge(t)
+	cluster.assertExactlyOneOwner(t)
+
+	cluster.kill("scheduler-1")
+
+	// Leases of the dead replica are still valid, so its services are not checked yet
+	cluster.round(t)
+	orphaned := 0
+	for id := uint64(1); id <= testServices; id++ {
+		if cluster.owners(id) == 0 {
+			orphaned++
+		}
+	}
+	assert.Positive(t, orphaned)
+
+	// Survivors keep renewing every third of the TTL, so failover is bounded by
+	// the lease TTL plus a single renew interval
+	for i := 0; i < 4; i++ {
+		cluster.advance(testLeaseTTL / 3)
+		cluster.round(t)
+	}
+	cluster.assertExactlyOneOwner(t)
+}
+
+func TestShardingStalledReplica(t *testing.T) {
+	cluster := setupTestCluster(t, 2)
+	defer cluster.s.Close()
+
+	cluster.converge(t)
+
+	// A replica that misses its renewals stops trusting its leases on its own
+	stalled := cluster.sharders[0]
+	cluster.advance(testLeaseTTL)
+
+	for id := uint64(1); id <= testServices; id++ {
+		assert.False(t, stalled.owns(id), "stalled replica should not own service %d", id)
+	}
+}
+
+func TestShardingReplicaLeaves(t *testing.T) {
+	cluster := setupTestCluster(t, 2)
+	defer cluster.s.Close()
+
+	cluster.converge(t)
+
+	leaving := cluster.sharders[0]
+	leaving.leave(context.Background())
+	cluster.kill(leaving.id)
+
+	// Released leases can be claimed right away
+	cluster.round(t)
+	cluster.assertExactlyOneOwner(t)
+}
+
+func TestShardAwareScheduler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cluster := setupTestCluster(t, 2)
+	defer cluster.s.Close()
+	cluster.converge(t)
+
+	var schedulers []*scheduler
+	for _, sh := range cluster.sharders {
+		sched, _ := setupTestScheduler()
+		sched.sharder = sh
+		schedulers = append(schedulers, sched)
+
+		for id := uint64(1); id <= testServices; id++ {
+			sched.applyConfiguration(ctx, &rpc.ServiceInfoForScheduler{ServiceId: id, HealthCheckInterval: 3600})
+		}
+	}
+
+	assertTasks := func() {
+		for id := uint64(1); id <= testServices; id++ {
+			running := 0
+			for _, sched := range schedulers {
+				if _, exists := sched.activeTasks[id]; exists {
+					running++
+				}
+			}
+			assert.Equal(t, 1, running, "service %d should run on exactly one scheduler", id)
+		}
+	}
+
+	assertTasks()
+
+	// Moving every partition to the second replica moves the tasks as well
+	cluster.sharders[0].leave(ctx)
+	cluster.kill(cluster.sharders[0].id)
+	cluster.round(t)
+	schedulers[0].rebalanceTasks(ctx)
+	schedulers[1].rebalanceTasks(ctx)
+
+	assert.Empty(t, schedulers[0].activeTasks)
+	assertTasks()
+}
+
+func TestShardLoopWithoutSharder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sched, _ := setupTestScheduler(newService(1, "http://example.com"))
+	assert.True(t, sched.owns(1))
+
+	done := sched.startShardLoop(ctx)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("shard loop did not stop")
+	}
+}
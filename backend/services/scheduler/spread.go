@@ -0,0 +1,135 @@
+package main
+
+import (
+	"expvar"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxJitterPercent keeps jittered ticks in their own interval
+const maxJitterPercent = 50
+
+// tickSchedule spreads the checks of services over their interval instead of firing
+// them all when the scheduler starts. The phase is derived from the service ID, so
+// it stays the same across restarts and when the service moves to another replica.
+type tickSchedule struct {
+	interval time.Duration
+	phase    time.Duration
+	jitter   time.Duration                          // upper bound of the random delay of every tick but the first
+	failing  time.Duration                          // interval while the service is failing, 0 keeps the interval
+	cron     interface{ Next(time.Time) time.Time } // replaces the interval if set
+}
+
+func newTickSchedule(serviceID uint64, interval time.Duration, jitterPercent int) tickSchedule {
+	jitterPercent = min(max(jitterPercent, 0), maxJitterPercent)
+
+	return tickSchedule{
+		interval: interval,
+		phase:    time.Duration(shardHash("phase/"+strconv.FormatUint(serviceID, 10)) % uint64(interval)),
+		jitter:   interval * time.Duration(jitterPercent) / 100,
+	}
+}
+
+// first returns the first tick in the service's phase after now
+func (t tickSchedule) first(now time.Time) time.Time {
+	if t.cron != nil {
+		return t.cron.Next(now)
+	}
+
+	elapsed := time.Duration(now.UnixNano()-int64(t.phase)) % t.interval
+	if elapsed < 0 {
+		elapsed += t.interval
+	}
+	return now.Add(t.interval - elapsed)
+}
+
+// after returns the tick planned after base, skipping those already in the past, and
+// when to fire it. Failing services are checked at the failing interval counted from
+// base, others return to their phase or cron schedule. Jitter never accumulates, as
+// it is applied to the planned tick, and failing or cron scheduled services get none.
+func (t tickSchedule) after(base time.Time, now time.Time, failing bool) (planned time.Time, fireAt time.Time) {
+	if failing && t.failing > 0 {
+		planned = base.Add(t.failing)
+		for !planned.After(now) {
+			planned = planned.Add(t.failing)
+		}
+		return planned, planned
+	}
+
+	if t.cron != nil {
+		planned = t.cron.Next(now)
+		return planned, planned
+	}
+
+	planned = t.first(base)
+	for !planned.After(now) {
+		planned = planned.Add(t.interval)
+	}
+
+	fireAt = planned
+	if t.jitter > 0 {
+		fireAt = fireAt.Add(rand.N(t.jitter))
+	}
+	return planned, fireAt
+}
+
+// publishSpread counts publishes per second over the last minute. A peak to mean
+// ratio close to 1 means checks are evenly spread, bursts show as large ratios.
+type publishSpread struct {
+	mu      sync.Mutex
+	counts  [60]int
+	seconds [60]int64 // the second each count belongs to
+}
+
+type spreadStats struct {
+	Publishes     int     `json:"publishes"` // in the last minute
+	PeakPerSecond int     `json:"peak_per_second"`
+	PeakToMean    float64 `json:"peak_to_mean"`
+}
+
+func (p *publishSpread) record(now time.Time) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	second := now.Unix()
+	bucket := second % int64(len(p.counts))
+	if p.seconds[bucket] != second {
+		p.seconds[bucket] = second
+		p.counts[bucket] = 0
+	}
+	p.counts[bucket]++
+}
+
+func (p *publishSpread) stats(now time.Time) spreadStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var stats spreadStats
+	for i, count := range p.counts {
+		if now.Unix()-p.seconds[i] >= int64(len(p.counts)) {
+			continue
+		}
+
+		stats.Publishes += count
+		stats.PeakPerSecond = max(stats.PeakPerSecond, count)
+	}
+
+	if stats.Publishes > 0 {
+		mean := float64(stats.Publishes) / float64(len(p.counts))
+		stats.PeakToMean = float64(stats.PeakPerSecond) / mean
+	}
+	return stats
+}
+
+// publish exposes the stats next to the liveness probe, see live.StartLiveServer
+func (p *publishSpread) publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return p.stats(time.Now())
+	}))
+}
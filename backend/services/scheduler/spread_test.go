@@ -0,0 +1,149 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"alerting-platform/common/crontab"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTickSchedule(t *testing.T) {
+	interval := 30 * time.Second
+	now := time.Date(2025, 1, 1, 12, 0, 7, 0, time.UTC)
+
+	t.Run("Phase is stable and within the interval", func(t *testing.T) {
+		for id := uint64(1); id <= 100; id++ {
+			schedule := newTickSchedule(id, interval, 0)
+
+			assert.Equal(t, schedule.phase, newTickSchedule(id, interval, 0).phase)
+			assert.GreaterOrEqual(t, schedule.phase, time.Duration(0))
+			assert.Less(t, schedule.phase, interval)
+		}
+	})
+
+	t.Run("Services with the same interval are spread", func(t *testing.T) {
+		seconds := map[int64]int{}
+		for id := uint64(1); id <= 300; id++ {
+			seconds[newTickSchedule(id, interval, 0).first(now).Unix()]++
+		}
+
+		// 300 services over 30 seconds, a burst would put most of them in a few seconds
+		assert.Len(t, seconds, 30)
+		for _, count := range seconds {
+			assert.Less(t, count, 30)
+		}
+	})
+
+	t.Run("First tick is in phase", func(t *testing.T) {
+		schedule := newTickSchedule(42, interval, 0)
+
+		first := schedule.first(now)
+
+		assert.True(t, first.After(now))
+		assert.LessOrEqual(t, first.Sub(now), interval)
+		assert.Zero(t, time.Duration(first.UnixNano()-int64(schedule.phase))%interval)
+	})
+
+	t.Run("Missed ticks are skipped", func(t *testing.T) {
+		schedule := newTickSchedule(42, interval, 0)
+		base := schedule.first(now)
+
+		planned, fireAt := schedule.after(base, base.Add(75*time.Second), false)
+
+		assert.Equal(t, base.Add(90*time.Second), planned)
+		assert.Equal(t, planned, fireAt)
+	})
+
+	t.Run("Failing interval", func(t *testing.T) {
+		schedule := newTickSchedule(42, interval, 50)
+		schedule.failing = 5 * time.Second
+		base := schedule.first(now)
+
+		planned, fireAt := schedule.after(base, base, true)
+		assert.Equal(t, base.Add(5*time.Second), planned)
+		assert.Equal(t, planned, fireAt)
+
+		// Back to the regular interval, in phase again
+		planned, _ = schedule.after(base.Add(5*time.Second), base.Add(5*time.Second), false)
+		assert.Equal(t, base.Add(interval), planned)
+	})
+
+	t.Run("Cron schedule", func(t *testing.T) {
+		schedule := newTickSchedule(42, interval, 50)
+		schedule.failing = 5 * time.Second
+		schedule.cron, _ = crontab.Parse("0 9 * * 1-5", "Europe/Warsaw")
+
+		// Wednesday noon in Warsaw, the next check is on Thursday morning
+		wednesday := time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)
+		thursday := time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+
+		assert.True(t, thursday.Equal(schedule.first(wednesday)))
+
+		planned, fireAt := schedule.after(thursday, thursday, false)
+		assert.True(t, planned.Equal(thursday.Add(24*time.Hour)))
+		assert.Equal(t, planned, fireAt)
+
+		// Failing services are checked again soon, whatever the schedule
+		planned, _ = schedule.after(thursday, thursday, true)
+		assert.Equal(t, thursday.Add(5*time.Second), planned)
+	})
+
+	t.Run("Jitter is bounded and does not accumulate", func(t *testing.T) {
+		schedule := newTickSchedule(42, interval, 90)
+		assert.Equal(t, 15*time.Second, schedule.jitter)
+
+		planned := schedule.first(now)
+		for range 20 {
+			var fireAt time.Time
+			previous := planned
+			planned, fireAt = schedule.after(planned, previous, false)
+
+			assert.Equal(t, previous.Add(interval), planned)
+			assert.False(t, fireAt.Before(planned))
+			assert.Less(t, fireAt.Sub(planned), schedule.jitter)
+		}
+	})
+}
+
+func TestPublishSpread(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Even", func(t *testing.T) {
+		spread := &publishSpread{}
+		for i := range 60 {
+			spread.record(now.Add(time.Duration(i) * time.Second))
+		}
+
+		stats := spread.stats(now.Add(59 * time.Second))
+
+		assert.Equal(t, 60, stats.Publishes)
+		assert.Equal(t, 1, stats.PeakPerSecond)
+		assert.InDelta(t, 1.0, stats.PeakToMean, 0.001)
+	})
+
+	t.Run("Burst", func(t *testing.T) {
+		spread := &publishSpread{}
+		for range 60 {
+			spread.record(now)
+		}
+
+		stats := spread.stats(now.Add(time.Second))
+
+		assert.Equal(t, 60, stats.PeakPerSecond)
+		assert.InDelta(t, 60.0, stats.PeakToMean, 0.001)
+	})
+
+	t.Run("Old publishes are forgotten", func(t *testing.T) {
+		spread := &publishSpread{}
+		spread.record(now)
+
+		assert.Zero(t, spread.stats(now.Add(time.Minute)).Publishes)
+	})
+
+	t.Run("Nil spread", func(t *testing.T) {
+		var spread *publishSpread
+		assert.NotPanics(t, func() { spread.record(now) })
+	})
+}
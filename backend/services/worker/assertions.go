@@ -0,0 +1,115 @@
+package main
+
+import (
+	pubsub_common "alerting-platform/common/pubsub"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Bodies are only read up to this size, assertions see the truncated body
+const maxBodySize = 1 << 20
+
+const (
+	AssertionStatusCode   = "status_code"
+	AssertionBodyContains = "body_contains"
+	AssertionBodyRegex    = "body_regex"
+	AssertionJSONPath     = "json_path"
+)
+
+// AssertionError tells which of the configured assertions rejected the response
+type AssertionError struct {
+	Assertion string
+	Detail    string
+}
+
+func (e *AssertionError) Error() string {
+	return fmt.Sprintf("assertion %s failed: %s", e.Assertion, e.Detail)
+}
+
+func needsBody(task pubsub_common.MonitoringTask) bool {
+	return task.BodyContains != "" || task.BodyRegex != "" || task.JSONPath != ""
+}
+
+// checkAssertions runs the assertions in a fixed order and stops at the first failure
+func checkAssertions(task pubsub_common.MonitoringTask, statusCode int, body []byte) error {
+	if len(task.ExpectedStatusCodes) == 0 {
+		if statusCode < 200 || statusCode >= 300 {
+			return &AssertionError{AssertionStatusCode, fmt.Sprintf("got %d, expected 2xx", statusCode)}
+		}
+	} else if !slices.Contains(task.ExpectedStatusCodes, statusCode) {
+		return &AssertionError{AssertionStatusCode, fmt.Sprintf("got %d, expected one of %v", statusCode, task.ExpectedStatusCodes)}
+	}
+
+	if task.BodyContains != "" && !bytes.Contains(body, []byte(task.BodyContains)) {
+		return &AssertionError{AssertionBodyContains, fmt.Sprintf("body does not contain %q", task.BodyContains)}
+	}
+
+	if task.BodyRegex != "" {
+		re, err := regexp.Compile(task.BodyRegex)
+		if err != nil {
+			return &AssertionError{AssertionBodyRegex, fmt.Sprintf("invalid regex: %v", err)}
+		}
+		if !re.Match(body) {
+			return &AssertionError{AssertionBodyRegex, fmt.Sprintf("body does not match %q", task.BodyRegex)}
+		}
+	}
+
+	if task.JSONPath != "" {
+		value, err := lookupJSONPath(body, task.JSONPath)
+		if err != nil {
+			return &AssertionError{AssertionJSONPath, err.Error()}
+		}
+		if value != task.JSONPathEquals {
+			return &AssertionError{AssertionJSONPath, fmt.Sprintf("%s is %s, expected %s", task.JSONPath, value, task.JSONPathEquals)}
+		}
+	}
+
+	return nil
+}
+
+// lookupJSONPath resolves a dotted path like $.data.items.0.state. Strings are
+// returned as is, any other value in its JSON encoding, so that true or 3 can be
+// compared with the configured value.
+func lookupJSONPath(body []byte, path string) (string, error) {
+	var current any
+	if err := json.Unmarshal(body, &current); err != nil {
+		return "", fmt.Errorf("body is not valid JSON: %v", err)
+	}
+
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path != "" {
+		for _, segment := range strings.Split(path, ".") {
+			switch node := current.(type) {
+			case map[string]any:
+				value, exists := node[segment]
+				if !exists {
+					return "", fmt.Errorf("%s not found", segment)
+				}
+				current = value
+			case []any:
+				index, err := strconv.Atoi(segment)
+				if err != nil || index < 0 || index >= len(node) {
+					return "", fmt.Errorf("index %s out of range", segment)
+				}
+				current = node[index]
+			default:
+				return "", fmt.Errorf("%s not found", segment)
+			}
+		}
+	}
+
+	if value, ok := current.(string); ok {
+		return value, nil
+	}
+
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	pubsub_common "alerting-platform/common/pubsub"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/degraded":
+			w.Write([]byte(`{"status": "degraded", "checks": [{"name": "db", "ok": false}]}`))
+		case "/healthy":
+			w.Write([]byte(`{"status": "ok", "checks": [{"name": "db", "ok": true}]}`))
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/text":
+			w.Write([]byte("version 1.4.2 running"))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		task      pubsub_common.MonitoringTask
+		assertion string // empty when the check should pass
+	}{
+		{
+			name: "Any 2xx by default",
+			task: pubsub_common.MonitoringTask{URL: server.URL + "/no-content"},
+		},
+		{
+			name:      "Non 2xx by default",
+			task:      pubsub_common.MonitoringTask{URL: server.URL + "/unauthorized"},
+			assertion: AssertionStatusCode,
+		},
+		{
+			name: "Expected status code",
+			task: pubsub_common.MonitoringTask{URL: server.URL + "/unauthorized", ExpectedStatusCodes: []int{401, 403}},
+		},
+		{
+			name:      "Unexpected status code",
+			task:      pubsub_common.MonitoringTask{URL: server.URL + "/healthy", ExpectedStatusCodes: []int{204}},
+			assertion: AssertionStatusCode,
+		},
+		{
+			name: "Body contains",
+			task: pubsub_common.MonitoringTask{URL: server.URL + "/text", BodyContains: "running"},
+		},
+		{
+			name:      "Body does not contain",
+			task:      pubsub_common.MonitoringTask{URL: server.URL + "/text", BodyContains: "stopped"},
+			assertion: AssertionBodyContains,
+		},
+		{
+			name: "Body matches regex",
+			task: pubsub_common.MonitoringTask{URL: server.URL + "/text", BodyRegex: `version 1\.\d+\.\d+`},
+		},
+		{
+			name:      "Body does not match regex",
+			task:      pubsub_common.MonitoringTask{URL: server.URL + "/text", BodyRegex: `version 2\.`},
+			assertion: AssertionBodyRegex,
+		},
+		{
+			name: "JSON path equals",
+			task: pubsub_common.MonitoringTask{URL: server.URL + "/healthy", JSONPath: "$.status", JSONPathEquals: "ok"},
+		},
+		{
+			name:      "JSON path differs",
+			task:      pubsub_common.MonitoringTask{URL: server.URL + "/degraded", JSONPath: "$.status", JSONPathEquals: "ok"},
+			assertion: AssertionJSONPath,
+		},
+		{
+			name: "JSON path into array",
+			task: pubsub_common.MonitoringTask{URL: server.URL + "/healthy", JSONPath: "checks.0.ok", JSONPathEquals: "true"},
+		},
+		{
+			name:      "JSON path missing",
+			task:      pubsub_common.MonitoringTask{URL: server.URL + "/healthy", JSONPath: "$.version", JSONPathEquals: "1"},
+			assertion: AssertionJSONPath,
+		},
+		{
+			name:      "JSON path on non JSON body",
+			task:      pubsub_common.MonitoringTask{URL: server.URL + "/text", JSONPath: "$.status", JSONPathEquals: "ok"},
+			assertion: AssertionJSONPath,
+		},
+		{
+			name:      "First failing assertion is reported",
+			task:      pubsub_common.MonitoringTask{URL: server.URL + "/degraded", BodyContains: "degraded", JSONPath: "$.status", JSONPathEquals: "ok"},
+			assertion: AssertionJSONPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := healthError(tt.task)
+
+			if tt.assertion == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var assertionErr *AssertionError
+			if assert.True(t, errors.As(err, &assertionErr), "expected an assertion error, got %v", err) {
+				assert.Equal(t, tt.assertion, assertionErr.Assertion)
+			}
+		})
+	}
+}
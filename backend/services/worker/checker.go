@@ -0,0 +1,244 @@
+package main
+
+import (
+	"alerting-platform/common/config"
+	"alerting-platform/common/egress"
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/secrets"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const checkTimeout = 10 * time.Second
+
+// checkHealth returns nil when the service is up, otherwise the reason it is
+// considered down. The status code and certificate are only set by HTTP checks,
+// the step results by synthetic ones.
+func checkHealth(task pubsub_common.MonitoringTask, result *pubsub_common.CheckResult) error {
+	switch task.CheckType {
+	case pubsub_common.CheckTypeTCP:
+		return checkTCP(task.URL, task.Port, attemptTimeout(task))
+	case pubsub_common.CheckTypeGRPC:
+		return checkGRPC(task)
+	case pubsub_common.CheckTypeDNS:
+		return checkDNS(task)
+	case pubsub_common.CheckTypeSynthetic:
+		return checkSynthetic(task, result)
+	default:
+		return checkHTTP(task, result)
+	}
+}
+
+// attemptTimeout bounds a single attempt of the check. The gRPC specific
+// timeout predates retries and still takes precedence for gRPC checks.
+func attemptTimeout(task pubsub_common.MonitoringTask) time.Duration {
+	if task.CheckType == pubsub_common.CheckTypeGRPC && task.GRPCTimeout > 0 {
+		return time.Duration(task.GRPCTimeout) * time.Second
+	}
+	if task.AttemptTimeout > 0 {
+		return time.Duration(task.AttemptTimeout) * time.Second
+	}
+	return checkTimeout
+}
+
+func checkHTTP(task pubsub_common.MonitoringTask, result *pubsub_common.CheckResult) error {
+	// Clients are cheap, the pooled connections live in the shared transport
+	client := &http.Client{
+		Timeout:   attemptTimeout(task),
+		Transport: httpTransport,
+	}
+
+	req, err := buildRequest(task)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	if resp.TLS != nil {
+		result.Certificate = earliestExpiry(resp.TLS.PeerCertificates)
+	}
+
+	var body []byte
+	if needsBody(task) {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return fmt.Errorf("failed to read body: %w", err)
+		}
+	}
+
+	return checkAssertions(task, resp.StatusCode, body)
+}
+
+// earliestExpiry picks the certificate of the chain that expires first, as an
+// expired intermediate breaks the chain just like an expired leaf
+func earliestExpiry(chain []*x509.Certificate) *pubsub_common.CertificateInfo {
+	var earliest *x509.Certificate
+	for _, cert := range chain {
+		if earliest == nil || cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+
+	if earliest == nil {
+		return nil
+	}
+
+	return &pubsub_common.CertificateInfo{
+		NotAfter: earliest.NotAfter.UTC(),
+		Issuer:   earliest.Issuer.String(),
+		Subject:  earliest.Subject.String(),
+	}
+}
+
+func buildRequest(task pubsub_common.MonitoringTask) (*http.Request, error) {
+	method := task.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if task.RequestBody != "" {
+		body = strings.NewReader(task.RequestBody)
+	}
+
+	req, err := http.NewRequest(method, task.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// The errors deliberately leave out the secrets, they end up in logs and events
+	for name, encrypted := range task.EncryptedHeaders {
+		value, err := secrets.Decrypt(encrypted, config.GetSecretsKey())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt header %s", name)
+		}
+		req.Header.Set(name, value)
+	}
+
+	if task.AuthType == "" {
+		return req, nil
+	}
+
+	secret, err := secrets.Decrypt(task.EncryptedAuthSecret, config.GetSecretsKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt auth secret")
+	}
+
+	switch task.AuthType {
+	case pubsub_common.AuthTypeBasic:
+		req.SetBasicAuth(task.AuthUsername, secret)
+	case pubsub_common.AuthTypeBearer:
+		req.Header.Set("Authorization", "Bearer "+secret)
+	default:
+		return nil, fmt.Errorf("unknown auth type %s", task.AuthType)
+	}
+
+	return req, nil
+}
+
+// checkTCP treats the service as up once the connection is established
+func checkTCP(target string, port int, timeout time.Duration) error {
+	address := net.JoinHostPort(egress.Host(target), strconv.Itoa(port))
+
+	conn, err := dialer(timeout).Dial("tcp", address)
+	if err != nil {
+		return fmt.Errorf("dial failed: %w", err)
+	}
+
+	conn.Close()
+	return nil
+}
+
+// tlsConfig is replaced in tests to trust a self-signed certificate
+var tlsConfig = func() *tls.Config {
+	return &tls.Config{}
+}
+
+// checkGRPC calls the standard grpc.health.v1.Health/Check. An empty service
+// name asks about the overall health of the server.
+func checkGRPC(task pubsub_common.MonitoringTask) error {
+	address := net.JoinHostPort(egress.Host(task.URL), strconv.Itoa(task.Port))
+
+	timeout := attemptTimeout(task)
+
+	creds := insecure.NewCredentials()
+	if task.GRPCUseTLS {
+		creds = credentials.NewTLS(tlsConfig())
+	}
+
+	conn, err := grpc.NewClient(address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return dialer(timeout).DialContext(ctx, "tcp", address)
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: task.GRPCServiceName})
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health status is %s", resp.GetStatus())
+	}
+
+	return nil
+}
+
+// egressPolicy is replaced in tests to reach local test servers
+var egressPolicy = egress.GetPolicy
+
+// dialer refuses forbidden addresses after resolving the name, for every check type
+func dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			return egressPolicy().Control(network, address, conn)
+		},
+	}
+}
+
+// httpTransport is shared by all HTTP checks, so that connections to a host are
+// reused instead of opened for every check
+var httpTransport = newHTTPTransport()
+
+func newHTTPTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig()
+	// A proxy would make the connection on our behalf, out of reach of the egress policy
+	transport.Proxy = nil
+	// The client timeout of each check bounds the dial as well
+	transport.DialContext = dialer(0).DialContext
+	transport.MaxIdleConnsPerHost = 4
+	// Idle connections are closed soon, so a renewed certificate is seen on the next handshake
+	transport.IdleConnTimeout = 30 * time.Second
+	return transport
+}
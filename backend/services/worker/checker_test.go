@@ -0,0 +1,365 @@
+package main
+
+import (
+	"alerting-platform/common/config"
+	"alerting-platform/common/egress"
+	pubsub_common "alerting-platform/common/pubsub"
+	"alerting-platform/common/secrets"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestMain(m *testing.M) {
+	// Test servers listen on loopback, which the default policy refuses
+	policy, err := egress.NewPolicy([]string{"127.0.0.0/8", "::1/128"})
+	if err != nil {
+		panic(err)
+	}
+	egressPolicy = func() *egress.Policy { return policy }
+
+	os.Exit(m.Run())
+}
+
+// healthError drops the result details for tests that only care about the verdict
+func healthError(task pubsub_common.MonitoringTask) error {
+	return checkHealth(task, &pubsub_common.CheckResult{})
+}
+
+func TestCheckHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	assert.NoError(t, healthError(pubsub_common.MonitoringTask{URL: server.URL}))
+	assert.NoError(t, healthError(pubsub_common.MonitoringTask{URL: server.URL, CheckType: pubsub_common.CheckTypeHTTP}))
+	assert.Error(t, healthError(pubsub_common.MonitoringTask{URL: server.URL + "/fail"}))
+}
+
+func TestCheckHTTPRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		username, password, hasBasicAuth := r.BasicAuth()
+		authorized := (hasBasicAuth && username == "monitor" && password == "s3cret") ||
+			r.Header.Get("Authorization") == "Bearer t0ken"
+
+		if r.Method != http.MethodPost || string(body) != `{"ping":true}` || r.Header.Get("X-Source") != "alerting" {
+			w.WriteHeader(http.StatusBadRequest)
+		} else if !authorized {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	encrypt := func(secret string) string {
+		encrypted, err := secrets.Encrypt(secret, config.GetSecretsKey())
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when encrypting a secret", err)
+		}
+		return encrypted
+	}
+
+	task := func(authType, username, encryptedSecret string) pubsub_common.MonitoringTask {
+		return pubsub_common.MonitoringTask{
+			URL:                 server.URL,
+			Method:              http.MethodPost,
+			EncryptedHeaders:    map[string]string{"X-Source": encrypt("alerting")},
+			RequestBody:         `{"ping":true}`,
+			AuthType:            authType,
+			AuthUsername:        username,
+			EncryptedAuthSecret: encryptedSecret,
+		}
+	}
+
+	t.Run("Basic auth", func(t *testing.T) {
+		assert.NoError(t, healthError(task(pubsub_common.AuthTypeBasic, "monitor", encrypt("s3cret"))))
+	})
+
+	t.Run("Bearer auth", func(t *testing.T) {
+		assert.NoError(t, healthError(task(pubsub_common.AuthTypeBearer, "", encrypt("t0ken"))))
+	})
+
+	t.Run("Wrong credentials", func(t *testing.T) {
+		assert.Error(t, healthError(task(pubsub_common.AuthTypeBearer, "", encrypt("wrong"))))
+	})
+
+	t.Run("Missing auth", func(t *testing.T) {
+		assert.Error(t, healthError(task("", "", "")))
+	})
+
+	t.Run("Secret encrypted with another key", func(t *testing.T) {
+		foreign, _ := secrets.Encrypt("t0ken", []byte("another key"))
+
+		err := healthError(task(pubsub_common.AuthTypeBearer, "", foreign))
+		assert.EqualError(t, err, "failed to decrypt auth secret")
+	})
+
+	t.Run("Header encrypted with another key", func(t *testing.T) {
+		foreign, _ := secrets.Encrypt("alerting", []byte("another key"))
+
+		headerTask := task(pubsub_common.AuthTypeBearer, "", encrypt("t0ken"))
+		headerTask.EncryptedHeaders = map[string]string{"X-Source": foreign}
+
+		assert.EqualError(t, healthError(headerTask), "failed to decrypt header X-Source")
+	})
+}
+
+func TestCheckTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a listener", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	t.Run("Open port", func(t *testing.T) {
+		assert.NoError(t, healthError(pubsub_common.MonitoringTask{URL: "tcp://127.0.0.1", Port: port, CheckType: pubsub_common.CheckTypeTCP}))
+	})
+
+	t.Run("Bare host name", func(t *testing.T) {
+		assert.NoError(t, healthError(pubsub_common.MonitoringTask{URL: "127.0.0.1", Port: port, CheckType: pubsub_common.CheckTypeTCP}))
+	})
+
+	t.Run("Closed port", func(t *testing.T) {
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a listener", err)
+		}
+		closedPort := closed.Addr().(*net.TCPAddr).Port
+		closed.Close()
+
+		assert.Error(t, healthError(pubsub_common.MonitoringTask{URL: "tcp://127.0.0.1", Port: closedPort, CheckType: pubsub_common.CheckTypeTCP}))
+	})
+}
+
+// newTestCertificate returns a self-signed certificate for 127.0.0.1 and a pool trusting it
+func newTestCertificate(t *testing.T, notAfter time.Time) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when generating a key", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		Issuer:                pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating a certificate", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when parsing a certificate", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+func startHealthServer(t *testing.T, opts ...grpc.ServerOption) (*health.Server, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a listener", err)
+	}
+
+	healthServer := health.NewServer()
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return healthServer, listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestCheckGRPC(t *testing.T) {
+	healthServer, port := startHealthServer(t)
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("ledger", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	grpcTask := func(serviceName string) pubsub_common.MonitoringTask {
+		return pubsub_common.MonitoringTask{
+			URL:             "grpc://127.0.0.1",
+			Port:            port,
+			CheckType:       pubsub_common.CheckTypeGRPC,
+			GRPCServiceName: serviceName,
+			GRPCTimeout:     2,
+		}
+	}
+
+	t.Run("Server serving", func(t *testing.T) {
+		assert.NoError(t, healthError(grpcTask("")))
+	})
+
+	t.Run("Service serving", func(t *testing.T) {
+		assert.NoError(t, healthError(grpcTask("payments")))
+	})
+
+	t.Run("Service not serving", func(t *testing.T) {
+		assert.Error(t, healthError(grpcTask("ledger")))
+	})
+
+	t.Run("Unknown service", func(t *testing.T) {
+		assert.Error(t, healthError(grpcTask("unknown")))
+	})
+
+	t.Run("Server shutting down", func(t *testing.T) {
+		healthServer.Shutdown()
+		defer healthServer.Resume()
+
+		assert.Error(t, healthError(grpcTask("")))
+	})
+}
+
+func TestCheckHTTPCertificate(t *testing.T) {
+	notAfter := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+	cert, pool := newTestCertificate(t, notAfter)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	server.StartTLS()
+	defer server.Close()
+
+	originalTLSConfig, originalTransport := tlsConfig, httpTransport
+	tlsConfig = func() *tls.Config { return &tls.Config{RootCAs: pool} }
+	httpTransport = newHTTPTransport()
+	defer func() { tlsConfig, httpTransport = originalTLSConfig, originalTransport }()
+
+	t.Run("HTTPS reports the certificate", func(t *testing.T) {
+		var result pubsub_common.CheckResult
+		err := checkHealth(pubsub_common.MonitoringTask{URL: server.URL}, &result)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		if assert.NotNil(t, result.Certificate) {
+			assert.True(t, notAfter.Equal(result.Certificate.NotAfter))
+			assert.Equal(t, "CN=localhost", result.Certificate.Subject)
+			assert.Equal(t, "CN=localhost", result.Certificate.Issuer)
+		}
+	})
+
+	t.Run("Plain HTTP has no certificate", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer plain.Close()
+
+		var result pubsub_common.CheckResult
+		assert.NoError(t, checkHealth(pubsub_common.MonitoringTask{URL: plain.URL}, &result))
+		assert.Nil(t, result.Certificate)
+	})
+}
+
+func TestEarliestExpiry(t *testing.T) {
+	leaf := &x509.Certificate{NotAfter: time.Now().Add(90 * 24 * time.Hour), Subject: pkix.Name{CommonName: "leaf"}}
+	intermediate := &x509.Certificate{NotAfter: time.Now().Add(10 * 24 * time.Hour), Subject: pkix.Name{CommonName: "intermediate"}}
+
+	assert.Equal(t, "CN=intermediate", earliestExpiry([]*x509.Certificate{leaf, intermediate}).Subject)
+	assert.Nil(t, earliestExpiry(nil))
+}
+
+func TestCheckGRPCWithTLS(t *testing.T) {
+	cert, pool := newTestCertificate(t, time.Now().Add(24*time.Hour))
+	_, port := startHealthServer(t, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+
+	originalTLSConfig := tlsConfig
+	tlsConfig = func() *tls.Config { return &tls.Config{RootCAs: pool} }
+	defer func() { tlsConfig = originalTLSConfig }()
+
+	task := pubsub_common.MonitoringTask{
+		URL:         "grpc://127.0.0.1",
+		Port:        port,
+		CheckType:   pubsub_common.CheckTypeGRPC,
+		GRPCTimeout: 2,
+	}
+
+	t.Run("TLS", func(t *testing.T) {
+		task.GRPCUseTLS = true
+		assert.NoError(t, healthError(task))
+	})
+
+	t.Run("Plaintext against TLS server", func(t *testing.T) {
+		task.GRPCUseTLS = false
+		assert.Error(t, healthError(task))
+	})
+}
+
+func TestEgressPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	restricted, err := egress.NewPolicy(nil)
+	assert.NoError(t, err)
+
+	allowLoopback := egressPolicy
+	egressPolicy = func() *egress.Policy { return restricted }
+	defer func() { egressPolicy = allowLoopback }()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	tcpPort, _ := strconv.Atoi(port)
+
+	t.Run("HTTP", func(t *testing.T) {
+		err := healthError(pubsub_common.MonitoringTask{URL: server.URL})
+		assert.ErrorIs(t, err, egress.ErrForbiddenTarget)
+	})
+
+	t.Run("HTTP through a name", func(t *testing.T) {
+		err := healthError(pubsub_common.MonitoringTask{URL: "http://localhost:" + port})
+		assert.ErrorIs(t, err, egress.ErrForbiddenTarget)
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		err := healthError(pubsub_common.MonitoringTask{CheckType: pubsub_common.CheckTypeTCP, URL: "127.0.0.1", Port: tcpPort})
+		assert.ErrorIs(t, err, egress.ErrForbiddenTarget)
+	})
+
+	t.Run("Classified", func(t *testing.T) {
+		result := runCheck(pubsub_common.MonitoringTask{URL: server.URL}, "worker-1")
+		assert.Equal(t, pubsub_common.ErrorTypeForbiddenTarget, result.ErrorType)
+	})
+}
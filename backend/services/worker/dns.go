@@ -0,0 +1,165 @@
+package main
+
+import (
+	"alerting-platform/common/egress"
+	pubsub_common "alerting-platform/common/pubsub"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const AssertionDNSAnswer = "dns_answer"
+
+// checkDNS asks every configured resolver, so a single hijacked or lagging
+// resolver is enough to fail the check. The API requires expected values, tasks
+// without them only count failed resolution and empty answers as down.
+func checkDNS(task pubsub_common.MonitoringTask) error {
+	name := egress.Host(task.URL)
+	recordType := strings.ToUpper(task.DNSRecordType)
+	if recordType == "" {
+		recordType = pubsub_common.DNSRecordA
+	}
+
+	resolvers := task.DNSResolvers
+	if len(resolvers) == 0 {
+		resolvers = []string{""}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), attemptTimeout(task))
+	defer cancel()
+
+	for _, address := range resolvers {
+		// The resolver reports dial errors as text only, so the policy is checked upfront as well
+		if address != "" {
+			if err := egressPolicy().CheckHost(ctx, egress.AddressHost(address)); err != nil {
+				return fmt.Errorf("resolver %s: %w", address, err)
+			}
+		}
+
+		answer, err := lookupRecords(ctx, newResolver(address), recordType, name)
+		if err != nil {
+			return fmt.Errorf("%s lookup of %s failed: %w", recordType, name, err)
+		}
+
+		if len(answer) == 0 {
+			return fmt.Errorf("%s lookup of %s returned no records", recordType, name)
+		}
+
+		if len(task.DNSExpected) > 0 && !sameAnswer(answer, task.DNSExpected) {
+			resolver := address
+			if resolver == "" {
+				resolver = "system resolver"
+			}
+			return &AssertionError{
+				Assertion: AssertionDNSAnswer,
+				Detail:    fmt.Sprintf("%s answered %v, expected %v", resolver, answer, task.DNSExpected),
+			}
+		}
+	}
+
+	return nil
+}
+
+// newResolver sends every query to the given server. The system resolver is
+// used as is, only resolvers chosen by users go through the egress policy.
+func newResolver(address string) *net.Resolver {
+	if address == "" {
+		return net.DefaultResolver
+	}
+
+	address = net.JoinHostPort(egress.AddressHost(address), resolverPort(address))
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer(0).DialContext(ctx, network, address)
+		},
+	}
+}
+
+// Resolvers are given as host:port, the port defaults to 53
+func resolverPort(address string) string {
+	if _, port, err := net.SplitHostPort(address); err == nil {
+		return port
+	}
+	return "53"
+}
+
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	switch recordType {
+	case pubsub_common.DNSRecordA, pubsub_common.DNSRecordAAAA:
+		network := "ip4"
+		if recordType == pubsub_common.DNSRecordAAAA {
+			network = "ip6"
+		}
+
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+
+		answer := make([]string, len(ips))
+		for i, ip := range ips {
+			answer[i] = ip.String()
+		}
+		return answer, nil
+	case pubsub_common.DNSRecordCNAME:
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return []string{cname}, nil
+	case pubsub_common.DNSRecordMX:
+		records, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		answer := make([]string, len(records))
+		for i, record := range records {
+			answer[i] = strconv.Itoa(int(record.Pref)) + " " + record.Host
+		}
+		return answer, nil
+	case pubsub_common.DNSRecordTXT:
+		return resolver.LookupTXT(ctx, name)
+	case pubsub_common.DNSRecordNS:
+		records, err := resolver.LookupNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		answer := make([]string, len(records))
+		for i, record := range records {
+			answer[i] = record.Host
+		}
+		return answer, nil
+	}
+
+	return nil, fmt.Errorf("unsupported record type %s", recordType)
+}
+
+// sameAnswer compares the records as sets, ignoring case, trailing dots and the
+// notation of IPv6 addresses
+func sameAnswer(answer, expected []string) bool {
+	normalize := func(records []string) []string {
+		normalized := make([]string, len(records))
+		for i, record := range records {
+			normalized[i] = normalizeRecord(record)
+		}
+		slices.Sort(normalized)
+		return slices.Compact(normalized)
+	}
+
+	return slices.Equal(normalize(answer), normalize(expected))
+}
+
+func normalizeRecord(record string) string {
+	record = strings.TrimSpace(record)
+	if ip := net.ParseIP(record); ip != nil {
+		return ip.String()
+	}
+	return strings.ToLower(strings.TrimSuffix(record, "."))
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"alerting-platform/common/egress"
+	pubsub_common "alerting-platform/common/pubsub"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+type dnsZone map[string]map[dnsmessage.Type][]dnsmessage.ResourceBody
+
+// startDNSServer answers queries from the zone and returns NXDOMAIN for names
+// outside of it. Like real servers, it answers with the CNAME of aliased names.
+func startDNSServer(t *testing.T, zone dnsZone) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var parser dnsmessage.Parser
+			header, err := parser.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			question, err := parser.Question()
+			if err != nil {
+				continue
+			}
+
+			records, known := zone[strings.TrimSuffix(strings.ToLower(question.Name.String()), ".")]
+			rcode := dnsmessage.RCodeSuccess
+			if !known {
+				rcode = dnsmessage.RCodeNameError
+			}
+
+			builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+				ID:                 header.ID,
+				Response:           true,
+				Authoritative:      true,
+				RecursionAvailable: true,
+				RCode:              rcode,
+			})
+			builder.StartQuestions()
+			builder.Question(question)
+			builder.StartAnswers()
+
+			answer := records[question.Type]
+			if aliases, aliased := records[dnsmessage.TypeCNAME]; aliased {
+				answer = aliases
+			}
+
+			if known {
+				for _, body := range answer {
+					resource := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60}
+					switch record := body.(type) {
+					case *dnsmessage.AResource:
+						builder.AResource(resource, *record)
+					case *dnsmessage.AAAAResource:
+						builder.AAAAResource(resource, *record)
+					case *dnsmessage.CNAMEResource:
+						builder.CNAMEResource(resource, *record)
+					case *dnsmessage.MXResource:
+						builder.MXResource(resource, *record)
+					case *dnsmessage.TXTResource:
+						builder.TXTResource(resource, *record)
+					}
+				}
+			}
+
+			response, err := builder.Finish()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(response, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestCheckDNS(t *testing.T) {
+	name := "shop.example.test"
+	resolver := startDNSServer(t, dnsZone{
+		name: {
+			dnsmessage.TypeA: {
+				&dnsmessage.AResource{A: [4]byte{203, 0, 113, 10}},
+				&dnsmessage.AResource{A: [4]byte{203, 0, 113, 11}},
+			},
+			dnsmessage.TypeMX: {
+				&dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mail.example.test.")},
+			},
+			dnsmessage.TypeTXT: {
+				&dnsmessage.TXTResource{TXT: []string{"v=spf1 -all"}},
+			},
+		},
+		"www.example.test": {
+			dnsmessage.TypeCNAME: {
+				&dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("shops.cdn.example.")},
+			},
+		},
+	})
+
+	dnsTask := func(recordType string, expected ...string) pubsub_common.MonitoringTask {
+		return pubsub_common.MonitoringTask{
+			CheckType:      pubsub_common.CheckTypeDNS,
+			URL:            name,
+			DNSRecordType:  recordType,
+			DNSResolvers:   []string{resolver},
+			DNSExpected:    expected,
+			AttemptTimeout: 2,
+		}
+	}
+
+	withName := func(task pubsub_common.MonitoringTask, name string) pubsub_common.MonitoringTask {
+		task.URL = name
+		return task
+	}
+
+	tests := []struct {
+		name      string
+		task      pubsub_common.MonitoringTask
+		errorType string
+	}{
+		{"A in any order", dnsTask("A", "203.0.113.11", "203.0.113.10"), ""},
+		{"A defaults the record type", dnsTask("", "203.0.113.10", "203.0.113.11"), ""},
+		{"Any answer without expected values", dnsTask("A"), ""},
+		{"A changed", dnsTask("A", "203.0.113.10"), pubsub_common.ErrorTypeAssertion},
+		{"CNAME", withName(dnsTask("CNAME", "shops.cdn.example"), "www.example.test"), ""},
+		{"MX", dnsTask("MX", "10 mail.example.test."), ""},
+		{"TXT", dnsTask("TXT", "v=spf1 -all"), ""},
+		{"TXT changed", dnsTask("TXT", "v=spf1 include:example.com -all"), pubsub_common.ErrorTypeAssertion},
+		{"No records of the type", dnsTask("AAAA"), pubsub_common.ErrorTypeDNS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCheck(tt.task, "worker-1")
+
+			assert.Equal(t, tt.errorType, result.ErrorType, result.Error)
+		})
+	}
+
+	t.Run("Unknown name", func(t *testing.T) {
+		result := runCheck(withName(dnsTask("A"), "gone.example.test"), "worker-1")
+
+		assert.Equal(t, pubsub_common.ErrorTypeDNS, result.ErrorType)
+	})
+
+	t.Run("Every resolver must agree", func(t *testing.T) {
+		hijacked := startDNSServer(t, dnsZone{
+			name: {dnsmessage.TypeA: {&dnsmessage.AResource{A: [4]byte{198, 51, 100, 66}}}},
+		})
+
+		task := dnsTask("A", "203.0.113.10", "203.0.113.11")
+		task.DNSResolvers = append(task.DNSResolvers, hijacked)
+
+		err := healthError(task)
+
+		var assertionErr *AssertionError
+		if assert.ErrorAs(t, err, &assertionErr) {
+			assert.Contains(t, assertionErr.Detail, "198.51.100.66")
+		}
+	})
+
+	t.Run("Resolver refused by the egress policy", func(t *testing.T) {
+		restricted, err := egress.NewPolicy(nil)
+		assert.NoError(t, err)
+
+		allowLoopback := egressPolicy
+		egressPolicy = func() *egress.Policy { return restricted }
+		defer func() { egressPolicy = allowLoopback }()
+
+		assert.ErrorIs(t, healthError(dnsTask("A")), egress.ErrForbiddenTarget)
+	})
+}
+
+func TestSameAnswer(t *testing.T) {
+	assert.True(t, sameAnswer([]string{"2001:db8::1"}, []string{"2001:0db8:0:0:0:0:0:1"}))
+	assert.True(t, sameAnswer([]string{"Mail.Example.com."}, []string{"mail.example.com"}))
+	assert.False(t, sameAnswer([]string{"a.example.com", "b.example.com"}, []string{"a.example.com"}))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limiterSweepInterval is how often limiters of hosts that were not checked lately are dropped
+const limiterSweepInterval = time.Minute
+
+// hostLimiter spaces out checks to the same host, so that many services on one
+// shared host do not hit it all at once
+type hostLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	limit    rate.Limit
+	burst    int
+
+	lastSweep time.Time
+}
+
+// newHostLimiter returns nil when limiting is disabled, which waits for nothing
+func newHostLimiter(perSecond int) *hostLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	return &hostLimiter{
+		limiters: make(map[string]*rate.Limiter),
+		limit:    rate.Limit(perSecond),
+		burst:    perSecond,
+	}
+}
+
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	l.sweep(time.Now())
+
+	limiter, exists := l.limiters[host]
+	if !exists {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[host] = limiter
+	}
+	l.mu.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// sweep drops the limiters with a full bucket, which a new limiter would not limit any
+// differently, so that hosts that are no longer monitored do not pile up.
+// Should be locked before calling
+func (l *hostLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for host, limiter := range l.limiters {
+		if limiter.TokensAt(now) >= float64(l.burst) {
+			delete(l.limiters, host)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostLimiter(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		limiter := newHostLimiter(0)
+
+		assert.Nil(t, limiter)
+		assert.NoError(t, limiter.wait(context.Background(), "example.com"))
+	})
+
+	t.Run("Limits per host", func(t *testing.T) {
+		limiter := newHostLimiter(2)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		// The burst is spent right away, the next check to the host has to wait
+		assert.NoError(t, limiter.wait(ctx, "example.com"))
+		assert.NoError(t, limiter.wait(ctx, "example.com"))
+		assert.Error(t, limiter.wait(ctx, "example.com"))
+
+		// Other hosts have their own budget
+		assert.NoError(t, limiter.wait(ctx, "example.org"))
+	})
+
+	t.Run("Drops idle hosts", func(t *testing.T) {
+		limiter := newHostLimiter(100)
+
+		assert.NoError(t, limiter.wait(context.Background(), "example.com"))
+		assert.Len(t, limiter.limiters, 1)
+
+		// The bucket refills within 10ms, after which the host is idle
+		time.Sleep(20 * time.Millisecond)
+		limiter.lastSweep = time.Time{}
+
+		assert.NoError(t, limiter.wait(context.Background(), "example.org"))
+		assert.Len(t, limiter.limiters, 1)
+		assert.Contains(t, limiter.limiters, "example.org")
+	})
+}
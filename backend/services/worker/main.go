@@ -8,42 +8,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"sync"
 	"time"
 
 	"cloud.google.com/go/pubsub"
 )
 
-func checkHealth(url string) bool {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		log.Printf("Request failed for %s: %v", url, err)
-		return false
-	}
-
-	defer resp.Body.Close()
-
-	return resp.StatusCode >= 200 && resp.StatusCode < 300
-}
-
 func main() {
 	config.Intro("Worker")
+	config.CheckSecretsKey()
 	ctx := context.Background()
 
 	pubsubClient := pubsub_common.Init(ctx)
 	defer pubsubClient.Close()
 
-	log.Println("Worker service is running and connected to Pub/Sub ...")
+	workerID := config.GetInstanceID()
+	location := config.GetConfig().WorkerLocation
+	log.Printf("Worker %s is running in location %s and connected to Pub/Sub ...", workerID, location)
 
-	subName := "worker-execute-health-check"
+	// Workers of a location share one subscription, so each task is checked once per location
+	topic := pubsub_common.ExecuteHealthCheckTopicFor(location)
+	subName := "worker-" + topic
 
 	subscriptions := map[string]string{
-		subName: pubsub_common.ExecuteHealthCheckTopic,
+		subName: topic,
 	}
 
 	pubsub_common.CreateSubscriptionsAndTopics(pubsubClient, subscriptions, nil)
@@ -52,7 +40,9 @@ func main() {
 
 	// this is the limit of simultaneous tasks that the worker
 	// can process:
-	sub.ReceiveSettings.MaxOutstandingMessages = 10
+	cfg := config.GetConfig()
+	sub.ReceiveSettings.MaxOutstandingMessages = cfg.WorkerConcurrency
+	hostLimits = newHostLimiter(cfg.WorkerHostRateLimit)
 
 	log.Printf("Worker listening on subscription %s ...", subName)
 
@@ -67,17 +57,27 @@ func main() {
 			return
 		}
 
-		log.Printf("[Worker] Recived task: Check %s serviceId: %d", task.URL, task.ServiceID)
-
-		isServiceUp := checkHealth(task.URL)
-		resultTopic := pubsub_common.ServiceDownTopic
-		if isServiceUp {
-			resultTopic = pubsub_common.ServiceUpTopic
+		if isStale(task, time.Now()) {
+			log.Printf("[WARNING] Dropping stale task for service %d scheduled at %s", task.ServiceID, task.ScheduledAt.Format(time.RFC3339))
+			msg.Ack()
+			return
 		}
 
+		log.Printf("[Worker] Recived task: Check %s (%s) serviceId: %d", task.URL, task.CheckType, task.ServiceID)
+
+		result := runCheck(task, workerID)
 		payload := pubsub_common.PubSubPayload{
 			ServiceID: task.ServiceID,
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Data: pubsub_common.PubSubPayloadData{
+				Result: &result,
+			},
+		}
+
+		resultTopic := pubsub_common.ServiceUpTopic
+		if result.Error != "" {
+			log.Printf("[Worker] Service %d is down (%s): %s", task.ServiceID, result.ErrorType, result.Error)
+			resultTopic = pubsub_common.ServiceDownTopic
 		}
 
 		err := pubsub_common.SendPayload(ctx, pubsubClient, resultTopic, payload, fmt.Sprintf("%d", task.ServiceID))
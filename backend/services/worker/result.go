@@ -0,0 +1,137 @@
+package main
+
+import (
+	"alerting-platform/common/egress"
+	pubsub_common "alerting-platform/common/pubsub"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sleep is replaced in tests to skip the backoff
+var sleep = time.Sleep
+
+// hostLimits is set up in main, nil does not limit
+var hostLimits *hostLimiter
+
+// runCheck performs the check, retrying it as configured, and describes the
+// outcome of the last attempt for the result event
+func runCheck(task pubsub_common.MonitoringTask, workerID string) pubsub_common.CheckResult {
+	var result pubsub_common.CheckResult
+	backoff := time.Duration(task.RetryBackoff) * time.Millisecond
+
+	for attempt := 1; ; attempt++ {
+		result = pubsub_common.CheckResult{
+			WorkerID: workerID,
+			Location: task.Location,
+			Round:    task.Round,
+			Attempts: attempt,
+		}
+
+		if err := hostLimits.wait(context.Background(), limitedHost(task)); err != nil {
+			log.Printf("[Worker] Rate limiter failed for service %d: %v", task.ServiceID, err)
+		}
+
+		start := time.Now()
+		err := checkHealth(task, &result)
+		result.ResponseTimeMs = float64(time.Since(start).Microseconds()) / 1000
+
+		if err == nil {
+			return result
+		}
+
+		result.ErrorType = classifyError(err)
+		result.Error = err.Error()
+
+		if attempt > task.Retries {
+			return result
+		}
+
+		log.Printf("[Worker] Attempt %d for service %d failed, retrying in %s: %v", attempt, task.ServiceID, backoff, err)
+		sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// limitedHost is the host the rate limit of the task applies to. Synthetic
+// checks count against the host of their first step.
+func limitedHost(task pubsub_common.MonitoringTask) string {
+	if task.CheckType == pubsub_common.CheckTypeSynthetic && len(task.Steps) > 0 {
+		return egress.Host(task.Steps[0].URL)
+	}
+	return egress.Host(task.URL)
+}
+
+// isStale reports whether the task waited so long that the next one is already
+// due. Checking it late would only skew the metrics.
+func isStale(task pubsub_common.MonitoringTask, now time.Time) bool {
+	if task.ScheduledAt.IsZero() || task.HealthCheckInterval <= 0 {
+		return false
+	}
+	return now.Sub(task.ScheduledAt) > time.Duration(task.HealthCheckInterval)*time.Second
+}
+
+func classifyError(err error) string {
+	var assertionErr *AssertionError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var recordHeaderErr tls.RecordHeaderError
+	var certVerificationErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certInvalidErr x509.CertificateInvalidError
+
+	switch {
+	case errors.Is(err, egress.ErrForbiddenTarget):
+		return pubsub_common.ErrorTypeForbiddenTarget
+	case errors.As(err, &assertionErr):
+		return pubsub_common.ErrorTypeAssertion
+	case errors.As(err, &dnsErr):
+		return pubsub_common.ErrorTypeDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return pubsub_common.ErrorTypeConnectionRefused
+	case errors.As(err, &recordHeaderErr), errors.As(err, &certVerificationErr),
+		errors.As(err, &unknownAuthorityErr), errors.As(err, &hostnameErr), errors.As(err, &certInvalidErr):
+		return pubsub_common.ErrorTypeTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return pubsub_common.ErrorTypeTimeout
+	}
+
+	return classifyGRPCError(err)
+}
+
+// gRPC flattens transport errors into the status message, so only the code and
+// the text are left to go by
+func classifyGRPCError(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return pubsub_common.ErrorTypeOther
+	}
+
+	message := st.Message()
+	switch {
+	case st.Code() == codes.DeadlineExceeded:
+		return pubsub_common.ErrorTypeTimeout
+	case strings.Contains(message, egress.ErrForbiddenTarget.Error()):
+		return pubsub_common.ErrorTypeForbiddenTarget
+	case strings.Contains(message, "connection refused"):
+		return pubsub_common.ErrorTypeConnectionRefused
+	case strings.Contains(message, "no such host"):
+		return pubsub_common.ErrorTypeDNS
+	case strings.Contains(message, "tls:"), strings.Contains(message, "x509:"):
+		return pubsub_common.ErrorTypeTLS
+	}
+
+	return pubsub_common.ErrorTypeOther
+}
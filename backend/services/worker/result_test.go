@@ -0,0 +1,209 @@
+package main
+
+import (
+	pubsub_common "alerting-platform/common/pubsub"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("Up", func(t *testing.T) {
+		result := runCheck(pubsub_common.MonitoringTask{URL: server.URL}, "worker-1")
+
+		assert.Empty(t, result.Error)
+		assert.Empty(t, result.ErrorType)
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		assert.Equal(t, "worker-1", result.WorkerID)
+		assert.Positive(t, result.ResponseTimeMs)
+	})
+
+	t.Run("Down", func(t *testing.T) {
+		result := runCheck(pubsub_common.MonitoringTask{URL: server.URL + "/fail"}, "worker-1")
+
+		assert.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+		assert.Equal(t, pubsub_common.ErrorTypeAssertion, result.ErrorType)
+		assert.Contains(t, result.Error, "503")
+	})
+
+	t.Run("Round", func(t *testing.T) {
+		result := runCheck(pubsub_common.MonitoringTask{URL: server.URL, Location: "eu-west", Round: 42}, "worker-1")
+
+		assert.Equal(t, "eu-west", result.Location)
+		assert.Equal(t, int64(42), result.Round)
+	})
+}
+
+func TestClassifyError(t *testing.T) {
+	closedPort := func(t *testing.T) int {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a listener", err)
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+		return port
+	}
+
+	// Accepts connections but never answers, so any protocol on top times out
+	silent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a listener", err)
+	}
+	defer silent.Close()
+	go func() {
+		for {
+			conn, err := silent.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	tests := []struct {
+		name      string
+		task      pubsub_common.MonitoringTask
+		errorType string
+	}{
+		{
+			name:      "DNS",
+			task:      pubsub_common.MonitoringTask{URL: "http://does-not-exist.invalid"},
+			errorType: pubsub_common.ErrorTypeDNS,
+		},
+		{
+			name:      "HTTP connection refused",
+			task:      pubsub_common.MonitoringTask{URL: fmt.Sprintf("http://127.0.0.1:%d", closedPort(t))},
+			errorType: pubsub_common.ErrorTypeConnectionRefused,
+		},
+		{
+			name:      "TCP connection refused",
+			task:      pubsub_common.MonitoringTask{URL: "127.0.0.1", Port: closedPort(t), CheckType: pubsub_common.CheckTypeTCP},
+			errorType: pubsub_common.ErrorTypeConnectionRefused,
+		},
+		{
+			name:      "Untrusted certificate",
+			task:      pubsub_common.MonitoringTask{URL: tlsServer.URL},
+			errorType: pubsub_common.ErrorTypeTLS,
+		},
+		{
+			name: "gRPC timeout",
+			task: pubsub_common.MonitoringTask{
+				URL:         "127.0.0.1",
+				Port:        silent.Addr().(*net.TCPAddr).Port,
+				CheckType:   pubsub_common.CheckTypeGRPC,
+				GRPCTimeout: 1,
+			},
+			errorType: pubsub_common.ErrorTypeTimeout,
+		},
+		{
+			name: "gRPC connection refused",
+			task: pubsub_common.MonitoringTask{
+				URL:         "127.0.0.1",
+				Port:        closedPort(t),
+				CheckType:   pubsub_common.CheckTypeGRPC,
+				GRPCTimeout: 1,
+			},
+			errorType: pubsub_common.ErrorTypeConnectionRefused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCheck(tt.task, "worker-1")
+			assert.Equal(t, tt.errorType, result.ErrorType, result.Error)
+		})
+	}
+}
+
+func TestRunCheckRetries(t *testing.T) {
+	var backoffs []time.Duration
+	sleep = func(d time.Duration) { backoffs = append(backoffs, d) }
+	defer func() { sleep = time.Sleep }()
+
+	// failsFirst answers 503 to the first n requests
+	failsFirst := func(n int32) *httptest.Server {
+		var requests atomic.Int32
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= n {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+	}
+
+	t.Run("Recovers within retries", func(t *testing.T) {
+		backoffs = nil
+		server := failsFirst(2)
+		defer server.Close()
+
+		result := runCheck(pubsub_common.MonitoringTask{URL: server.URL, Retries: 3, RetryBackoff: 100}, "worker-1")
+
+		assert.Empty(t, result.Error)
+		assert.Equal(t, 3, result.Attempts)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, backoffs)
+	})
+
+	t.Run("Down after all retries", func(t *testing.T) {
+		backoffs = nil
+		server := failsFirst(10)
+		defer server.Close()
+
+		result := runCheck(pubsub_common.MonitoringTask{URL: server.URL, Retries: 2}, "worker-1")
+
+		assert.Equal(t, pubsub_common.ErrorTypeAssertion, result.ErrorType)
+		assert.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+		assert.Equal(t, 3, result.Attempts)
+		assert.Len(t, backoffs, 2)
+	})
+
+	t.Run("No retries by default", func(t *testing.T) {
+		backoffs = nil
+		server := failsFirst(1)
+		defer server.Close()
+
+		result := runCheck(pubsub_common.MonitoringTask{URL: server.URL}, "worker-1")
+
+		assert.NotEmpty(t, result.Error)
+		assert.Equal(t, 1, result.Attempts)
+		assert.Empty(t, backoffs)
+	})
+}
+
+func TestAttemptTimeout(t *testing.T) {
+	assert.Equal(t, checkTimeout, attemptTimeout(pubsub_common.MonitoringTask{}))
+	assert.Equal(t, 3*time.Second, attemptTimeout(pubsub_common.MonitoringTask{AttemptTimeout: 3}))
+	assert.Equal(t, 5*time.Second, attemptTimeout(pubsub_common.MonitoringTask{
+		CheckType:      pubsub_common.CheckTypeGRPC,
+		GRPCTimeout:    5,
+		AttemptTimeout: 3,
+	}))
+	assert.Equal(t, 3*time.Second, attemptTimeout(pubsub_common.MonitoringTask{
+		CheckType:      pubsub_common.CheckTypeTCP,
+		GRPCTimeout:    5,
+		AttemptTimeout: 3,
+	}))
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Now()
+
+	assert.False(t, isStale(pubsub_common.MonitoringTask{}, now), "tasks of older schedulers are never stale")
+	assert.False(t, isStale(pubsub_common.MonitoringTask{ScheduledAt: now.Add(-30 * time.Second), HealthCheckInterval: 60}, now))
+	assert.True(t, isStale(pubsub_common.MonitoringTask{ScheduledAt: now.Add(-61 * time.Second), HealthCheckInterval: 60}, now))
+}
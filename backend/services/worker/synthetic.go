@@ -0,0 +1,129 @@
+package main
+
+import (
+	pubsub_common "alerting-platform/common/pubsub"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// checkSynthetic runs the steps in order and stops at the first failing one.
+// The timeout applies to every step on its own.
+func checkSynthetic(task pubsub_common.MonitoringTask, result *pubsub_common.CheckResult) error {
+	if len(task.Steps) == 0 {
+		return fmt.Errorf("synthetic check has no steps")
+	}
+
+	client := &http.Client{
+		Timeout:   attemptTimeout(task),
+		Transport: httpTransport,
+	}
+	variables := map[string]string{}
+
+	for i, step := range task.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+
+		stepResult := pubsub_common.StepResult{Name: name}
+		start := time.Now()
+		err := runStep(client, step, variables, &stepResult)
+		stepResult.ResponseTimeMs = float64(time.Since(start).Microseconds()) / 1000
+
+		result.Steps = append(result.Steps, stepResult)
+		result.StatusCode = stepResult.StatusCode
+
+		if err != nil {
+			result.FailedStep = name
+			return fmt.Errorf("step %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func runStep(client *http.Client, step pubsub_common.SyntheticStep, variables map[string]string, stepResult *pubsub_common.StepResult) error {
+	req, err := buildStepRequest(step, variables)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	stepResult.StatusCode = resp.StatusCode
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return fmt.Errorf("failed to read body: %w", err)
+	}
+
+	// The step assertions are the same as those of a plain HTTP check
+	assertions := pubsub_common.MonitoringTask{
+		ExpectedStatusCodes: step.ExpectedStatusCodes,
+		BodyContains:        step.BodyContains,
+		BodyRegex:           step.BodyRegex,
+		JSONPath:            step.JSONPath,
+		JSONPathEquals:      step.JSONPathEquals,
+	}
+	if err := checkAssertions(assertions, resp.StatusCode, body); err != nil {
+		return err
+	}
+
+	for _, extraction := range step.Extract {
+		value, err := extract(extraction, resp.Header, body)
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", extraction.Name, err)
+		}
+		variables[extraction.Name] = value
+	}
+
+	return nil
+}
+
+func buildStepRequest(step pubsub_common.SyntheticStep, variables map[string]string) (*http.Request, error) {
+	pairs := make([]string, 0, 2*len(variables))
+	for name, value := range variables {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	expand := strings.NewReplacer(pairs...).Replace
+
+	method := step.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if step.Body != "" {
+		body = strings.NewReader(expand(step.Body))
+	}
+
+	req, err := http.NewRequest(method, expand(step.URL), body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	for name, value := range step.Headers {
+		req.Header.Set(name, expand(value))
+	}
+
+	return req, nil
+}
+
+func extract(extraction pubsub_common.VariableExtraction, header http.Header, body []byte) (string, error) {
+	if extraction.JSONPath != "" {
+		return lookupJSONPath(body, extraction.JSONPath)
+	}
+
+	value := header.Get(extraction.Header)
+	if value == "" {
+		return "", fmt.Errorf("header %s not found", extraction.Header)
+	}
+	return value, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	pubsub_common "alerting-platform/common/pubsub"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSynthetic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || string(body) != `{"user":"probe"}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("X-Session", "s-42")
+			w.Write([]byte(`{"data": {"token": "abc"}}`))
+		case "/orders/s-42":
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"count": 2}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	login := pubsub_common.SyntheticStep{
+		Name:   "login",
+		Method: http.MethodPost,
+		URL:    server.URL + "/login",
+		Body:   `{"user":"probe"}`,
+		Extract: []pubsub_common.VariableExtraction{
+			{Name: "token", JSONPath: "$.data.token"},
+			{Name: "session", Header: "X-Session"},
+		},
+	}
+	orders := pubsub_common.SyntheticStep{
+		Name:           "orders",
+		URL:            server.URL + "/orders/{{session}}",
+		Headers:        map[string]string{"Authorization": "Bearer {{token}}"},
+		JSONPath:       "$.count",
+		JSONPathEquals: "2",
+	}
+
+	syntheticTask := func(steps ...pubsub_common.SyntheticStep) pubsub_common.MonitoringTask {
+		return pubsub_common.MonitoringTask{CheckType: pubsub_common.CheckTypeSynthetic, Steps: steps}
+	}
+
+	t.Run("Flow succeeds", func(t *testing.T) {
+		result := runCheck(syntheticTask(login, orders), "worker-1")
+
+		assert.Empty(t, result.Error)
+		assert.Empty(t, result.FailedStep)
+		if assert.Len(t, result.Steps, 2) {
+			assert.Equal(t, "login", result.Steps[0].Name)
+			assert.Equal(t, http.StatusOK, result.Steps[1].StatusCode)
+			assert.Positive(t, result.Steps[1].ResponseTimeMs)
+		}
+	})
+
+	t.Run("Assertion of a step fails", func(t *testing.T) {
+		wrongCount := orders
+		wrongCount.JSONPathEquals = "3"
+
+		result := runCheck(syntheticTask(login, wrongCount), "worker-1")
+
+		assert.Equal(t, "orders", result.FailedStep)
+		assert.Equal(t, pubsub_common.ErrorTypeAssertion, result.ErrorType)
+		assert.Contains(t, result.Error, "step orders")
+		assert.Len(t, result.Steps, 2)
+	})
+
+	t.Run("Later steps are skipped", func(t *testing.T) {
+		badLogin := login
+		badLogin.Body = `{"user":"nobody"}`
+
+		result := runCheck(syntheticTask(badLogin, orders), "worker-1")
+
+		assert.Equal(t, "login", result.FailedStep)
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		assert.Len(t, result.Steps, 1)
+	})
+
+	t.Run("Missing variable", func(t *testing.T) {
+		noToken := login
+		noToken.Extract = []pubsub_common.VariableExtraction{{Name: "token", JSONPath: "$.data.jwt"}}
+
+		err := healthError(syntheticTask(noToken, orders))
+
+		assert.ErrorContains(t, err, "failed to extract token")
+	})
+
+	t.Run("Unnamed steps", func(t *testing.T) {
+		missing := pubsub_common.SyntheticStep{URL: server.URL + "/missing"}
+
+		result := runCheck(syntheticTask(missing), "worker-1")
+
+		assert.Equal(t, "step 1", result.FailedStep)
+	})
+}
@@ -22,11 +22,27 @@ const mapIncidentEventToTimelineElement = (
         description: "",
         color: "bg-yellow-500",
       };
+    case "ACKNOWLEDGED":
+      return {
+        ...commonData,
+        title: "Incident Acknowledged",
+        description: `Acknowledged by ${event.oncaller}`,
+        color: "bg-blue-500",
+      };
+    case "RECOVERED":
+      return {
+        ...commonData,
+        title: "Service Recovered",
+        description: "The service is healthy again.",
+        color: "bg-green-500",
+      };
     case "RESOLVED":
       return {
         ...commonData,
         title: "Incident Resolved",
-        description: `Resolved by ${event.oncaller}`,
+        description: event.oncaller
+          ? `Resolved by ${event.oncaller}`
+          : "Resolved automatically after the service recovered.",
         color: "bg-green-500",
       };
     case "UNRESOLVED":
@@ -66,8 +82,10 @@ const incidentEventCompare = (a: IncidentEvent, b: IncidentEvent): number => {
     START: 0,
     NOTIFIED: 1,
     TIMEOUT: 2,
-    UNRESOLVED: 3,
-    RESOLVED: 3,
+    ACKNOWLEDGED: 3,
+    RECOVERED: 4,
+    UNRESOLVED: 5,
+    RESOLVED: 5,
   };
 
   return typeOrder[a.type] - typeOrder[b.type];
@@ -3,7 +3,9 @@ import { Badge } from "../ui/badge";
 
 const serviceStatusToColor = {
   UP: "#22c55e",
+  DEGRADED: "#f59e0b",
   DOWN: "#ef4444",
+  NO_DATA: "#64748b",
   UNKNOWN: "#888888",
 } as const;
 
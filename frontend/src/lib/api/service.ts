@@ -4,7 +4,7 @@ import { CacheKeys } from "./cache";
 import { toast } from "sonner";
 import { extractErrorMessage, requireNotNullish } from "../utils";
 
-export type ServiceStatus = "UP" | "DOWN" | "UNKNOWN";
+export type ServiceStatus = "UP" | "DEGRADED" | "DOWN" | "NO_DATA" | "UNKNOWN";
 
 export type Seconds = number & { __secondsBrand: never };
 export type Minutes = number & { __minutesBrand: never };
@@ -54,9 +54,18 @@ export type IncidentEvent =
     }
   | {
       timestamp: string;
-      type: "RESOLVED";
+      type: "ACKNOWLEDGED";
       oncaller: string;
     }
+  | {
+      timestamp: string;
+      type: "RECOVERED";
+    }
+  | {
+      timestamp: string;
+      type: "RESOLVED";
+      oncaller?: string; // empty if resolved automatically
+    }
   | {
       timestamp: string;
       type: "UNRESOLVED";
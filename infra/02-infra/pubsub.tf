@@ -3,6 +3,14 @@ resource "google_pubsub_topic" "incident_start" {
   name = "incident-start"
 }
 
+resource "google_pubsub_topic" "incident_acknowledged" {
+  name = "incident-acknowledged"
+}
+
+resource "google_pubsub_topic" "incident_recovered" {
+  name = "incident-recovered"
+}
+
 resource "google_pubsub_topic" "incident_resolved" {
   name = "incident-resolved"
 }
@@ -27,6 +35,13 @@ resource "google_pubsub_topic" "execute_health_check" {
   name = "execute-health-check"
 }
 
+# Workers of the default location listen on execute-health-check itself
+resource "google_pubsub_topic" "execute_health_check_location" {
+  for_each = toset(var.check_locations)
+
+  name = "execute-health-check-${each.value}"
+}
+
 resource "google_pubsub_topic" "service_created" {
   name = "service-created"
 }
@@ -43,10 +58,22 @@ resource "google_pubsub_topic" "notify_oncaller" {
   name = "notify-oncaller"
 }
 
+resource "google_pubsub_topic" "notify_recovery" {
+  name = "notify-recovery"
+}
+
 resource "google_pubsub_topic" "oncaller_acknowledged" {
   name = "oncaller-acknowledged"
 }
 
+resource "google_pubsub_topic" "oncaller_resolved" {
+  name = "oncaller-resolved"
+}
+
+resource "google_pubsub_topic" "monitoring_gap" {
+  name = "monitoring-gap"
+}
+
 # Subscriptions
 resource "google_pubsub_subscription" "logger_incident_start" {
   name  = "logger-incident-start"
@@ -55,6 +82,27 @@ resource "google_pubsub_subscription" "logger_incident_start" {
   enable_message_ordering = true
 }
 
+resource "google_pubsub_subscription" "logger_monitoring_gap" {
+  name  = "logger-monitoring-gap"
+  topic = google_pubsub_topic.monitoring_gap.name
+
+  enable_message_ordering = true
+}
+
+resource "google_pubsub_subscription" "logger_incident_acknowledged" {
+  name  = "logger-incident-acknowledged"
+  topic = google_pubsub_topic.incident_acknowledged.name
+
+  enable_message_ordering = true
+}
+
+resource "google_pubsub_subscription" "logger_incident_recovered" {
+  name  = "logger-incident-recovered"
+  topic = google_pubsub_topic.incident_recovered.name
+
+  enable_message_ordering = true
+}
+
 resource "google_pubsub_subscription" "logger_incident_resolved" {
   name  = "logger-incident-resolved"
   topic = google_pubsub_topic.incident_resolved.name
@@ -139,6 +187,20 @@ resource "google_pubsub_subscription" "incident_manager_oncaller_acknowledged" {
   enable_message_ordering = true
 }
 
+resource "google_pubsub_subscription" "incident_manager_oncaller_resolved" {
+  name  = "incident-manager-oncaller-resolved"
+  topic = google_pubsub_topic.oncaller_resolved.name
+
+  enable_message_ordering = true
+}
+
+resource "google_pubsub_subscription" "incident_manager_monitoring_gap" {
+  name  = "incident-manager-monitoring-gap"
+  topic = google_pubsub_topic.monitoring_gap.name
+
+  enable_message_ordering = true
+}
+
 resource "google_pubsub_subscription" "notifier_notify_oncaller" {
   name  = "notifier-notify-oncaller"
   topic = google_pubsub_topic.notify_oncaller.name
@@ -146,9 +208,60 @@ resource "google_pubsub_subscription" "notifier_notify_oncaller" {
   enable_message_ordering = true
 }
 
+resource "google_pubsub_subscription" "notifier_notify_recovery" {
+  name  = "notifier-notify-recovery"
+  topic = google_pubsub_topic.notify_recovery.name
+
+  enable_message_ordering = true
+}
+
 resource "google_pubsub_subscription" "worker-execute-health-check" {
   name  = "worker-execute-health-check"
   topic = google_pubsub_topic.execute_health_check.name
 
   enable_message_ordering = true
 }
+
+resource "google_pubsub_subscription" "worker_execute_health_check_location" {
+  for_each = google_pubsub_topic.execute_health_check_location
+
+  name  = "worker-${each.value.name}"
+  topic = each.value.name
+
+  enable_message_ordering = true
+}
+
+resource "google_pubsub_subscription" "scheduler_service_created" {
+  name  = "scheduler-service-created"
+  topic = google_pubsub_topic.service_created.name
+
+  enable_message_ordering = true
+}
+
+resource "google_pubsub_subscription" "scheduler_service_modified" {
+  name  = "scheduler-service-modified"
+  topic = google_pubsub_topic.service_modified.name
+
+  enable_message_ordering = true
+}
+
+resource "google_pubsub_subscription" "scheduler_service_removed" {
+  name  = "scheduler-service-removed"
+  topic = google_pubsub_topic.service_removed.name
+
+  enable_message_ordering = true
+}
+
+resource "google_pubsub_subscription" "scheduler_service_up" {
+  name  = "scheduler-service-up"
+  topic = google_pubsub_topic.service_up.name
+
+  enable_message_ordering = true
+}
+
+resource "google_pubsub_subscription" "scheduler_service_down" {
+  name  = "scheduler-service-down"
+  topic = google_pubsub_topic.service_down.name
+
+  enable_message_ordering = true
+}
@@ -20,3 +20,9 @@ variable "firestore_db" {
   type    = string
   default = "logger-db"
 }
+
+# Locations with workers besides the default one, the same as CHECK_LOCATIONS of the services
+variable "check_locations" {
+  type    = list(string)
+  default = []
+}